@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
@@ -35,12 +36,17 @@ type Server struct {
 	WaitTimeout   time.Duration
 	Log           logr.Logger
 	NodeInfoCache cache.NodeInfoCache
+	BlackoutCache cache.BlackoutCache
 	StaticPath    string
 	Namespace     string
 	Elected       <-chan struct{}
 	Client        client.Client
 	counter       int
 	shutdown      chan struct{}
+
+	// enables the slack interaction endpoint, if set
+	SlackSigningSecret string
+	Recorder           events.EventRecorder
 }
 
 func (s *Server) NeedLeaderElection() bool {
@@ -66,20 +72,11 @@ func (s *Server) Start(ctx context.Context) error {
 		s.counter++
 		handler.ServeHTTP(w, r)
 	})
-	mux.HandleFunc("/api/v1/info", func(w http.ResponseWriter, r *http.Request) {
-		elected := false
-		select {
-		case _, ok := <-s.Elected:
-			elected = !ok
-			break
-		default:
-		}
-		if elected {
-			s.serveInfo(w)
-		} else {
-			s.fetchInfo(w)
-		}
-	})
+	mux.HandleFunc("/api/v1/info", s.leaderHandler("/api/v1/info", s.NodeInfoCache.JSON))
+	mux.HandleFunc("/api/v1/blackouts", s.leaderHandler("/api/v1/blackouts", s.BlackoutCache.JSON))
+	if s.SlackSigningSecret != "" {
+		mux.HandleFunc(slackInteractionsPath, s.handleSlackInteraction)
+	}
 	path := s.StaticPath
 	if path == "" {
 		path = "static"
@@ -119,43 +116,61 @@ func (s *Server) Start(ctx context.Context) error {
 	return nil
 }
 
-func (s *Server) writeError(err error, w http.ResponseWriter) {
+// leaderHandler serves the data of the given cache, if elected.
+// Otherwise the request is forwarded to the leading maintenance-controller, which holds the data.
+func (s *Server) leaderHandler(apiPath string, data func() ([]byte, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		elected := false
+		select {
+		case _, ok := <-s.Elected:
+			elected = !ok
+		default:
+		}
+		if elected {
+			s.serveJSON(w, apiPath, data)
+		} else {
+			s.fetchFromLeader(w, apiPath)
+		}
+	}
+}
+
+func (s *Server) writeError(err error, w http.ResponseWriter, apiPath string) {
 	jsonBytes := fmt.Appendf(nil, `{"error":"%s"}`, err.Error())
 	_, err = w.Write(jsonBytes)
 	if err != nil {
-		s.Log.Error(err, "failed to write error reply to /api/v1/info")
+		s.Log.Error(err, "failed to write error reply", "path", apiPath)
 	}
 }
 
-func (s *Server) serveInfo(w http.ResponseWriter) {
-	jsonBytes, err := s.NodeInfoCache.JSON()
+func (s *Server) serveJSON(w http.ResponseWriter, apiPath string, data func() ([]byte, error)) {
+	jsonBytes, err := data()
 	if err != nil {
-		s.writeError(err, w)
+		s.writeError(err, w, apiPath)
 		return
 	}
 	_, err = w.Write(jsonBytes)
 	if err != nil {
-		s.Log.Error(err, "failed to write reply to /api/v1/info")
+		s.Log.Error(err, "failed to write reply", "path", apiPath)
 	}
 }
 
-func (s *Server) fetchInfo(w http.ResponseWriter) {
+func (s *Server) fetchFromLeader(w http.ResponseWriter, apiPath string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 	namespace, err := s.getNamespace()
 	if err != nil {
-		s.writeError(err, w)
+		s.writeError(err, w, apiPath)
 		return
 	}
 	var lease coordinationv1.Lease
 	leaseName := types.NamespacedName{Namespace: namespace, Name: constants.LeaderElectionID}
 	err = s.Client.Get(ctx, leaseName, &lease)
 	if err != nil {
-		s.writeError(err, w)
+		s.writeError(err, w, apiPath)
 		return
 	}
 	if lease.Spec.HolderIdentity == nil {
-		s.writeError(errors.New("no maintenance-controller is leading"), w)
+		s.writeError(errors.New("no maintenance-controller is leading"), w, apiPath)
 		return
 	}
 	holder := *lease.Spec.HolderIdentity
@@ -163,19 +178,19 @@ func (s *Server) fetchInfo(w http.ResponseWriter) {
 	var pod corev1.Pod
 	err = s.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: leader}, &pod)
 	if err != nil {
-		s.writeError(err, w)
+		s.writeError(err, w, apiPath)
 		return
 	}
 	addr := net.JoinHostPort(pod.Status.PodIP, "8080")
-	url := fmt.Sprintf("http://%s/api/v1/info", addr)
+	url := fmt.Sprintf("http://%s%s", addr, apiPath)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
 	if err != nil {
-		s.writeError(err, w)
+		s.writeError(err, w, apiPath)
 		return
 	}
 	res, err := http.DefaultClient.Do(req)
 	if err != nil {
-		s.writeError(err, w)
+		s.writeError(err, w, apiPath)
 		return
 	}
 	defer func() {
@@ -186,7 +201,7 @@ func (s *Server) fetchInfo(w http.ResponseWriter) {
 	}()
 	_, err = io.Copy(w, res.Body)
 	if err != nil {
-		s.writeError(err, w)
+		s.writeError(err, w, apiPath)
 		return
 	}
 }
@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/slack-go/slack"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sapcc/maintenance-controller/common"
+	"github.com/sapcc/maintenance-controller/constants"
+	"github.com/sapcc/maintenance-controller/state"
+)
+
+const (
+	slackInteractionsPath = "/api/v1/slack/interactions"
+	// slack payloads are small, anything larger is not sent by slack
+	maxSlackPayloadBytes = 1 << 20
+)
+
+// errOutdatedApproval is returned for requests of messages, which have been sent for another maintenance
+// or whose profile has moved on from the maintenance-required state.
+var errOutdatedApproval = errors.New("the node does not await this approval anymore")
+
+var approvalReasons = map[common.ApprovalAction]string{
+	common.ApprovalApprove:  "MaintenanceApproved",
+	common.ApprovalDeny:     "MaintenanceDenied",
+	common.ApprovalPostpone: "MaintenancePostponed",
+}
+
+// handleSlackInteraction applies the decisions made using the approval buttons of slackThread messages.
+// Requests are only accepted, if they are signed using the slack signing secret.
+func (s *Server) handleSlackInteraction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	verifier, err := slack.NewSecretsVerifier(r.Header, s.SlackSigningSecret)
+	if err != nil {
+		s.Log.Info("Rejected slack interaction", "reason", err.Error())
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	body, err := io.ReadAll(io.TeeReader(http.MaxBytesReader(w, r.Body, maxSlackPayloadBytes), &verifier))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if err := verifier.Ensure(); err != nil {
+		s.Log.Info("Rejected slack interaction", "reason", err.Error())
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	var callback slack.InteractionCallback
+	if err := json.Unmarshal([]byte(form.Get("payload")), &callback); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	if callback.Type != slack.InteractionTypeBlockActions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	approver := callback.User.Name
+	if approver == "" {
+		approver = callback.User.ID
+	}
+	for _, action := range callback.ActionCallback.BlockActions {
+		if action.BlockID != common.ApprovalBlockID {
+			continue
+		}
+		var request common.ApprovalRequest
+		if err := json.Unmarshal([]byte(action.Value), &request); err != nil {
+			http.Error(w, "invalid approval request", http.StatusBadRequest)
+			return
+		}
+		if err := request.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err := s.applyApproval(r.Context(), &request, approver)
+		if errors.Is(err, errOutdatedApproval) {
+			s.Log.Info("Rejected outdated approval", "node", request.Node, "profile", request.Profile, "by", approver)
+			s.replyEphemeral(r.Context(), &callback, fmt.Sprintf("Node %s profile %s: %s",
+				request.Node, request.Profile, err.Error()))
+			continue
+		}
+		if err != nil {
+			s.Log.Error(err, "failed to apply approval", "node", request.Node, "profile", request.Profile)
+			http.Error(w, "failed to apply approval", http.StatusInternalServerError)
+			return
+		}
+		s.Log.Info("Applied approval", "node", request.Node, "profile", request.Profile,
+			"action", request.Action, "by", approver)
+		s.replaceApprovalMessage(r.Context(), &callback, &request, approver)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) applyApproval(ctx context.Context, request *common.ApprovalRequest, approver string) error {
+	var node corev1.Node
+	if err := s.Client.Get(ctx, types.NamespacedName{Name: request.Node}, &node); err != nil {
+		return fmt.Errorf("failed to get node %s: %w", request.Node, err)
+	}
+	data, err := state.ParseData(node.Annotations[constants.DataAnnotationKey])
+	if err != nil {
+		return err
+	}
+	// the buttons of messages from other maintenances or states must not approve the current one
+	profile, ok := data.Profiles[request.Profile]
+	if !ok || profile == nil || profile.Current != state.Required || !profile.Transition.Equal(request.Transition) {
+		return errOutdatedApproval
+	}
+	unmodified := node.DeepCopy()
+	if err := common.ApplyApproval(&node, request, approver, time.Now()); err != nil {
+		return err
+	}
+	if err := s.Client.Patch(ctx, &node, client.MergeFrom(unmodified)); err != nil {
+		return fmt.Errorf("failed to patch node %s: %w", request.Node, err)
+	}
+	if s.Recorder != nil {
+		s.Recorder.Eventf(&node, nil, corev1.EventTypeNormal, approvalReasons[request.Action], "Approval",
+			"%s using slack", approvalSummary(request, approver))
+	}
+	return nil
+}
+
+// replaceApprovalMessage replaces the buttons of the message with the decision, so it is not made twice.
+// Failures are only logged, as the decision has already been applied.
+func (s *Server) replaceApprovalMessage(ctx context.Context, callback *slack.InteractionCallback,
+	request *common.ApprovalRequest, approver string) {
+
+	if callback.ResponseURL == "" {
+		return
+	}
+	msg := slack.WebhookMessage{
+		Text:            fmt.Sprintf("%s\n_%s_", callback.Message.Text, approvalSummary(request, approver)),
+		ReplaceOriginal: true,
+	}
+	if err := slack.PostWebhookContext(ctx, callback.ResponseURL, &msg); err != nil {
+		s.Log.Error(err, "failed to replace slack approval message", "node", request.Node)
+	}
+}
+
+// replyEphemeral tells the user, who clicked a button, why the click had no effect.
+func (s *Server) replyEphemeral(ctx context.Context, callback *slack.InteractionCallback, text string) {
+	if callback.ResponseURL == "" {
+		return
+	}
+	msg := slack.WebhookMessage{Text: text, ResponseType: slack.ResponseTypeEphemeral}
+	if err := slack.PostWebhookContext(ctx, callback.ResponseURL, &msg); err != nil {
+		s.Log.Error(err, "failed to reply to slack interaction")
+	}
+}
+
+func approvalSummary(request *common.ApprovalRequest, approver string) string {
+	switch request.Action {
+	case common.ApprovalApprove:
+		return fmt.Sprintf("%s approved maintenance of profile %s", approver, request.Profile)
+	case common.ApprovalDeny:
+		return fmt.Sprintf("%s denied maintenance of profile %s", approver, request.Profile)
+	default:
+		return fmt.Sprintf("%s postponed maintenance of profile %s for %s", approver, request.Profile, request.Postpone)
+	}
+}
@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sapcc/maintenance-controller/common"
+	"github.com/sapcc/maintenance-controller/constants"
+)
+
+const signingSecret = "secret"
+
+var transition = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func makeInteraction(request common.ApprovalRequest) string {
+	value, err := json.Marshal(request)
+	Expect(err).To(Succeed())
+	payload, err := json.Marshal(map[string]any{
+		"type": "block_actions",
+		"user": map[string]string{"id": "U123", "name": "alice"},
+		"actions": []map[string]string{
+			{"type": "button", "block_id": common.ApprovalBlockID, "action_id": "approve", "value": string(value)},
+		},
+	})
+	Expect(err).To(Succeed())
+	return url.Values{"payload": []string{string(payload)}}.Encode()
+}
+
+func signedRequest(body string, timestamp time.Time, secret string) *http.Request {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	hash := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(hash, "v0:%s:%s", ts, body)
+	req := httptest.NewRequest(http.MethodPost, slackInteractionsPath, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", ts)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(hash.Sum(nil)))
+	return req
+}
+
+var _ = Describe("The slack interaction endpoint", func() {
+	var server *Server
+	var recorder *events.FakeRecorder
+
+	BeforeEach(func() {
+		node := &corev1.Node{}
+		node.Name = "targetnode"
+		node.Annotations = map[string]string{
+			constants.DataAnnotationKey: `{"Profiles":{"profile":{"Current":"maintenance-required",` +
+				`"Previous":"maintenance-required","Transition":"2026-01-02T03:04:05Z"}},"Notifications":{}}`,
+		}
+		recorder = events.NewFakeRecorder(10)
+		server = &Server{
+			Log:                logr.Discard(),
+			Client:             fake.NewClientBuilder().WithObjects(node).Build(),
+			SlackSigningSecret: signingSecret,
+			Recorder:           recorder,
+		}
+	})
+
+	fetchNode := func() *corev1.Node {
+		var node corev1.Node
+		Expect(server.Client.Get(context.Background(), types.NamespacedName{Name: "targetnode"}, &node)).To(Succeed())
+		return &node
+	}
+
+	It("applies approvals", func() {
+		body := makeInteraction(common.ApprovalRequest{
+			Node: "targetnode", Profile: "profile", Action: common.ApprovalApprove, Label: "approved", Value: "true",
+			Transition: transition,
+		})
+		rsp := httptest.NewRecorder()
+		server.handleSlackInteraction(rsp, signedRequest(body, time.Now(), signingSecret))
+		Expect(rsp.Code).To(Equal(http.StatusOK))
+		node := fetchNode()
+		Expect(node.Labels).To(HaveKeyWithValue("approved", "true"))
+		Expect(common.GetApprovalRecords(node)).To(HaveKeyWithValue("profile", HaveField("By", "alice")))
+		Expect(recorder.Events).To(Receive(ContainSubstring("MaintenanceApproved")))
+	})
+
+	It("rejects invalid signatures", func() {
+		body := makeInteraction(common.ApprovalRequest{
+			Node: "targetnode", Profile: "profile", Action: common.ApprovalApprove, Label: "approved", Value: "true",
+			Transition: transition,
+		})
+		rsp := httptest.NewRecorder()
+		server.handleSlackInteraction(rsp, signedRequest(body, time.Now(), "other"))
+		Expect(rsp.Code).To(Equal(http.StatusUnauthorized))
+		Expect(fetchNode().Labels).ToNot(HaveKey("approved"))
+	})
+
+	It("rejects stale requests", func() {
+		body := makeInteraction(common.ApprovalRequest{
+			Node: "targetnode", Profile: "profile", Action: common.ApprovalApprove, Label: "approved", Value: "true",
+			Transition: transition,
+		})
+		rsp := httptest.NewRecorder()
+		server.handleSlackInteraction(rsp, signedRequest(body, time.Now().Add(-time.Hour), signingSecret))
+		Expect(rsp.Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("refuses to alter keys managed by the controller", func() {
+		body := makeInteraction(common.ApprovalRequest{
+			Node: "targetnode", Profile: "profile", Action: common.ApprovalApprove,
+			Label: constants.StateLabelKey, Value: "in-maintenance", Transition: transition,
+		})
+		rsp := httptest.NewRecorder()
+		server.handleSlackInteraction(rsp, signedRequest(body, time.Now(), signingSecret))
+		Expect(rsp.Code).To(Equal(http.StatusBadRequest))
+		Expect(fetchNode().Labels).ToNot(HaveKey(constants.StateLabelKey))
+	})
+
+	It("rejects approvals of other maintenances", func() {
+		body := makeInteraction(common.ApprovalRequest{
+			Node: "targetnode", Profile: "profile", Action: common.ApprovalApprove, Label: "approved", Value: "true",
+			Transition: transition.Add(-24 * time.Hour),
+		})
+		rsp := httptest.NewRecorder()
+		server.handleSlackInteraction(rsp, signedRequest(body, time.Now(), signingSecret))
+		Expect(rsp.Code).To(Equal(http.StatusOK))
+		Expect(fetchNode().Labels).ToNot(HaveKey("approved"))
+		Expect(recorder.Events).ToNot(Receive())
+	})
+
+	It("rejects approvals of profiles, which do not require maintenance", func() {
+		node := fetchNode()
+		node.Annotations[constants.DataAnnotationKey] = `{"Profiles":{"profile":{"Current":"in-maintenance",` +
+			`"Previous":"in-maintenance","Transition":"2026-01-02T03:04:05Z"}},"Notifications":{}}`
+		Expect(server.Client.Update(context.Background(), node)).To(Succeed())
+		body := makeInteraction(common.ApprovalRequest{
+			Node: "targetnode", Profile: "profile", Action: common.ApprovalApprove, Label: "approved", Value: "true",
+			Transition: transition,
+		})
+		rsp := httptest.NewRecorder()
+		server.handleSlackInteraction(rsp, signedRequest(body, time.Now(), signingSecret))
+		Expect(rsp.Code).To(Equal(http.StatusOK))
+		Expect(fetchNode().Labels).ToNot(HaveKey("approved"))
+	})
+})
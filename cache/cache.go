@@ -10,6 +10,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/sapcc/maintenance-controller/common"
+	"github.com/sapcc/maintenance-controller/plugin"
 	"github.com/sapcc/maintenance-controller/state"
 )
 
@@ -17,6 +19,9 @@ type NodeInfoCache interface {
 	Update(state.NodeInfo)
 	Delete(string)
 	JSON() ([]byte, error)
+	// CheckResults returns the results of the check chains of the given node and profile
+	// from the last reconciliation. The results must not be modified.
+	CheckResults(node, profile string) []plugin.CheckChainResult
 }
 
 func NewNodeInfoCache() NodeInfoCache {
@@ -51,3 +56,58 @@ func (nic *nodeInfoCacheImpl) JSON() ([]byte, error) {
 	defer nic.mutex.Unlock()
 	return json.Marshal(slices.Collect(maps.Values(nic.nodes)))
 }
+
+func (nic *nodeInfoCacheImpl) CheckResults(node, profile string) []plugin.CheckChainResult {
+	nic.mutex.Lock()
+	defer nic.mutex.Unlock()
+	results := make([]plugin.CheckChainResult, 0)
+	for _, profileResult := range nic.nodes[node].Profiles {
+		if profileResult.Name != profile {
+			continue
+		}
+		for _, transition := range profileResult.Applied.Transitions {
+			results = append(results, transition.Chain)
+		}
+	}
+	return results
+}
+
+// BlackoutCache holds the upcoming periods of the shared calendars to be shown on the dashboard.
+type BlackoutCache interface {
+	Update([]common.CalendarPeriod)
+	// Updated returns the time of the last update.
+	Updated() time.Time
+	JSON() ([]byte, error)
+}
+
+func NewBlackoutCache() BlackoutCache {
+	return &blackoutCacheImpl{
+		mutex:   sync.Mutex{},
+		periods: make([]common.CalendarPeriod, 0),
+	}
+}
+
+type blackoutCacheImpl struct {
+	mutex   sync.Mutex
+	periods []common.CalendarPeriod
+	updated time.Time
+}
+
+func (bc *blackoutCacheImpl) Update(periods []common.CalendarPeriod) {
+	bc.mutex.Lock()
+	defer bc.mutex.Unlock()
+	bc.periods = periods
+	bc.updated = time.Now()
+}
+
+func (bc *blackoutCacheImpl) Updated() time.Time {
+	bc.mutex.Lock()
+	defer bc.mutex.Unlock()
+	return bc.updated
+}
+
+func (bc *blackoutCacheImpl) JSON() ([]byte, error) {
+	bc.mutex.Lock()
+	defer bc.mutex.Unlock()
+	return json.Marshal(bc.periods)
+}
@@ -6,10 +6,13 @@ package cache
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/sapcc/maintenance-controller/common"
+	"github.com/sapcc/maintenance-controller/plugin"
 	"github.com/sapcc/maintenance-controller/state"
 )
 
@@ -32,4 +35,34 @@ var _ = Describe("NodeInfoCache", func() {
 		Expect(result).To(HaveLen(2))
 	})
 
+	It("returns the check results of a profile", func() {
+		cache := NewNodeInfoCache()
+		chain := plugin.CheckChainResult{Expression: "check_approval"}
+		cache.Update(state.NodeInfo{Node: "a", Profiles: []state.ProfileResult{
+			{Name: "first", Applied: state.ApplyResult{Transitions: []state.TransitionResult{{Chain: chain}}}},
+			{Name: "second"},
+		}})
+		Expect(cache.CheckResults("a", "first")).To(ConsistOf(chain))
+		Expect(cache.CheckResults("a", "second")).To(BeEmpty())
+		Expect(cache.CheckResults("b", "first")).To(BeEmpty())
+	})
+
+})
+
+var _ = Describe("BlackoutCache", func() {
+
+	It("caches blackout periods", func() {
+		cache := NewBlackoutCache()
+		Expect(cache.Updated().IsZero()).To(BeTrue())
+		start := time.Date(2026, time.October, 3, 0, 0, 0, 0, time.UTC)
+		cache.Update([]common.CalendarPeriod{{Calendar: "holidays", Summary: "Oct 3", Start: start, End: start.Add(24 * time.Hour)}})
+		Expect(cache.Updated().IsZero()).To(BeFalse())
+		jsonStr, err := cache.JSON()
+		Expect(err).To(Succeed())
+		result := make([]common.CalendarPeriod, 0)
+		Expect(json.Unmarshal(jsonStr, &result)).To(Succeed())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Calendar).To(Equal("holidays"))
+	})
+
 })
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/sapcc/maintenance-controller/constants"
+)
+
+// ApprovalAction is the decision made on an approval request.
+type ApprovalAction string
+
+const (
+	// ApprovalApprove sets the approval label or annotation to the approve value.
+	ApprovalApprove ApprovalAction = "approve"
+	// ApprovalDeny sets the approval label or annotation to the deny value.
+	ApprovalDeny ApprovalAction = "deny"
+	// ApprovalPostpone removes the approval label or annotation and defers further requests.
+	ApprovalPostpone ApprovalAction = "postpone"
+)
+
+// ApprovalBlockID identifies the slack block holding the approval buttons.
+const ApprovalBlockID = "maintenance-approval"
+
+// reservedKeyPrefix guards the labels and annotations managed by the controller from being altered by approvals.
+const reservedKeyPrefix = "cloud.sap/maintenance-"
+
+// ApprovalRequest describes the change to apply to a node, if a decision is made.
+// It is carried as JSON by the buttons of interactive slack messages.
+type ApprovalRequest struct {
+	Node    string         `json:"node"`
+	Profile string         `json:"profile"`
+	Action  ApprovalAction `json:"action"`
+	// either Label or Annotation is set
+	Label      string `json:"label,omitempty"`
+	Annotation string `json:"annotation,omitempty"`
+	Value      string `json:"value,omitempty"`
+	// only used by ApprovalPostpone
+	Postpone time.Duration `json:"postpone,omitempty"`
+	// time the profile entered the maintenance-required state, when the request has been sent
+	Transition time.Time `json:"transition"`
+}
+
+// Validate ensures the request is complete and does not target keys managed by the controller.
+func (ar *ApprovalRequest) Validate() error {
+	if ar.Node == "" || ar.Profile == "" {
+		return errors.New("approval request needs a node and a profile")
+	}
+	if ar.Transition.IsZero() {
+		return errors.New("approval request needs the time of the transition it has been sent for")
+	}
+	switch ar.Action {
+	case ApprovalApprove, ApprovalDeny, ApprovalPostpone:
+	default:
+		return fmt.Errorf("approval request has invalid action %s", ar.Action)
+	}
+	if (ar.Label == "") == (ar.Annotation == "") {
+		return errors.New("approval request needs either a label or an annotation")
+	}
+	if strings.HasPrefix(ar.Label+ar.Annotation, reservedKeyPrefix) {
+		return fmt.Errorf("approval request must not alter %s%s, which is managed by the controller", ar.Label, ar.Annotation)
+	}
+	return nil
+}
+
+// ApprovalRecord describes who made which decision on the approval of a profile and when.
+// Records are stored per profile as JSON in the constants.ApprovalsAnnotationKey annotation.
+type ApprovalRecord struct {
+	Action         ApprovalAction `json:"action"`
+	By             string         `json:"by"`
+	Time           time.Time      `json:"time"`
+	PostponedUntil *time.Time     `json:"postponedUntil,omitempty"`
+}
+
+// GetApprovalRecords returns the approval records of the given node keyed by profile.
+// Invalid annotations yield no records.
+func GetApprovalRecords(node *corev1.Node) map[string]ApprovalRecord {
+	records := make(map[string]ApprovalRecord)
+	raw, ok := node.Annotations[constants.ApprovalsAnnotationKey]
+	if !ok {
+		return records
+	}
+	if err := json.Unmarshal([]byte(raw), &records); err != nil {
+		return make(map[string]ApprovalRecord)
+	}
+	return records
+}
+
+// IsPostponed returns whether the approval of the given profile has been postponed beyond the given time.
+func IsPostponed(node *corev1.Node, profile string, now time.Time) bool {
+	record, ok := GetApprovalRecords(node)[profile]
+	return ok && record.PostponedUntil != nil && now.Before(*record.PostponedUntil)
+}
+
+// ApplyApproval alters the label or annotation of the given node in memory according to the request
+// and records the decision. The request is expected to be validated.
+func ApplyApproval(node *corev1.Node, request *ApprovalRequest, by string, now time.Time) error {
+	values := &node.Labels
+	key := request.Label
+	if request.Annotation != "" {
+		values = &node.Annotations
+		key = request.Annotation
+	}
+	if *values == nil {
+		*values = make(map[string]string)
+	}
+	record := ApprovalRecord{Action: request.Action, By: by, Time: now.UTC()}
+	if request.Action == ApprovalPostpone {
+		delete(*values, key)
+		until := record.Time.Add(request.Postpone)
+		record.PostponedUntil = &until
+	} else {
+		(*values)[key] = request.Value
+	}
+	records := GetApprovalRecords(node)
+	records[request.Profile] = record
+	raw, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal approval records: %w", err)
+	}
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+	node.Annotations[constants.ApprovalsAnnotationKey] = string(raw)
+	return nil
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/sapcc/maintenance-controller/constants"
+)
+
+var _ = Describe("Approvals", func() {
+
+	It("rejects incomplete requests and reserved keys", func() {
+		transition := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		request := ApprovalRequest{Node: "node", Profile: "profile", Action: ApprovalApprove, Label: "approved"}
+		Expect(request.Validate()).ToNot(Succeed())
+		request.Transition = transition
+		Expect(request.Validate()).To(Succeed())
+		request.Annotation = "approved"
+		Expect(request.Validate()).ToNot(Succeed())
+		request = ApprovalRequest{Node: "node", Profile: "profile", Action: "maybe", Label: "approved", Transition: transition}
+		Expect(request.Validate()).ToNot(Succeed())
+		request = ApprovalRequest{
+			Node: "node", Profile: "profile", Action: ApprovalDeny, Label: constants.StateLabelKey, Transition: transition,
+		}
+		Expect(request.Validate()).ToNot(Succeed())
+	})
+
+	It("sets the label and records the approver", func() {
+		node := &corev1.Node{}
+		now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		request := ApprovalRequest{
+			Node: "node", Profile: "profile", Action: ApprovalApprove, Label: "approved", Value: "true",
+		}
+		Expect(ApplyApproval(node, &request, "alice", now)).To(Succeed())
+		Expect(node.Labels).To(HaveKeyWithValue("approved", "true"))
+		records := GetApprovalRecords(node)
+		Expect(records).To(HaveKeyWithValue("profile", ApprovalRecord{Action: ApprovalApprove, By: "alice", Time: now}))
+		Expect(IsPostponed(node, "profile", now)).To(BeFalse())
+	})
+
+	It("removes the annotation on postpone", func() {
+		node := &corev1.Node{}
+		node.Annotations = map[string]string{"approved": "true"}
+		now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		request := ApprovalRequest{
+			Node: "node", Profile: "profile", Action: ApprovalPostpone, Annotation: "approved", Postpone: time.Hour,
+		}
+		Expect(ApplyApproval(node, &request, "bob", now)).To(Succeed())
+		Expect(node.Annotations).ToNot(HaveKey("approved"))
+		Expect(IsPostponed(node, "profile", now.Add(59*time.Minute))).To(BeTrue())
+		Expect(IsPostponed(node, "profile", now.Add(61*time.Minute))).To(BeFalse())
+		Expect(IsPostponed(node, "other", now)).To(BeFalse())
+	})
+
+})
@@ -5,6 +5,7 @@ package common
 
 import (
 	"errors"
+	"slices"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -23,7 +24,10 @@ func DefaultKubernetesCacheOpts() cache.Options {
 					}
 					pod.ManagedFields = nil
 					pod.Spec.Containers = nil
-					pod.Spec.Volumes = nil
+					// emptyDir volumes are kept for the drain pod filter
+					pod.Spec.Volumes = slices.DeleteFunc(pod.Spec.Volumes, func(volume corev1.Volume) bool {
+						return volume.EmptyDir == nil
+					})
 					return pod, nil
 				},
 			},
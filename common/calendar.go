@@ -0,0 +1,776 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxOccurrences bounds the evaluation of recurring calendar events.
+const maxOccurrences = 100000
+
+// Calendar holds the events of an iCalendar (RFC 5545) file.
+// Other components like todos or time zone definitions are ignored.
+type Calendar struct {
+	Events []CalendarEvent
+}
+
+// CalendarEvent is a single or recurring event of a Calendar.
+type CalendarEvent struct {
+	Summary  string
+	Start    time.Time
+	Duration time.Duration
+	// nil for non-recurring events
+	Recurrence *Recurrence
+	// start times of occurrences, which are excluded from the recurrence
+	Exclude []time.Time
+}
+
+// Recurrence is the subset of RFC 5545 recurrence rules consisting of FREQ, INTERVAL, COUNT, UNTIL,
+// BYDAY, BYMONTH, BYMONTHDAY and WKST.
+type Recurrence struct {
+	Frequency string
+	Interval  int
+	// zero means unlimited
+	Count int
+	// zero means unlimited
+	Until time.Time
+	ByDay []RecurrenceWeekday
+	// negative days count from the end of the month
+	ByMonthDay []int
+	ByMonth    []time.Month
+	// first day of a week, only relevant for weekly rules with BYDAY
+	WeekStart time.Weekday
+}
+
+// RecurrenceWeekday is a BYDAY value like "TU" for every Tuesday or "-1FR" for the last Friday.
+type RecurrenceWeekday struct {
+	// n-th occurrence of the weekday within the month or year, negative values count from the end,
+	// zero means every occurrence
+	Ordinal int
+	Weekday time.Weekday
+}
+
+var durationRegex = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+var recurrenceWeekdayRegex = regexp.MustCompile(`^([+-]?\d{1,2})?(MO|TU|WE|TH|FR|SA|SU)$`)
+
+var recurrenceWeekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// ParseCalendar parses the events of an iCalendar file.
+// Floating times and dates are interpreted in the given location.
+func ParseCalendar(data string, loc *time.Location) (*Calendar, error) {
+	// unfold lines
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\n ", "")
+	data = strings.ReplaceAll(data, "\n\t", "")
+	var calendar Calendar
+	var event *calendarEventProps
+	depth := 0
+	for line := range strings.SplitSeq(data, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		name, params, value, err := parseContentLine(line)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT") && event == nil:
+			event = &calendarEventProps{}
+			depth = 0
+		case name == "BEGIN" && event != nil:
+			depth++
+		case name == "END" && event != nil && depth > 0:
+			depth--
+		case name == "END" && strings.EqualFold(value, "VEVENT") && event != nil:
+			parsed, err := event.toEvent(loc)
+			if err != nil {
+				return nil, err
+			}
+			calendar.Events = append(calendar.Events, parsed)
+			event = nil
+		case event != nil && depth == 0:
+			event.set(name, params, value)
+		}
+	}
+	if event != nil {
+		return nil, errors.New("calendar contains an unterminated VEVENT")
+	}
+	return &calendar, nil
+}
+
+type calendarProp struct {
+	params map[string]string
+	value  string
+}
+
+// calendarEventProps collects the properties of a VEVENT.
+type calendarEventProps struct {
+	props   map[string]calendarProp
+	exdates []calendarProp
+}
+
+func (cep *calendarEventProps) set(name string, params map[string]string, value string) {
+	if cep.props == nil {
+		cep.props = make(map[string]calendarProp)
+	}
+	prop := calendarProp{params: params, value: value}
+	if name == "EXDATE" {
+		cep.exdates = append(cep.exdates, prop)
+		return
+	}
+	cep.props[name] = prop
+}
+
+func (cep *calendarEventProps) toEvent(loc *time.Location) (CalendarEvent, error) {
+	var event CalendarEvent
+	dtstart, ok := cep.props["DTSTART"]
+	if !ok {
+		return event, errors.New("calendar event without DTSTART")
+	}
+	start, isDate, err := parseCalendarTime(dtstart, loc)
+	if err != nil {
+		return event, err
+	}
+	event.Start = start
+	event.Summary = unescapeCalendarText(cep.props["SUMMARY"].value)
+	if dtend, ok := cep.props["DTEND"]; ok {
+		end, _, err := parseCalendarTime(dtend, loc)
+		if err != nil {
+			return event, err
+		}
+		event.Duration = end.Sub(start)
+	} else if duration, ok := cep.props["DURATION"]; ok {
+		event.Duration, err = parseCalendarDuration(duration.value)
+		if err != nil {
+			return event, err
+		}
+	} else if isDate {
+		event.Duration = 24 * time.Hour
+	}
+	if event.Duration < 0 {
+		return event, fmt.Errorf("calendar event '%s' ends before it starts", event.Summary)
+	}
+	if rrule, ok := cep.props["RRULE"]; ok {
+		event.Recurrence, err = parseRecurrence(rrule.value, loc)
+		if err != nil {
+			return event, fmt.Errorf("calendar event '%s': %w", event.Summary, err)
+		}
+	}
+	for _, exdate := range cep.exdates {
+		for value := range strings.SplitSeq(exdate.value, ",") {
+			exclude, _, err := parseCalendarTime(calendarProp{params: exdate.params, value: value}, loc)
+			if err != nil {
+				return event, err
+			}
+			event.Exclude = append(event.Exclude, exclude)
+		}
+	}
+	return event, nil
+}
+
+// parseContentLine splits a content line into its name, parameters and value.
+func parseContentLine(line string) (string, map[string]string, string, error) {
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		}
+		if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon == -1 {
+		return "", nil, "", fmt.Errorf("invalid calendar content line '%s'", line)
+	}
+	parts := strings.Split(line[:colon], ";")
+	params := make(map[string]string)
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:], nil
+}
+
+// parseCalendarTime parses a DATE or DATE-TIME value and returns whether it was a DATE.
+func parseCalendarTime(prop calendarProp, loc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+	if prop.params["VALUE"] == "DATE" || len(value) == 8 {
+		parsed, err := time.ParseInLocation("20060102", value, loc)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid calendar date '%s': %w", value, err)
+		}
+		return parsed, true, nil
+	}
+	if strings.HasSuffix(value, "Z") {
+		parsed, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid calendar date-time '%s': %w", value, err)
+		}
+		return parsed, false, nil
+	}
+	valueLoc := loc
+	if tzid, ok := prop.params["TZID"]; ok {
+		var err error
+		valueLoc, err = time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown time zone '%s' in calendar: %w", tzid, err)
+		}
+	}
+	parsed, err := time.ParseInLocation("20060102T150405", value, valueLoc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid calendar date-time '%s': %w", value, err)
+	}
+	return parsed, false, nil
+}
+
+func parseCalendarDuration(value string) (time.Duration, error) {
+	match := durationRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, fmt.Errorf("invalid calendar duration '%s'", value)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var duration time.Duration
+	for i, unit := range units {
+		if match[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+2])
+		if err != nil {
+			return 0, fmt.Errorf("invalid calendar duration '%s': %w", value, err)
+		}
+		duration += time.Duration(n) * unit
+	}
+	if match[1] == "-" {
+		duration = -duration
+	}
+	return duration, nil
+}
+
+func parseRecurrence(value string, loc *time.Location) (*Recurrence, error) {
+	recurrence := Recurrence{Interval: 1, WeekStart: time.Monday}
+	for part := range strings.SplitSeq(value, ";") {
+		key, val, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			recurrence.Frequency = strings.ToUpper(val)
+		case "INTERVAL":
+			recurrence.Interval, err = strconv.Atoi(val)
+			if err == nil && recurrence.Interval < 1 {
+				err = errors.New("interval needs to be positive")
+			}
+		case "COUNT":
+			recurrence.Count, err = strconv.Atoi(val)
+		case "UNTIL":
+			recurrence.Until, _, err = parseCalendarTime(calendarProp{value: val}, loc)
+		case "BYDAY":
+			recurrence.ByDay, err = parseRecurrenceWeekdays(val)
+		case "BYMONTHDAY":
+			recurrence.ByMonthDay, err = parseRecurrenceNumbers(val, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseRecurrenceNumbers(val, 12)
+			for _, month := range months {
+				if month < 0 {
+					err = errors.New("months need to be positive")
+				}
+				recurrence.ByMonth = append(recurrence.ByMonth, time.Month(month))
+			}
+		case "WKST":
+			var ok bool
+			recurrence.WeekStart, ok = recurrenceWeekdays[strings.ToUpper(val)]
+			if !ok {
+				err = fmt.Errorf("unknown weekday '%s'", val)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported recurrence rule part '%s'", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid recurrence rule part '%s': %w", part, err)
+		}
+	}
+	switch recurrence.Frequency {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return nil, fmt.Errorf("unsupported recurrence frequency '%s'", recurrence.Frequency)
+	}
+	// RFC 5545 restricts ordinal weekdays to monthly and yearly rules and BYMONTHDAY to non-weekly rules
+	if recurrence.Frequency == "DAILY" || recurrence.Frequency == "WEEKLY" {
+		for _, weekday := range recurrence.ByDay {
+			if weekday.Ordinal != 0 {
+				return nil, fmt.Errorf("ordinal weekdays are not allowed for %s recurrences", recurrence.Frequency)
+			}
+		}
+	}
+	if recurrence.Frequency == "WEEKLY" && len(recurrence.ByMonthDay) > 0 {
+		return nil, errors.New("BYMONTHDAY is not allowed for WEEKLY recurrences")
+	}
+	return &recurrence, nil
+}
+
+// parseRecurrenceWeekdays parses a comma separated list of BYDAY values.
+func parseRecurrenceWeekdays(value string) ([]RecurrenceWeekday, error) {
+	weekdays := make([]RecurrenceWeekday, 0)
+	for item := range strings.SplitSeq(strings.ToUpper(value), ",") {
+		match := recurrenceWeekdayRegex.FindStringSubmatch(item)
+		if match == nil {
+			return nil, fmt.Errorf("invalid weekday '%s'", item)
+		}
+		weekday := RecurrenceWeekday{Weekday: recurrenceWeekdays[match[2]]}
+		if match[1] != "" {
+			ordinal, err := strconv.Atoi(match[1])
+			if err != nil || ordinal == 0 || ordinal < -53 || ordinal > 53 {
+				return nil, fmt.Errorf("invalid weekday ordinal '%s'", match[1])
+			}
+			weekday.Ordinal = ordinal
+		}
+		weekdays = append(weekdays, weekday)
+	}
+	return weekdays, nil
+}
+
+// parseRecurrenceNumbers parses a comma separated list of non-zero numbers within [-limit, limit].
+func parseRecurrenceNumbers(value string, limit int) ([]int, error) {
+	numbers := make([]int, 0)
+	for item := range strings.SplitSeq(value, ",") {
+		number, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, err
+		}
+		if number == 0 || number < -limit || number > limit {
+			return nil, fmt.Errorf("%d is out of range", number)
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, nil
+}
+
+func unescapeCalendarText(text string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(text)
+}
+
+// occurrence returns the start of the i-th recurrence of the event.
+// false is returned, if the recurrence does not exist like the 31st of a month with fewer days.
+func (ce *CalendarEvent) occurrence(i int) (time.Time, bool) {
+	s := ce.Start
+	step := i * ce.Recurrence.Interval
+	years, months, days := 0, 0, 0
+	switch ce.Recurrence.Frequency {
+	case "DAILY":
+		days = step
+	case "WEEKLY":
+		days = 7 * step
+	case "MONTHLY":
+		months = step
+	case "YEARLY":
+		years = step
+	}
+	occurrence := time.Date(s.Year()+years, s.Month()+time.Month(months), s.Day()+days,
+		s.Hour(), s.Minute(), s.Second(), 0, s.Location())
+	// time.Date normalizes the 31st of a shorter month into the next month
+	return occurrence, days != 0 || occurrence.Day() == s.Day()
+}
+
+// period returns the beginning of the i-th day, week, month or year of the recurrence
+// and the sorted starts of the occurrences within it.
+func (ce *CalendarEvent) period(i int) (time.Time, []time.Time) {
+	recurrence := ce.Recurrence
+	if len(recurrence.ByDay) == 0 && len(recurrence.ByMonthDay) == 0 && len(recurrence.ByMonth) == 0 {
+		start, ok := ce.occurrence(i)
+		if !ok {
+			return start, nil
+		}
+		return start, []time.Time{start}
+	}
+	s := ce.Start
+	step := i * recurrence.Interval
+	var begin time.Time
+	days := 1
+	switch recurrence.Frequency {
+	case "DAILY":
+		begin = time.Date(s.Year(), s.Month(), s.Day()+step, 0, 0, 0, 0, s.Location())
+	case "WEEKLY":
+		offset := (int(s.Weekday()) - int(recurrence.WeekStart) + 7) % 7
+		begin = time.Date(s.Year(), s.Month(), s.Day()-offset+7*step, 0, 0, 0, 0, s.Location())
+		days = 7
+	case "MONTHLY":
+		begin = time.Date(s.Year(), s.Month()+time.Month(step), 1, 0, 0, 0, 0, s.Location())
+		days = daysInMonth(begin)
+	case "YEARLY":
+		begin = time.Date(s.Year()+step, time.January, 1, 0, 0, 0, 0, s.Location())
+		days = daysInYear(begin)
+	}
+	starts := make([]time.Time, 0)
+	for offset := range days {
+		start := time.Date(begin.Year(), begin.Month(), begin.Day()+offset,
+			s.Hour(), s.Minute(), s.Second(), 0, s.Location())
+		if recurrence.matches(start, s) {
+			starts = append(starts, start)
+		}
+	}
+	return begin, starts
+}
+
+// matches returns whether the given day satisfies the BYxxx parts of the recurrence.
+// Like in RFC 5545 the day of the first occurrence is used, if a rule does not define any day.
+func (r *Recurrence) matches(day, first time.Time) bool {
+	if len(r.ByMonth) > 0 && !slices.Contains(r.ByMonth, day.Month()) {
+		return false
+	}
+	byDay, byMonthDay := r.ByDay, r.ByMonthDay
+	if len(byDay) == 0 && len(byMonthDay) == 0 {
+		switch r.Frequency {
+		case "WEEKLY":
+			byDay = []RecurrenceWeekday{{Weekday: first.Weekday()}}
+		case "MONTHLY", "YEARLY":
+			byMonthDay = []int{first.Day()}
+		}
+	}
+	if len(byMonthDay) > 0 && !slices.ContainsFunc(byMonthDay, func(monthDay int) bool {
+		return monthDay == day.Day() || monthDay == day.Day()-daysInMonth(day)-1
+	}) {
+		return false
+	}
+	if len(byDay) > 0 && !slices.ContainsFunc(byDay, func(weekday RecurrenceWeekday) bool {
+		return r.matchesWeekday(weekday, day)
+	}) {
+		return false
+	}
+	return true
+}
+
+// matchesWeekday returns whether the day is the given weekday. Ordinals refer to the month
+// for monthly rules and yearly rules with BYMONTH, otherwise to the year.
+func (r *Recurrence) matchesWeekday(weekday RecurrenceWeekday, day time.Time) bool {
+	if day.Weekday() != weekday.Weekday {
+		return false
+	}
+	if weekday.Ordinal == 0 {
+		return true
+	}
+	position, length := day.Day(), daysInMonth(day)
+	if r.Frequency == "YEARLY" && len(r.ByMonth) == 0 {
+		position, length = day.YearDay(), daysInYear(day)
+	}
+	if weekday.Ordinal > 0 {
+		return (position-1)/7+1 == weekday.Ordinal
+	}
+	return (length-position)/7+1 == -weekday.Ordinal
+}
+
+func daysInMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func daysInYear(t time.Time) int {
+	return time.Date(t.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
+
+// firstPeriod returns the index of a period, which begins before any occurrence ending after the given time,
+// so recurrences do not need to be expanded from their start. Recurrences with a count are expanded from
+// their start, as the count refers to all occurrences.
+func (ce *CalendarEvent) firstPeriod(from time.Time) int {
+	recurrence := ce.Recurrence
+	if recurrence.Count > 0 {
+		return 0
+	}
+	// occurrences starting before from minus the duration ended already
+	t := from.Add(-ce.Duration).In(ce.Start.Location())
+	s := ce.Start
+	var periods int
+	switch recurrence.Frequency {
+	case "DAILY":
+		periods = daysBetween(s, t)
+	case "WEEKLY":
+		periods = daysBetween(s, t) / 7
+	case "MONTHLY":
+		periods = (t.Year()-s.Year())*12 + int(t.Month()) - int(s.Month())
+	case "YEARLY":
+		periods = t.Year() - s.Year()
+	}
+	// step back a period, as weeks with BYDAY begin before the weekday of the start
+	return max(0, periods/recurrence.Interval-1)
+}
+
+// daysBetween returns the number of calendar days from a to b, which is not affected by daylight saving time.
+func daysBetween(a, b time.Time) int {
+	from := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// occurrences invokes fn with the start of each occurrence, which ends after from and does not start after until,
+// until fn returns false. Excluded occurrences are skipped.
+// Occurrences ending before from may be passed to fn as well.
+func (ce *CalendarEvent) occurrences(from, until time.Time, fn func(start time.Time) bool) {
+	if ce.Recurrence == nil {
+		if !ce.Start.After(until) {
+			fn(ce.Start)
+		}
+		return
+	}
+	count := 0
+	first := ce.firstPeriod(from)
+	for i := first; i-first < maxOccurrences; i++ {
+		begin, starts := ce.period(i)
+		if begin.After(until) {
+			return
+		}
+		for _, start := range starts {
+			// BYxxx rules may yield days before the first occurrence within its period
+			if start.Before(ce.Start) {
+				continue
+			}
+			count++
+			if ce.Recurrence.Count > 0 && count > ce.Recurrence.Count {
+				return
+			}
+			if !ce.Recurrence.Until.IsZero() && start.After(ce.Recurrence.Until) {
+				return
+			}
+			if start.After(until) {
+				return
+			}
+			if slices.ContainsFunc(ce.Exclude, start.Equal) {
+				continue
+			}
+			if !fn(start) {
+				return
+			}
+		}
+	}
+}
+
+// ActiveAt returns whether any occurrence of the event contains the given time.
+func (ce *CalendarEvent) ActiveAt(t time.Time) bool {
+	active := false
+	ce.occurrences(t, t, func(start time.Time) bool {
+		active = t.Before(start.Add(ce.Duration))
+		return !active
+	})
+	return active
+}
+
+// Periods returns the occurrences of the event, which overlap with the given interval.
+func (ce *CalendarEvent) Periods(from, to time.Time) []CalendarPeriod {
+	periods := make([]CalendarPeriod, 0)
+	ce.occurrences(from, to, func(start time.Time) bool {
+		end := start.Add(ce.Duration)
+		if start.Before(to) && end.After(from) {
+			periods = append(periods, CalendarPeriod{Summary: ce.Summary, Start: start, End: end})
+		}
+		return true
+	})
+	return periods
+}
+
+// EventAt returns the first event, which is active at the given time.
+func (c *Calendar) EventAt(t time.Time) (CalendarEvent, bool) {
+	for _, event := range c.Events {
+		if event.ActiveAt(t) {
+			return event, true
+		}
+	}
+	return CalendarEvent{}, false
+}
+
+// Periods returns the occurrences of all events, which overlap with the given interval, sorted by their start.
+func (c *Calendar) Periods(from, to time.Time) []CalendarPeriod {
+	periods := make([]CalendarPeriod, 0)
+	for _, event := range c.Events {
+		periods = append(periods, event.Periods(from, to)...)
+	}
+	sortPeriods(periods)
+	return periods
+}
+
+// CalendarPeriod is a single occurrence of a calendar event.
+type CalendarPeriod struct {
+	// name of the calendar definition, empty for anonymous calendars
+	Calendar string    `json:"calendar,omitempty"`
+	Summary  string    `json:"summary"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+}
+
+func sortPeriods(periods []CalendarPeriod) {
+	slices.SortStableFunc(periods, func(a, b CalendarPeriod) int {
+		return a.Start.Compare(b.Start)
+	})
+}
+
+// OverlapDuration returns how much of the given interval is covered by the periods.
+// Overlapping periods are only accounted once.
+func OverlapDuration(periods []CalendarPeriod, from, to time.Time) time.Duration {
+	sorted := slices.Clone(periods)
+	sortPeriods(sorted)
+	var total time.Duration
+	// end of the covered time accounted so far
+	covered := from
+	for _, period := range sorted {
+		start := period.Start
+		if start.Before(covered) {
+			start = covered
+		}
+		end := period.End
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+			covered = end
+		}
+	}
+	return total
+}
+
+// ConfigMapKeyRef references a key within a ConfigMap.
+type ConfigMapKeyRef struct {
+	Namespace string `config:"namespace" validate:"required"`
+	Name      string `config:"name" validate:"required"`
+	Key       string `config:"key" validate:"required"`
+}
+
+// CalendarSource defines where an iCalendar file is loaded from.
+// Exactly one of Inline, File and ConfigMap has to be set.
+type CalendarSource struct {
+	Inline    string           `config:"inline"`
+	File      string           `config:"file"`
+	ConfigMap *ConfigMapKeyRef `config:"configMap"`
+}
+
+// Validate is invoked when unpacking configuration.
+func (cs *CalendarSource) Validate() error {
+	count := 0
+	if cs.Inline != "" {
+		count++
+	}
+	if cs.File != "" {
+		count++
+	}
+	if cs.ConfigMap != nil {
+		count++
+	}
+	if count != 1 {
+		return errors.New("a calendar source needs exactly one of inline, file or configMap")
+	}
+	return nil
+}
+
+// calendarCacheTTL is how long calendars loaded from files and ConfigMaps are reused before they are read again.
+const calendarCacheTTL = time.Minute
+
+// calendarCache is shared by all calendar sources, as they are recreated whenever the configuration is loaded.
+var calendarCache = sourceCache{entries: make(map[sourceCacheKey]*cachedCalendar)}
+
+type sourceCacheKey struct {
+	file      string
+	configMap ConfigMapKeyRef
+	location  string
+}
+
+type cachedCalendar struct {
+	calendar *Calendar
+	fetched  time.Time
+	// resource version of the ConfigMap the calendar has been parsed from
+	resourceVersion string
+}
+
+// sourceCache holds parsed calendars of files and ConfigMaps.
+type sourceCache struct {
+	mutex   sync.Mutex
+	entries map[sourceCacheKey]*cachedCalendar
+}
+
+func (sc *sourceCache) get(key sourceCacheKey, now time.Time) (*cachedCalendar, bool) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	cached, ok := sc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	return cached, now.Sub(cached.fetched) < calendarCacheTTL
+}
+
+func (sc *sourceCache) put(key sourceCacheKey, cached *cachedCalendar) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	sc.entries[key] = cached
+}
+
+// Load fetches and parses the calendar. Floating times are interpreted in the given location.
+// Files and ConfigMaps are cached for a minute, so updates are picked up without a restart.
+// The returned calendar may be shared and must not be modified.
+func (cs *CalendarSource) Load(ctx context.Context, k8sClient client.Client, loc *time.Location) (*Calendar, error) {
+	if cs.Inline != "" {
+		return ParseCalendar(cs.Inline, loc)
+	}
+	key := sourceCacheKey{file: cs.File, location: loc.String()}
+	if cs.ConfigMap != nil {
+		key.configMap = *cs.ConfigMap
+	}
+	now := time.Now()
+	cached, fresh := calendarCache.get(key, now)
+	if fresh {
+		return cached.calendar, nil
+	}
+	var data, resourceVersion string
+	switch {
+	case cs.File != "":
+		raw, err := os.ReadFile(cs.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read calendar file: %w", err)
+		}
+		data = string(raw)
+	case cs.ConfigMap != nil:
+		var configMap corev1.ConfigMap
+		ref := cs.ConfigMap
+		err := k8sClient.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &configMap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch calendar ConfigMap %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+		var ok bool
+		data, ok = configMap.Data[ref.Key]
+		if !ok {
+			return nil, fmt.Errorf("calendar ConfigMap %s/%s has no key %s", ref.Namespace, ref.Name, ref.Key)
+		}
+		resourceVersion = configMap.ResourceVersion
+		// an unchanged ConfigMap does not need to be parsed again
+		if cached != nil && resourceVersion != "" && cached.resourceVersion == resourceVersion {
+			calendarCache.put(key, &cachedCalendar{calendar: cached.calendar, fetched: now, resourceVersion: resourceVersion})
+			return cached.calendar, nil
+		}
+	}
+	calendar, err := ParseCalendar(data, loc)
+	if err != nil {
+		return nil, err
+	}
+	calendarCache.put(key, &cachedCalendar{calendar: calendar, fetched: now, resourceVersion: resourceVersion})
+	return calendar, nil
+}
@@ -0,0 +1,256 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const freezeCalendar = `BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+SUMMARY:Change freeze
+DTSTART:20261220T000000Z
+DTEND:20270102T000000Z
+END:VEVENT
+BEGIN:VEVENT
+SUMMARY:Weekly
+  release
+DTSTART;TZID=Europe/Berlin:20261005T100000
+DURATION:PT2H
+RRULE:FREQ=WEEKLY;COUNT=4
+EXDATE;TZID=Europe/Berlin:20261012T100000
+BEGIN:VALARM
+SUMMARY:ignored
+TRIGGER:-PT15M
+END:VALARM
+END:VEVENT
+BEGIN:VEVENT
+SUMMARY:Holiday
+DTSTART;VALUE=DATE:20261003
+RRULE:FREQ=YEARLY
+END:VEVENT
+END:VCALENDAR
+`
+
+var _ = Describe("ParseCalendar", func() {
+
+	var berlin *time.Location
+
+	BeforeEach(func() {
+		var err error
+		berlin, err = time.LoadLocation("Europe/Berlin")
+		Expect(err).To(Succeed())
+	})
+
+	It("should parse events", func() {
+		calendar, err := ParseCalendar(freezeCalendar, time.UTC)
+		Expect(err).To(Succeed())
+		Expect(calendar.Events).To(HaveLen(3))
+		Expect(calendar.Events[0].Summary).To(Equal("Change freeze"))
+		Expect(calendar.Events[0].Duration).To(Equal(13 * 24 * time.Hour))
+		Expect(calendar.Events[1].Summary).To(Equal("Weekly release"))
+		Expect(calendar.Events[1].Duration).To(Equal(2 * time.Hour))
+		Expect(calendar.Events[1].Exclude).To(HaveLen(1))
+		Expect(calendar.Events[2].Duration).To(Equal(24 * time.Hour))
+	})
+
+	It("should find single events", func() {
+		calendar, err := ParseCalendar(freezeCalendar, time.UTC)
+		Expect(err).To(Succeed())
+		event, ok := calendar.EventAt(time.Date(2026, time.December, 24, 18, 0, 0, 0, time.UTC))
+		Expect(ok).To(BeTrue())
+		Expect(event.Summary).To(Equal("Change freeze"))
+		_, ok = calendar.EventAt(time.Date(2027, time.January, 2, 0, 0, 0, 0, time.UTC))
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should evaluate recurrences", func() {
+		calendar, err := ParseCalendar(freezeCalendar, time.UTC)
+		Expect(err).To(Succeed())
+		_, ok := calendar.EventAt(time.Date(2026, time.October, 19, 11, 0, 0, 0, berlin))
+		Expect(ok).To(BeTrue())
+		// excluded
+		_, ok = calendar.EventAt(time.Date(2026, time.October, 12, 11, 0, 0, 0, berlin))
+		Expect(ok).To(BeFalse())
+		// count exceeded
+		_, ok = calendar.EventAt(time.Date(2026, time.November, 2, 11, 0, 0, 0, berlin))
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should interpret dates in the given location", func() {
+		calendar, err := ParseCalendar(freezeCalendar, berlin)
+		Expect(err).To(Succeed())
+		event, ok := calendar.EventAt(time.Date(2027, time.October, 3, 0, 30, 0, 0, berlin))
+		Expect(ok).To(BeTrue())
+		Expect(event.Summary).To(Equal("Holiday"))
+		_, ok = calendar.EventAt(time.Date(2027, time.October, 2, 23, 30, 0, 0, berlin))
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should evaluate weekdays of weekly recurrences", func() {
+		// starts on a Wednesday, so the Monday of the first week is not an occurrence
+		data := "BEGIN:VEVENT\nDTSTART:20261007T100000Z\nDURATION:PT1H\nRRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=3\nEND:VEVENT\n"
+		calendar, err := ParseCalendar(data, time.UTC)
+		Expect(err).To(Succeed())
+		periods := calendar.Periods(time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC))
+		Expect(periods).To(HaveLen(3))
+		Expect(periods[0].Start).To(Equal(time.Date(2026, time.October, 7, 10, 0, 0, 0, time.UTC)))
+		Expect(periods[1].Start).To(Equal(time.Date(2026, time.October, 12, 10, 0, 0, 0, time.UTC)))
+		Expect(periods[2].Start).To(Equal(time.Date(2026, time.October, 14, 10, 0, 0, 0, time.UTC)))
+	})
+
+	It("should evaluate ordinal weekdays of monthly recurrences", func() {
+		data := "BEGIN:VEVENT\nDTSTART:20261013T220000Z\nDURATION:PT4H\nRRULE:FREQ=MONTHLY;BYDAY=2TU,-1FR\nEND:VEVENT\n"
+		calendar, err := ParseCalendar(data, time.UTC)
+		Expect(err).To(Succeed())
+		periods := calendar.Periods(time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, time.December, 1, 0, 0, 0, 0, time.UTC))
+		Expect(periods).To(HaveLen(4))
+		Expect(periods[0].Start).To(Equal(time.Date(2026, time.October, 13, 22, 0, 0, 0, time.UTC)))
+		Expect(periods[1].Start).To(Equal(time.Date(2026, time.October, 30, 22, 0, 0, 0, time.UTC)))
+		Expect(periods[2].Start).To(Equal(time.Date(2026, time.November, 10, 22, 0, 0, 0, time.UTC)))
+		Expect(periods[3].Start).To(Equal(time.Date(2026, time.November, 27, 22, 0, 0, 0, time.UTC)))
+	})
+
+	It("should evaluate months and days of yearly recurrences", func() {
+		data := "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20261224\nRRULE:FREQ=YEARLY;BYMONTH=12;BYMONTHDAY=24,-1\nEND:VEVENT\n"
+		calendar, err := ParseCalendar(data, time.UTC)
+		Expect(err).To(Succeed())
+		_, ok := calendar.EventAt(time.Date(2027, time.December, 24, 12, 0, 0, 0, time.UTC))
+		Expect(ok).To(BeTrue())
+		_, ok = calendar.EventAt(time.Date(2027, time.December, 31, 12, 0, 0, 0, time.UTC))
+		Expect(ok).To(BeTrue())
+		_, ok = calendar.EventAt(time.Date(2027, time.December, 25, 12, 0, 0, 0, time.UTC))
+		Expect(ok).To(BeFalse())
+		_, ok = calendar.EventAt(time.Date(2027, time.November, 24, 12, 0, 0, 0, time.UTC))
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should limit daily recurrences by month", func() {
+		data := "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20260101\nRRULE:FREQ=DAILY;BYMONTH=8\nEND:VEVENT\n"
+		calendar, err := ParseCalendar(data, time.UTC)
+		Expect(err).To(Succeed())
+		_, ok := calendar.EventAt(time.Date(2026, time.August, 15, 12, 0, 0, 0, time.UTC))
+		Expect(ok).To(BeTrue())
+		_, ok = calendar.EventAt(time.Date(2026, time.September, 1, 12, 0, 0, 0, time.UTC))
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should evaluate recurrences started long ago", func() {
+		data := "BEGIN:VEVENT\nDTSTART:19900101T220000Z\nDURATION:PT4H\nRRULE:FREQ=DAILY\nEND:VEVENT\n"
+		calendar, err := ParseCalendar(data, time.UTC)
+		Expect(err).To(Succeed())
+		_, ok := calendar.EventAt(time.Date(2026, time.October, 19, 1, 0, 0, 0, time.UTC))
+		Expect(ok).To(BeTrue())
+		_, ok = calendar.EventAt(time.Date(2026, time.October, 19, 3, 0, 0, 0, time.UTC))
+		Expect(ok).To(BeFalse())
+		periods := calendar.Periods(time.Date(2026, time.October, 19, 1, 0, 0, 0, time.UTC),
+			time.Date(2026, time.October, 20, 0, 0, 0, 0, time.UTC))
+		Expect(periods).To(HaveLen(2))
+		Expect(periods[0].Start).To(Equal(time.Date(2026, time.October, 18, 22, 0, 0, 0, time.UTC)))
+		Expect(periods[1].Start).To(Equal(time.Date(2026, time.October, 19, 22, 0, 0, 0, time.UTC)))
+	})
+
+	It("should reject unsupported recurrence rules", func() {
+		for _, rrule := range []string{"FREQ=MONTHLY;BYSETPOS=-1", "FREQ=WEEKLY;BYDAY=2MO", "FREQ=WEEKLY;BYMONTHDAY=1",
+			"FREQ=MONTHLY;BYMONTHDAY=32", "FREQ=YEARLY;BYMONTH=13", "FREQ=MONTHLY;BYDAY=XX"} {
+			data := "BEGIN:VEVENT\nDTSTART:20261005T100000Z\nRRULE:" + rrule + "\nEND:VEVENT\n"
+			_, err := ParseCalendar(data, time.UTC)
+			Expect(err).To(HaveOccurred(), rrule)
+		}
+	})
+
+	It("should reject events without start", func() {
+		_, err := ParseCalendar("BEGIN:VEVENT\nSUMMARY:abc\nEND:VEVENT\n", time.UTC)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject unterminated events", func() {
+		_, err := ParseCalendar("BEGIN:VEVENT\nDTSTART:20261005T100000Z\n", time.UTC)
+		Expect(err).To(HaveOccurred())
+	})
+
+})
+
+var _ = Describe("CalendarSource", func() {
+
+	It("should require exactly one source", func() {
+		source := CalendarSource{}
+		Expect(source.Validate()).ToNot(Succeed())
+		source = CalendarSource{Inline: freezeCalendar, File: "/calendar.ics"}
+		Expect(source.Validate()).ToNot(Succeed())
+		source = CalendarSource{Inline: freezeCalendar}
+		Expect(source.Validate()).To(Succeed())
+	})
+
+	It("should load from a file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "calendar.ics")
+		Expect(os.WriteFile(path, []byte(freezeCalendar), 0600)).To(Succeed())
+		source := CalendarSource{File: path}
+		calendar, err := source.Load(context.Background(), nil, time.UTC)
+		Expect(err).To(Succeed())
+		Expect(calendar.Events).To(HaveLen(3))
+	})
+
+	It("should load from a ConfigMap", func() {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "calendar"},
+			Data:       map[string]string{"freeze.ics": freezeCalendar},
+		}
+		k8sClient := fake.NewClientBuilder().WithObjects(configMap).Build()
+		source := CalendarSource{ConfigMap: &ConfigMapKeyRef{Namespace: "default", Name: "calendar", Key: "freeze.ics"}}
+		calendar, err := source.Load(context.Background(), k8sClient, time.UTC)
+		Expect(err).To(Succeed())
+		Expect(calendar.Events).To(HaveLen(3))
+		source.ConfigMap.Key = "missing"
+		_, err = source.Load(context.Background(), k8sClient, time.UTC)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should cache ConfigMaps", func() {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cached-calendar"},
+			Data:       map[string]string{"freeze.ics": freezeCalendar},
+		}
+		gets := 0
+		k8sClient := fake.NewClientBuilder().WithObjects(configMap).WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				gets++
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).Build()
+		source := CalendarSource{ConfigMap: &ConfigMapKeyRef{Namespace: "default", Name: "cached-calendar", Key: "freeze.ics"}}
+		first, err := source.Load(context.Background(), k8sClient, time.UTC)
+		Expect(err).To(Succeed())
+		configMap.Data["freeze.ics"] = "BEGIN:VCALENDAR\nEND:VCALENDAR\n"
+		Expect(k8sClient.Update(context.Background(), configMap)).To(Succeed())
+		second, err := source.Load(context.Background(), k8sClient, time.UTC)
+		Expect(err).To(Succeed())
+		Expect(second).To(BeIdenticalTo(first))
+		Expect(gets).To(Equal(1))
+
+		// expire the cached calendar
+		for _, cached := range calendarCache.entries {
+			cached.fetched = cached.fetched.Add(-calendarCacheTTL)
+		}
+		third, err := source.Load(context.Background(), k8sClient, time.UTC)
+		Expect(err).To(Succeed())
+		Expect(third.Events).To(BeEmpty())
+		Expect(gets).To(Equal(2))
+	})
+
+})
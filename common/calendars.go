@@ -0,0 +1,221 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	calendarDateFormat     = "2006-01-02"
+	calendarDateTimeFormat = "2006-01-02 15:04"
+	calendarHolidayFormat  = "Jan 2"
+	// first year of recurring holidays, a leap year, so that Feb 29 is valid
+	calendarHolidayYear = 2000
+)
+
+// CalendarRange is a blackout period of a CalendarDefinition.
+type CalendarRange struct {
+	Summary string `config:"summary"`
+	// either a date, which includes the whole day, or a date with a time in "2006-01-02 15:04" format
+	Start string `config:"start" validate:"required"`
+	// either a date, which includes the whole day, or a date with a time in "2006-01-02 15:04" format
+	End string `config:"end" validate:"required"`
+}
+
+// CalendarDefinition describes a named calendar, which can be referenced by plugins and notification schedules.
+type CalendarDefinition struct {
+	Name string `config:"name" validate:"required"`
+	// IANA time zone the ranges, holidays and floating iCalendar times are interpreted in, defaults to UTC
+	Timezone string          `config:"timezone"`
+	Ranges   []CalendarRange `config:"ranges"`
+	// recurring whole days in "Jan 2" format
+	Holidays []string `config:"holidays"`
+	// optional iCalendar data, which is merged with the ranges and holidays
+	Source *CalendarSource `config:"source"`
+}
+
+// namedCalendar is a CalendarDefinition with its static events already parsed.
+type namedCalendar struct {
+	static   Calendar
+	location *time.Location
+	source   *CalendarSource
+	mutex    sync.Mutex
+	// the source is loaded at most once during the lifetime of a Calendars instance
+	loaded *Calendar
+}
+
+// Calendars holds named calendars shared across plugin instances.
+// A nil *Calendars is valid and contains no calendars.
+type Calendars struct {
+	calendars map[string]*namedCalendar
+}
+
+// NewCalendars parses the given calendar definitions.
+func NewCalendars(definitions []CalendarDefinition) (*Calendars, error) {
+	calendars := Calendars{calendars: make(map[string]*namedCalendar)}
+	for _, definition := range definitions {
+		if _, ok := calendars.calendars[definition.Name]; ok {
+			return nil, fmt.Errorf("calendar %s is defined multiple times", definition.Name)
+		}
+		named, err := parseCalendarDefinition(definition)
+		if err != nil {
+			return nil, fmt.Errorf("invalid calendar %s: %w", definition.Name, err)
+		}
+		calendars.calendars[definition.Name] = named
+	}
+	return &calendars, nil
+}
+
+func parseCalendarDefinition(definition CalendarDefinition) (*namedCalendar, error) {
+	location, err := LoadTimezone(definition.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	named := namedCalendar{location: location, source: definition.Source}
+	for _, calendarRange := range definition.Ranges {
+		start, err := parseRangeBoundary(calendarRange.Start, location, false)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseRangeBoundary(calendarRange.End, location, true)
+		if err != nil {
+			return nil, err
+		}
+		if !end.After(start) {
+			return nil, fmt.Errorf("range %s to %s ends before it starts", calendarRange.Start, calendarRange.End)
+		}
+		named.static.Events = append(named.static.Events, CalendarEvent{
+			Summary:  calendarRange.Summary,
+			Start:    start,
+			Duration: end.Sub(start),
+		})
+	}
+	for _, holiday := range definition.Holidays {
+		dayMonth, err := time.Parse(calendarHolidayFormat, holiday)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday %s: %w", holiday, err)
+		}
+		named.static.Events = append(named.static.Events, CalendarEvent{
+			Summary:    holiday,
+			Start:      time.Date(calendarHolidayYear, dayMonth.Month(), dayMonth.Day(), 0, 0, 0, 0, location),
+			Duration:   24 * time.Hour,
+			Recurrence: &Recurrence{Frequency: "YEARLY", Interval: 1},
+		})
+	}
+	if definition.Source != nil && definition.Source.Inline != "" {
+		// fail early on invalid inline calendars
+		if _, err := ParseCalendar(definition.Source.Inline, location); err != nil {
+			return nil, err
+		}
+	}
+	return &named, nil
+}
+
+// parseRangeBoundary parses a date or a date with time.
+// A date as end of a range includes the whole day.
+func parseRangeBoundary(value string, location *time.Location, isEnd bool) (time.Time, error) {
+	if date, err := time.ParseInLocation(calendarDateFormat, value, location); err == nil {
+		if isEnd {
+			return date.AddDate(0, 0, 1), nil
+		}
+		return date, nil
+	}
+	dateTime, err := time.ParseInLocation(calendarDateTimeFormat, value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid range boundary %s, expected %s or %s format",
+			value, calendarDateFormat, calendarDateTimeFormat)
+	}
+	return dateTime, nil
+}
+
+// Has returns whether a calendar with the given name is defined.
+func (c *Calendars) Has(name string) bool {
+	if c == nil {
+		return false
+	}
+	_, ok := c.calendars[name]
+	return ok
+}
+
+// Names returns the sorted names of all defined calendars.
+func (c *Calendars) Names() []string {
+	names := make([]string, 0)
+	if c == nil {
+		return names
+	}
+	for name := range c.calendars {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Get returns the events of the named calendar. A configured source is loaded on first use.
+func (c *Calendars) Get(ctx context.Context, k8sClient client.Client, name string) (*Calendar, error) {
+	if !c.Has(name) {
+		return nil, fmt.Errorf("calendar %s is not defined", name)
+	}
+	named := c.calendars[name]
+	if named.source == nil {
+		return &named.static, nil
+	}
+	named.mutex.Lock()
+	defer named.mutex.Unlock()
+	if named.loaded == nil {
+		loaded, err := named.source.Load(ctx, k8sClient, named.location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load calendar %s: %w", name, err)
+		}
+		merged := Calendar{Events: slices.Concat(named.static.Events, loaded.Events)}
+		named.loaded = &merged
+	}
+	return named.loaded, nil
+}
+
+// ActiveAt returns the first period of the named calendars, which contains the given time.
+func (c *Calendars) ActiveAt(ctx context.Context, k8sClient client.Client, names []string,
+	t time.Time) (CalendarPeriod, bool, error) {
+
+	for _, name := range names {
+		calendar, err := c.Get(ctx, k8sClient, name)
+		if err != nil {
+			return CalendarPeriod{}, false, err
+		}
+		periods := calendar.Periods(t, t.Add(time.Nanosecond))
+		if len(periods) > 0 {
+			periods[0].Calendar = name
+			return periods[0], true, nil
+		}
+	}
+	return CalendarPeriod{}, false, nil
+}
+
+// Periods returns the periods of the named calendars, which overlap with the given interval, sorted by their start.
+func (c *Calendars) Periods(ctx context.Context, k8sClient client.Client, names []string,
+	from, to time.Time) ([]CalendarPeriod, error) {
+
+	periods := make([]CalendarPeriod, 0)
+	var errs []error
+	for _, name := range names {
+		calendar, err := c.Get(ctx, k8sClient, name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, period := range calendar.Periods(from, to) {
+			period.Calendar = name
+			periods = append(periods, period)
+		}
+	}
+	sortPeriods(periods)
+	return periods, errors.Join(errs...)
+}
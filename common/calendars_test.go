@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Calendars", func() {
+
+	var berlin *time.Location
+
+	BeforeEach(func() {
+		var err error
+		berlin, err = time.LoadLocation("Europe/Berlin")
+		Expect(err).To(Succeed())
+	})
+
+	It("should parse ranges and holidays in the time zone of the calendar", func() {
+		calendars, err := NewCalendars([]CalendarDefinition{{
+			Name:     "holidays",
+			Timezone: "Europe/Berlin",
+			Ranges: []CalendarRange{
+				{Summary: "Freeze", Start: "2026-12-18", End: "2027-01-04"},
+				{Summary: "Move", Start: "2026-11-07 18:00", End: "2026-11-08 06:00"},
+			},
+			Holidays: []string{"Oct 3"},
+		}})
+		Expect(err).To(Succeed())
+		Expect(calendars.Names()).To(Equal([]string{"holidays"}))
+		names := []string{"holidays"}
+		period, ok, err := calendars.ActiveAt(context.Background(), nil, names,
+			time.Date(2027, time.January, 4, 23, 0, 0, 0, berlin))
+		Expect(err).To(Succeed())
+		Expect(ok).To(BeTrue())
+		Expect(period.Calendar).To(Equal("holidays"))
+		Expect(period.Summary).To(Equal("Freeze"))
+		_, ok, err = calendars.ActiveAt(context.Background(), nil, names, time.Date(2027, time.January, 5, 0, 0, 0, 0, berlin))
+		Expect(err).To(Succeed())
+		Expect(ok).To(BeFalse())
+		_, ok, err = calendars.ActiveAt(context.Background(), nil, names, time.Date(2026, time.November, 8, 5, 0, 0, 0, berlin))
+		Expect(err).To(Succeed())
+		Expect(ok).To(BeTrue())
+		period, ok, err = calendars.ActiveAt(context.Background(), nil, names, time.Date(2030, time.October, 3, 12, 0, 0, 0, berlin))
+		Expect(err).To(Succeed())
+		Expect(ok).To(BeTrue())
+		Expect(period.Summary).To(Equal("Oct 3"))
+	})
+
+	It("should list periods within an interval", func() {
+		calendars, err := NewCalendars([]CalendarDefinition{
+			{Name: "a", Holidays: []string{"Oct 3", "Dec 24"}},
+			{Name: "b", Ranges: []CalendarRange{{Start: "2026-10-20", End: "2026-10-21"}}},
+		})
+		Expect(err).To(Succeed())
+		from := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+		periods, err := calendars.Periods(context.Background(), nil, calendars.Names(), from, from.AddDate(0, 1, 0))
+		Expect(err).To(Succeed())
+		Expect(periods).To(HaveLen(2))
+		Expect(periods[0].Calendar).To(Equal("a"))
+		Expect(periods[0].Start).To(Equal(time.Date(2026, time.October, 3, 0, 0, 0, 0, time.UTC)))
+		Expect(periods[1].Calendar).To(Equal("b"))
+		Expect(periods[1].End).To(Equal(time.Date(2026, time.October, 22, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("should merge a ConfigMap source", func() {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "calendar"},
+			Data:       map[string]string{"freeze.ics": freezeCalendar},
+		}
+		k8sClient := fake.NewClientBuilder().WithObjects(configMap).Build()
+		calendars, err := NewCalendars([]CalendarDefinition{{
+			Name:     "merged",
+			Holidays: []string{"Oct 3"},
+			Source:   &CalendarSource{ConfigMap: &ConfigMapKeyRef{Namespace: "default", Name: "calendar", Key: "freeze.ics"}},
+		}})
+		Expect(err).To(Succeed())
+		calendar, err := calendars.Get(context.Background(), k8sClient, "merged")
+		Expect(err).To(Succeed())
+		Expect(calendar.Events).To(HaveLen(4))
+	})
+
+	It("should fail on undefined calendars", func() {
+		calendars, err := NewCalendars(nil)
+		Expect(err).To(Succeed())
+		_, _, err = calendars.ActiveAt(context.Background(), nil, []string{"missing"}, time.Now())
+		Expect(err).To(HaveOccurred())
+		var nilCalendars *Calendars
+		Expect(nilCalendars.Has("missing")).To(BeFalse())
+		_, ok, err := nilCalendars.ActiveAt(context.Background(), nil, nil, time.Now())
+		Expect(err).To(Succeed())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should reject invalid definitions", func() {
+		_, err := NewCalendars([]CalendarDefinition{{Name: "a"}, {Name: "a"}})
+		Expect(err).To(HaveOccurred())
+		_, err = NewCalendars([]CalendarDefinition{{Name: "a", Timezone: "Mars/Olympus"}})
+		Expect(err).To(HaveOccurred())
+		_, err = NewCalendars([]CalendarDefinition{{Name: "a", Holidays: []string{"Smarch 3"}}})
+		Expect(err).To(HaveOccurred())
+		_, err = NewCalendars([]CalendarDefinition{{Name: "a", Ranges: []CalendarRange{{Start: "2026-10-20", End: "2026-10-19"}}}})
+		Expect(err).To(HaveOccurred())
+	})
+
+})
+
+var _ = Describe("OverlapDuration", func() {
+
+	It("should account overlapping periods once", func() {
+		from := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+		periods := []CalendarPeriod{
+			{Start: from.Add(2 * time.Hour), End: from.Add(4 * time.Hour)},
+			{Start: from.Add(-time.Hour), End: from.Add(time.Hour)},
+			{Start: from.Add(3 * time.Hour), End: from.Add(5 * time.Hour)},
+			{Start: from.Add(9 * time.Hour), End: from.Add(12 * time.Hour)},
+		}
+		Expect(OverlapDuration(periods, from, from.Add(10*time.Hour))).To(Equal(5 * time.Hour))
+	})
+
+})
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sapcc/maintenance-controller/constants"
+)
+
+// CordonRecord describes who cordoned a node, why and when.
+// It is stored as JSON in the constants.CordonAnnotationKey annotation.
+type CordonRecord struct {
+	By     string    `json:"by"`
+	Reason string    `json:"reason"`
+	Time   time.Time `json:"time"`
+}
+
+// GetCordonRecord returns the cordon record of the given node, if there is a valid one.
+func GetCordonRecord(node *corev1.Node) (CordonRecord, bool) {
+	var record CordonRecord
+	raw, ok := node.Annotations[constants.CordonAnnotationKey]
+	if !ok {
+		return record, false
+	}
+	if err := json.Unmarshal([]byte(raw), &record); err != nil {
+		return record, false
+	}
+	return record, true
+}
+
+// Cordon marks the given node unschedulable in memory.
+// If the node is schedulable, the cordon is recorded to be owned by the controller.
+// Cordons of nodes, which are already unschedulable, are not taken over.
+func Cordon(node *corev1.Node, reason string) error {
+	if node.Spec.Unschedulable {
+		return nil
+	}
+	raw, err := json.Marshal(CordonRecord{By: constants.CordonOwner, Reason: reason, Time: time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cordon record: %w", err)
+	}
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+	node.Annotations[constants.CordonAnnotationKey] = string(raw)
+	node.Spec.Unschedulable = true
+	return nil
+}
+
+// Uncordon marks the given node schedulable in memory, if the controller owns its cordon.
+// If the cordon is owned by someone else, the node is left untouched and the reason is returned.
+func Uncordon(node *corev1.Node) (bool, string) {
+	if !node.Spec.Unschedulable {
+		delete(node.Annotations, constants.CordonAnnotationKey)
+		return true, ""
+	}
+	record, ok := GetCordonRecord(node)
+	if !ok {
+		return false, "node has not been cordoned by " + constants.CordonOwner
+	}
+	if record.By != constants.CordonOwner {
+		return false, fmt.Sprintf("node has been cordoned by %s at %s: %s",
+			record.By, record.Time.Format(time.RFC3339), record.Reason)
+	}
+	delete(node.Annotations, constants.CordonAnnotationKey)
+	node.Spec.Unschedulable = false
+	return true, ""
+}
+
+// EnsureCordoned cordons the given node and patches it on the api server.
+func EnsureCordoned(ctx context.Context, k8sClient client.Client, node *corev1.Node, reason string) error {
+	if node.Spec.Unschedulable {
+		return nil
+	}
+	cloned := node.DeepCopy()
+	if err := Cordon(node, reason); err != nil {
+		return err
+	}
+	if err := k8sClient.Patch(ctx, node, client.MergeFrom(cloned)); err != nil {
+		return fmt.Errorf("failed to cordon node %v: %w", node.Name, err)
+	}
+	return nil
+}
+
+// EnsureUncordoned uncordons the given node and patches it on the api server, if the controller owns its cordon.
+// If the cordon is owned by someone else, false and the reason are returned.
+func EnsureUncordoned(ctx context.Context, k8sClient client.Client, node *corev1.Node) (bool, string, error) {
+	cloned := node.DeepCopy()
+	uncordoned, reason := Uncordon(node)
+	if !uncordoned {
+		return false, reason, nil
+	}
+	if _, ok := cloned.Annotations[constants.CordonAnnotationKey]; !ok && !cloned.Spec.Unschedulable {
+		return true, "", nil
+	}
+	if err := k8sClient.Patch(ctx, node, client.MergeFrom(cloned)); err != nil {
+		return false, "", fmt.Errorf("failed to uncordon node %v: %w", node.Name, err)
+	}
+	return true, "", nil
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/sapcc/maintenance-controller/constants"
+)
+
+var _ = Describe("Cordon ownership", func() {
+
+	It("records cordons of schedulable nodes", func() {
+		node := &corev1.Node{}
+		Expect(Cordon(node, "testing")).To(Succeed())
+		Expect(node.Spec.Unschedulable).To(BeTrue())
+		record, ok := GetCordonRecord(node)
+		Expect(ok).To(BeTrue())
+		Expect(record.By).To(Equal(constants.CordonOwner))
+		Expect(record.Reason).To(Equal("testing"))
+		Expect(record.Time).ToNot(BeZero())
+
+		uncordoned, _ := Uncordon(node)
+		Expect(uncordoned).To(BeTrue())
+		Expect(node.Spec.Unschedulable).To(BeFalse())
+		Expect(node.Annotations).ToNot(HaveKey(constants.CordonAnnotationKey))
+	})
+
+	It("does not take over cordons", func() {
+		node := &corev1.Node{Spec: corev1.NodeSpec{Unschedulable: true}}
+		Expect(Cordon(node, "testing")).To(Succeed())
+		_, ok := GetCordonRecord(node)
+		Expect(ok).To(BeFalse())
+
+		uncordoned, reason := Uncordon(node)
+		Expect(uncordoned).To(BeFalse())
+		Expect(reason).ToNot(BeEmpty())
+		Expect(node.Spec.Unschedulable).To(BeTrue())
+	})
+
+	It("does not uncordon nodes cordoned by others", func() {
+		node := &corev1.Node{Spec: corev1.NodeSpec{Unschedulable: true}}
+		node.Annotations = map[string]string{
+			constants.CordonAnnotationKey: `{"by":"operator","reason":"investigation","time":"2026-01-02T03:04:05Z"}`,
+		}
+		uncordoned, reason := Uncordon(node)
+		Expect(uncordoned).To(BeFalse())
+		Expect(reason).To(ContainSubstring("operator"))
+		Expect(reason).To(ContainSubstring("investigation"))
+		Expect(node.Spec.Unschedulable).To(BeTrue())
+		Expect(node.Annotations).To(HaveKey(constants.CordonAnnotationKey))
+	})
+
+})
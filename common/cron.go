@@ -0,0 +1,277 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lastWeekday marks a nthWeekday, which matches the last occurrence of a weekday within a month.
+const lastWeekday = -1
+
+// cronYearLimit bounds the search for the next activation of a CronSchedule.
+const cronYearLimit = 5
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonths = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronWeekdays = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// nthWeekday matches the n-th occurrence of a weekday within a month.
+type nthWeekday struct {
+	weekday time.Weekday
+	// 1 to 5 or lastWeekday
+	n int
+}
+
+// CronSchedule is a parsed cron expression consisting of the fields
+// minute, hour, day of month, month and day of week.
+// Besides the standard syntax, the day of week field supports "TUE#2" for the second Tuesday
+// and "5L" for the last Friday of a month.
+type CronSchedule struct {
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	nthDow  []nthWeekday
+	domStar bool
+	dowStar bool
+}
+
+// ParseCron parses a cron expression with five fields or one of the descriptors @yearly,
+// @annually, @monthly, @weekly, @daily, @midnight and @hourly.
+func ParseCron(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if descriptor, ok := cronDescriptors[strings.ToLower(expr)]; ok {
+		expr = descriptor
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression '%s' needs to have 5 fields", expr)
+	}
+	var schedule CronSchedule
+	var err error
+	if schedule.minute, _, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid minute field in '%s': %w", expr, err)
+	}
+	if schedule.hour, _, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid hour field in '%s': %w", expr, err)
+	}
+	if schedule.dom, schedule.domStar, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid day of month field in '%s': %w", expr, err)
+	}
+	if schedule.month, _, err = parseCronField(fields[3], 1, 12, cronMonths); err != nil {
+		return nil, fmt.Errorf("invalid month field in '%s': %w", expr, err)
+	}
+	if err = schedule.parseDow(fields[4]); err != nil {
+		return nil, fmt.Errorf("invalid day of week field in '%s': %w", expr, err)
+	}
+	return &schedule, nil
+}
+
+func (cs *CronSchedule) parseDow(field string) error {
+	plain := make([]string, 0)
+	for part := range strings.SplitSeq(field, ",") {
+		nth := 0
+		name := part
+		if before, after, ok := strings.Cut(part, "#"); ok {
+			n, err := strconv.Atoi(after)
+			if err != nil || n < 1 || n > 5 {
+				return fmt.Errorf("invalid occurrence in '%s'", part)
+			}
+			nth, name = n, before
+		} else if len(part) > 1 && strings.HasSuffix(strings.ToUpper(part), "L") {
+			nth, name = lastWeekday, part[:len(part)-1]
+		}
+		if nth == 0 {
+			plain = append(plain, part)
+			continue
+		}
+		weekday, err := parseCronValue(name, 0, 7, cronWeekdays)
+		if err != nil {
+			return err
+		}
+		cs.nthDow = append(cs.nthDow, nthWeekday{weekday: time.Weekday(weekday % 7), n: nth})
+	}
+	if len(plain) == 0 {
+		return nil
+	}
+	bits, star, err := parseCronField(strings.Join(plain, ","), 0, 7, cronWeekdays)
+	if err != nil {
+		return err
+	}
+	// 7 is an alias of Sunday
+	if bits&(1<<7) != 0 {
+		bits |= 1
+	}
+	cs.dow = bits
+	cs.dowStar = star && len(cs.nthDow) == 0
+	return nil
+}
+
+// parseCronField parses a comma separated list of values, ranges and steps into a bitset.
+// It also returns whether the field is unrestricted.
+func parseCronField(field string, low, high int, names map[string]int) (uint64, bool, error) {
+	var bits uint64
+	star := false
+	for part := range strings.SplitSeq(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step < 1 {
+				return 0, false, fmt.Errorf("invalid step in '%s'", part)
+			}
+		}
+		var first, last int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			first, last = low, high
+			star = star || !hasStep
+		case strings.Contains(rangePart, "-"):
+			fromStr, toStr, _ := strings.Cut(rangePart, "-")
+			from, err := parseCronValue(fromStr, low, high, names)
+			if err != nil {
+				return 0, false, err
+			}
+			to, err := parseCronValue(toStr, low, high, names)
+			if err != nil {
+				return 0, false, err
+			}
+			if from > to {
+				return 0, false, fmt.Errorf("range '%s' ends before it starts", rangePart)
+			}
+			first, last = from, to
+		default:
+			value, err := parseCronValue(rangePart, low, high, names)
+			if err != nil {
+				return 0, false, err
+			}
+			first, last = value, value
+			if hasStep {
+				last = high
+			}
+		}
+		for i := first; i <= last; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, star, nil
+}
+
+func parseCronValue(s string, low, high int, names map[string]int) (int, error) {
+	if value, ok := names[strings.ToLower(s)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s'", s)
+	}
+	if value < low || value > high {
+		return 0, fmt.Errorf("value %d is not within %d and %d", value, low, high)
+	}
+	return value, nil
+}
+
+// Next returns the first activation of the schedule strictly after the given time in the location of the given time.
+// If there is no activation within the next years, the zero time is returned.
+func (cs *CronSchedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	// advance ensures progress even if a wall clock time does not exist due to daylight saving time
+	advance := func(next time.Time) time.Time {
+		if !next.After(t) {
+			return t.Add(time.Minute)
+		}
+		return next
+	}
+	limit := after.Year() + cronYearLimit
+	for t.Year() <= limit {
+		switch {
+		case cs.month&(1<<uint(t.Month())) == 0:
+			t = advance(time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc))
+		case !cs.matchesDay(t):
+			t = advance(time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc))
+		case cs.hour&(1<<uint(t.Hour())) == 0:
+			t = advance(time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc))
+		case cs.minute&(1<<uint(t.Minute())) == 0:
+			t = advance(time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc))
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// matchesDay implements the cron semantics, that a day matches if either the day of month or the day of week matches,
+// if both fields are restricted.
+func (cs *CronSchedule) matchesDay(t time.Time) bool {
+	domMatch := cs.dom&(1<<uint(t.Day())) != 0
+	dowMatch := cs.dow&(1<<uint(t.Weekday())) != 0
+	if !dowMatch {
+		daysInMonth := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+		for _, nth := range cs.nthDow {
+			if nth.weekday != t.Weekday() {
+				continue
+			}
+			if (nth.n == lastWeekday && t.Day()+7 > daysInMonth) || (t.Day()-1)/7+1 == nth.n {
+				dowMatch = true
+				break
+			}
+		}
+	}
+	if cs.domStar || cs.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// CronWindow is a recurring time window, which starts according to a cron schedule and lasts for a duration.
+// Windows may span midnight.
+type CronWindow struct {
+	Schedule *CronSchedule
+	Duration time.Duration
+}
+
+// ParseCronWindow parses the given cron expression into a window of the given duration.
+func ParseCronWindow(expr string, duration time.Duration) (CronWindow, error) {
+	if duration <= 0 {
+		return CronWindow{}, errors.New("the duration of a window needs to be positive")
+	}
+	schedule, err := ParseCron(expr)
+	if err != nil {
+		return CronWindow{}, err
+	}
+	return CronWindow{Schedule: schedule, Duration: duration}, nil
+}
+
+// Active returns the start of the window, which contains the given time.
+// The location of the given time determines the time zone the schedule is evaluated in.
+func (cw *CronWindow) Active(t time.Time) (time.Time, bool) {
+	start := cw.Schedule.Next(t.Add(-cw.Duration))
+	if start.IsZero() || start.After(t) {
+		return time.Time{}, false
+	}
+	return start, true
+}
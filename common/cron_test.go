@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseCron", func() {
+
+	It("should parse a standard expression", func() {
+		schedule, err := ParseCron("*/15 22 * * MON-FRI")
+		Expect(err).To(Succeed())
+		// Sunday
+		after := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+		Expect(schedule.Next(after)).To(Equal(time.Date(2026, time.October, 19, 22, 0, 0, 0, time.UTC)))
+		after = time.Date(2026, time.October, 19, 22, 0, 0, 0, time.UTC)
+		Expect(schedule.Next(after)).To(Equal(time.Date(2026, time.October, 19, 22, 15, 0, 0, time.UTC)))
+	})
+
+	It("should parse descriptors", func() {
+		schedule, err := ParseCron("@monthly")
+		Expect(err).To(Succeed())
+		after := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+		Expect(schedule.Next(after)).To(Equal(time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("should support the n-th weekday of a month", func() {
+		schedule, err := ParseCron("0 3 * * TUE#2")
+		Expect(err).To(Succeed())
+		after := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+		Expect(schedule.Next(after)).To(Equal(time.Date(2026, time.October, 13, 3, 0, 0, 0, time.UTC)))
+	})
+
+	It("should support the last weekday of a month", func() {
+		schedule, err := ParseCron("0 3 * * 5L")
+		Expect(err).To(Succeed())
+		after := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+		Expect(schedule.Next(after)).To(Equal(time.Date(2026, time.October, 30, 3, 0, 0, 0, time.UTC)))
+	})
+
+	It("should match either day of month or day of week if both are restricted", func() {
+		schedule, err := ParseCron("0 0 20 * SUN")
+		Expect(err).To(Succeed())
+		after := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+		Expect(schedule.Next(after)).To(Equal(time.Date(2026, time.October, 20, 0, 0, 0, 0, time.UTC)))
+		after = time.Date(2026, time.October, 20, 12, 0, 0, 0, time.UTC)
+		Expect(schedule.Next(after)).To(Equal(time.Date(2026, time.October, 25, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("should evaluate in the location of the given time", func() {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		Expect(err).To(Succeed())
+		schedule, err := ParseCron("0 22 * * *")
+		Expect(err).To(Succeed())
+		after := time.Date(2026, time.October, 18, 12, 0, 0, 0, berlin)
+		Expect(schedule.Next(after)).To(Equal(time.Date(2026, time.October, 18, 22, 0, 0, 0, berlin)))
+	})
+
+	It("should return the zero time for impossible schedules", func() {
+		schedule, err := ParseCron("0 0 31 2 *")
+		Expect(err).To(Succeed())
+		Expect(schedule.Next(time.Now()).IsZero()).To(BeTrue())
+	})
+
+	It("should reject invalid expressions", func() {
+		for _, expr := range []string{"* * * *", "60 * * * *", "* * * * MON#6", "5-1 * * * *", "*/0 * * * *", "abc * * * *"} {
+			_, err := ParseCron(expr)
+			Expect(err).To(HaveOccurred(), expr)
+		}
+	})
+
+})
+
+var _ = Describe("CronWindow", func() {
+
+	It("should be active within the duration", func() {
+		window, err := ParseCronWindow("0 22 * * *", 4*time.Hour)
+		Expect(err).To(Succeed())
+		start, ok := window.Active(time.Date(2026, time.October, 19, 1, 30, 0, 0, time.UTC))
+		Expect(ok).To(BeTrue())
+		Expect(start).To(Equal(time.Date(2026, time.October, 18, 22, 0, 0, 0, time.UTC)))
+	})
+
+	It("should be inactive outside the duration", func() {
+		window, err := ParseCronWindow("0 22 * * *", 4*time.Hour)
+		Expect(err).To(Succeed())
+		_, ok := window.Active(time.Date(2026, time.October, 19, 2, 0, 0, 0, time.UTC))
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should reject non-positive durations", func() {
+		_, err := ParseCronWindow("0 22 * * *", 0)
+		Expect(err).To(HaveOccurred())
+	})
+
+})
@@ -0,0 +1,250 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DrainOrderType defines how pods are grouped into batches, which are evicted one after another.
+type DrainOrderType string
+
+const (
+	// OrderNone evicts all pods at once.
+	OrderNone DrainOrderType = ""
+	// OrderPriority evicts pods with a lower priority first.
+	OrderPriority DrainOrderType = "priority"
+	// OrderOwnerKind evicts pods in the order of their controllers kinds.
+	OrderOwnerKind DrainOrderType = "ownerKind"
+	// OrderLabels evicts pods in the order of the label selectors they match.
+	OrderLabels DrainOrderType = "labels"
+)
+
+// DrainOrder defines the order in which pods are evicted.
+// A batch is only evicted once all pods of the previous batches are gone.
+type DrainOrder struct {
+	By DrainOrderType `config:"by"`
+	// controller kinds in eviction order for OrderOwnerKind, e.g. Job, Deployment, StatefulSet,
+	// pods with other or without controllers are evicted last
+	OwnerKinds []string `config:"ownerKinds"`
+	// label selectors in eviction order for OrderLabels, pods not matching any selector are evicted last
+	Labels []string `config:"labels"`
+	// await the Deployments and StatefulSets owning pods of the previous batch to be fully available
+	WaitForReplacement bool `config:"waitForReplacement"`
+}
+
+// Validate is invoked when unpacking configuration.
+func (do *DrainOrder) Validate() error {
+	switch do.By {
+	case OrderNone, OrderPriority:
+	case OrderOwnerKind:
+		if len(do.OwnerKinds) == 0 {
+			return errors.New("drain order by ownerKind requires ownerKinds")
+		}
+	case OrderLabels:
+		if len(do.Labels) == 0 {
+			return errors.New("drain order by labels requires labels")
+		}
+		if _, err := do.parseSelectors(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid drain order: %s", do.By)
+	}
+	return nil
+}
+
+func (do *DrainOrder) parseSelectors() ([]labels.Selector, error) {
+	selectors := make([]labels.Selector, 0, len(do.Labels))
+	for _, raw := range do.Labels {
+		selector, err := labels.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid drain order label selector %s: %w", raw, err)
+		}
+		selectors = append(selectors, selector)
+	}
+	return selectors, nil
+}
+
+// NextBatch returns the pods with the lowest rank, which are evicted before all other pods.
+func (do *DrainOrder) NextBatch(pods []corev1.Pod) ([]corev1.Pod, error) {
+	if do.By == OrderNone || len(pods) == 0 {
+		return pods, nil
+	}
+	rank, err := do.ranker()
+	if err != nil {
+		return nil, err
+	}
+	lowest := math.MaxInt
+	for i := range pods {
+		lowest = min(lowest, rank(&pods[i]))
+	}
+	batch := make([]corev1.Pod, 0)
+	for i := range pods {
+		if rank(&pods[i]) == lowest {
+			batch = append(batch, pods[i])
+		}
+	}
+	return batch, nil
+}
+
+func (do *DrainOrder) ranker() (func(*corev1.Pod) int, error) {
+	switch do.By {
+	case OrderPriority:
+		return func(pod *corev1.Pod) int {
+			if pod.Spec.Priority == nil {
+				return 0
+			}
+			return int(*pod.Spec.Priority)
+		}, nil
+	case OrderOwnerKind:
+		return func(pod *corev1.Pod) int {
+			owner := metav1.GetControllerOf(pod)
+			if owner == nil {
+				return len(do.OwnerKinds)
+			}
+			kind, _ := WorkloadOf(pod)
+			// pods of Deployments are also ranked by ReplicaSet, if Deployment is not listed
+			idx := slices.Index(do.OwnerKinds, kind)
+			if idx == -1 {
+				idx = slices.Index(do.OwnerKinds, owner.Kind)
+			}
+			if idx == -1 {
+				return len(do.OwnerKinds)
+			}
+			return idx
+		}, nil
+	case OrderLabels:
+		selectors, err := do.parseSelectors()
+		if err != nil {
+			return nil, err
+		}
+		return func(pod *corev1.Pod) int {
+			for i, selector := range selectors {
+				if selector.Matches(labels.Set(pod.Labels)) {
+					return i
+				}
+			}
+			return len(selectors)
+		}, nil
+	}
+	return nil, fmt.Errorf("invalid drain order: %s", do.By)
+}
+
+// WorkloadOf returns the kind and name of the controller of the given pod.
+// Pods of ReplicaSets created by a Deployment are attributed to the Deployment
+// and pods without a controller to themselves with the kind Pod.
+func WorkloadOf(pod *corev1.Pod) (kind, name string) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "Pod", pod.Name
+	}
+	hash, ok := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+	if owner.Kind == "ReplicaSet" && ok && strings.HasSuffix(owner.Name, "-"+hash) {
+		return "Deployment", strings.TrimSuffix(owner.Name, "-"+hash)
+	}
+	return owner.Kind, owner.Name
+}
+
+// WorkloadRef identifies a Deployment or StatefulSet.
+type WorkloadRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+func (ref WorkloadRef) String() string {
+	return fmt.Sprintf("%s %s/%s", ref.Kind, ref.Namespace, ref.Name)
+}
+
+// trackReplacements remembers the workloads owning the given pods in the progress of the drain.
+func trackReplacements(progress *DrainProgress, pods []corev1.Pod) {
+	for i := range pods {
+		pod := &pods[i]
+		kind, name := WorkloadOf(pod)
+		if kind != "Deployment" && kind != "StatefulSet" {
+			continue
+		}
+		ref := WorkloadRef{Kind: kind, Namespace: pod.Namespace, Name: name}
+		if !slices.Contains(progress.Replacements, ref) {
+			progress.Replacements = append(progress.Replacements, ref)
+		}
+	}
+}
+
+// awaitReplacements returns the tracked workloads of the drain, which are not fully available.
+// Workloads, which are available again, are no longer tracked.
+func awaitReplacements(ctx context.Context, k8sClient client.Client, progress *DrainProgress) ([]string, error) {
+	pending := make([]WorkloadRef, 0)
+	unavailable := make([]string, 0)
+	var errs []error
+	for _, ref := range progress.Replacements {
+		available, err := isWorkloadAvailable(ctx, k8sClient, ref)
+		if err != nil {
+			errs = append(errs, err)
+			pending = append(pending, ref)
+			continue
+		}
+		if !available {
+			pending = append(pending, ref)
+			unavailable = append(unavailable, ref.String())
+		}
+	}
+	if len(pending) == 0 {
+		pending = nil
+	}
+	progress.Replacements = pending
+	return unavailable, errors.Join(errs...)
+}
+
+func isWorkloadAvailable(ctx context.Context, k8sClient client.Client, ref WorkloadRef) (bool, error) {
+	var obj client.Object
+	switch ref.Kind {
+	case "Deployment":
+		obj = &appsv1.Deployment{}
+	case "StatefulSet":
+		obj = &appsv1.StatefulSet{}
+	default:
+		return true, nil
+	}
+	err := k8sClient.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, obj)
+	if k8serrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch %s: %w", ref, err)
+	}
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		desired := ptrValueOrOne(workload.Spec.Replicas)
+		return workload.Status.ObservedGeneration >= workload.Generation &&
+			workload.Status.UpdatedReplicas >= desired &&
+			workload.Status.AvailableReplicas >= desired, nil
+	case *appsv1.StatefulSet:
+		desired := ptrValueOrOne(workload.Spec.Replicas)
+		return workload.Status.ObservedGeneration >= workload.Generation &&
+			workload.Status.AvailableReplicas >= desired, nil
+	}
+	return true, nil
+}
+
+func ptrValueOrOne(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("DrainOrder", func() {
+
+	controller := true
+	makePod := func(name, ownerKind string, priority int32, podLabels map[string]string) corev1.Pod {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: podLabels},
+			Spec:       corev1.PodSpec{Priority: &priority},
+		}
+		if ownerKind != "" {
+			pod.OwnerReferences = []metav1.OwnerReference{{Kind: ownerKind, Name: name, Controller: &controller}}
+		}
+		return pod
+	}
+	names := func(pods []corev1.Pod) []string {
+		result := make([]string, 0)
+		for _, pod := range pods {
+			result = append(result, pod.Name)
+		}
+		return result
+	}
+
+	pods := []corev1.Pod{
+		makePod("database", "StatefulSet", 1000, map[string]string{"tier": "db"}),
+		makePod("batch", "Job", -10, map[string]string{"tier": "batch"}),
+		makePod("web", "ReplicaSet", 100, map[string]string{"tier": "web"}),
+		makePod("bare", "", 100, nil),
+	}
+
+	It("returns all pods without an order", func() {
+		var order DrainOrder
+		batch, err := order.NextBatch(pods)
+		Expect(err).To(Succeed())
+		Expect(batch).To(HaveLen(4))
+	})
+
+	It("orders by priority", func() {
+		order := DrainOrder{By: OrderPriority}
+		batch, err := order.NextBatch(pods)
+		Expect(err).To(Succeed())
+		Expect(names(batch)).To(HaveExactElements("batch"))
+		batch, err = order.NextBatch(pods[2:])
+		Expect(err).To(Succeed())
+		Expect(names(batch)).To(HaveExactElements("web", "bare"))
+	})
+
+	It("orders by owner kind", func() {
+		order := DrainOrder{By: OrderOwnerKind, OwnerKinds: []string{"Job", "ReplicaSet", "StatefulSet"}}
+		batch, err := order.NextBatch(pods)
+		Expect(err).To(Succeed())
+		Expect(names(batch)).To(HaveExactElements("batch"))
+		batch, err = order.NextBatch([]corev1.Pod{pods[0], pods[3]})
+		Expect(err).To(Succeed())
+		Expect(names(batch)).To(HaveExactElements("database"))
+	})
+
+	It("orders by labels", func() {
+		order := DrainOrder{By: OrderLabels, Labels: []string{"tier=web", "tier in (batch, db)"}}
+		batch, err := order.NextBatch(pods)
+		Expect(err).To(Succeed())
+		Expect(names(batch)).To(HaveExactElements("web"))
+		batch, err = order.NextBatch([]corev1.Pod{pods[0], pods[1], pods[3]})
+		Expect(err).To(Succeed())
+		Expect(names(batch)).To(HaveExactElements("database", "batch"))
+	})
+
+	It("validates its configuration", func() {
+		Expect((&DrainOrder{By: "random"}).Validate()).ToNot(Succeed())
+		Expect((&DrainOrder{By: OrderOwnerKind}).Validate()).ToNot(Succeed())
+		Expect((&DrainOrder{By: OrderLabels, Labels: []string{"a in (b"}}).Validate()).ToNot(Succeed())
+		Expect((&DrainOrder{By: OrderPriority, WaitForReplacement: true}).Validate()).To(Succeed())
+	})
+
+	It("orders pods of Deployments by owner kind", func() {
+		deploymentPod := makePod("web-abc12-x", "ReplicaSet", 0, map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "abc12"})
+		deploymentPod.OwnerReferences[0].Name = "web-abc12"
+		order := DrainOrder{By: OrderOwnerKind, OwnerKinds: []string{"Deployment", "StatefulSet"}}
+		batch, err := order.NextBatch([]corev1.Pod{pods[0], deploymentPod, pods[2]})
+		Expect(err).To(Succeed())
+		Expect(names(batch)).To(HaveExactElements("web-abc12-x"))
+
+		order.OwnerKinds = []string{"ReplicaSet", "StatefulSet"}
+		batch, err = order.NextBatch([]corev1.Pod{pods[0], deploymentPod, pods[2]})
+		Expect(err).To(Succeed())
+		Expect(names(batch)).To(HaveExactElements("web-abc12-x", "web"))
+	})
+
+	It("determines the workload of pods", func() {
+		deploymentPod := makePod("web-abc12-x", "ReplicaSet", 0, map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "abc12"})
+		deploymentPod.OwnerReferences[0].Name = "web-abc12"
+		kind, name := WorkloadOf(&deploymentPod)
+		Expect(kind).To(Equal("Deployment"))
+		Expect(name).To(Equal("web"))
+		kind, name = WorkloadOf(&pods[2])
+		Expect(kind).To(Equal("ReplicaSet"))
+		Expect(name).To(Equal("web"))
+		kind, name = WorkloadOf(&pods[3])
+		Expect(kind).To(Equal("Pod"))
+		Expect(name).To(Equal("bare"))
+	})
+
+	It("awaits replacements of evicted pods", func() {
+		replicas := int32(2)
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{UpdatedReplicas: 2, AvailableReplicas: 1},
+		}
+		k8sClient := fake.NewClientBuilder().
+			WithObjects(deployment).
+			WithStatusSubresource(&appsv1.Deployment{}).
+			Build()
+		ctx := context.Background()
+		pod := makePod("web-abc12-x", "ReplicaSet", 0, map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "abc12"})
+		pod.OwnerReferences[0].Name = "web-abc12"
+		var progress DrainProgress
+
+		trackReplacements(&progress, []corev1.Pod{pod, pods[1]})
+		Expect(progress.Replacements).To(HaveExactElements(WorkloadRef{Kind: "Deployment", Namespace: "default", Name: "web"}))
+		// the tracked workloads survive a restart of the controller as part of the persisted progress
+		data, err := json.Marshal(&progress)
+		Expect(err).To(Succeed())
+		var restored DrainProgress
+		Expect(json.Unmarshal(data, &restored)).To(Succeed())
+		unavailable, err := awaitReplacements(ctx, k8sClient, &restored)
+		Expect(err).To(Succeed())
+		Expect(unavailable).To(HaveExactElements("Deployment default/web"))
+
+		deployment.Status.AvailableReplicas = 2
+		Expect(k8sClient.Status().Update(ctx, deployment)).To(Succeed())
+		unavailable, err = awaitReplacements(ctx, k8sClient, &restored)
+		Expect(err).To(Succeed())
+		Expect(unavailable).To(BeEmpty())
+		Expect(restored.Replacements).To(BeEmpty())
+	})
+
+})
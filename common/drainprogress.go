@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// BlockedPod is a pod, which eviction has been rejected due to a PodDisruptionBudget.
+type BlockedPod struct {
+	Pod string `json:"pod"`
+	// empty if the budget could not be determined
+	PodDisruptionBudget string `json:"podDisruptionBudget"`
+}
+
+// DrainProgress describes the progress of a drain, which spans multiple invocations of EnsureDrain.
+type DrainProgress struct {
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished,omitzero"`
+	// pods, which still need to vanish from the node
+	Remaining []string `json:"remaining,omitempty"`
+	// pods, which eviction has been rejected during the last invocation
+	Blocked []BlockedPod `json:"blocked,omitempty"`
+	// pods, which have been force deleted after exceeding their grace period
+	ForceDeleted []string `json:"forceDeleted,omitempty"`
+	// count of pods, which have been evicted or deleted successfully
+	Evicted int `json:"evicted"`
+	// workloads, which lost pods due to evictions and are awaited to be available before the next batch
+	Replacements []WorkloadRef `json:"replacements,omitempty"`
+	// reasons of skipped or blocking pods, which have already been reported by an event during this drain
+	Reported map[string]string `json:"reported,omitempty"`
+	// whether the maintenance ended before the drain finished
+	Aborted bool `json:"aborted,omitempty"`
+}
+
+// DrainProgresses keeps the progress of drains per node in memory for callers of EnsureDrain,
+// which do not persist it. The zero value is ready to use.
+type DrainProgresses struct {
+	mutex      sync.Mutex
+	progresses map[string]*DrainProgress
+}
+
+// Get returns the progress of the drain of the given node.
+func (dp *DrainProgresses) Get(node string) *DrainProgress {
+	dp.mutex.Lock()
+	defer dp.mutex.Unlock()
+	if dp.progresses == nil {
+		dp.progresses = make(map[string]*DrainProgress)
+	}
+	progress, ok := dp.progresses[node]
+	if !ok {
+		progress = &DrainProgress{}
+		dp.progresses[node] = progress
+	}
+	return progress
+}
+
+// Forget drops the progress of the drain of the given node, e.g. once it has been drained.
+func (dp *DrainProgresses) Forget(node string) {
+	dp.mutex.Lock()
+	defer dp.mutex.Unlock()
+	delete(dp.progresses, node)
+}
+
+// InProgress returns whether a drain started, but did not finish yet.
+func (dp *DrainProgress) InProgress() bool {
+	return !dp.Started.IsZero() && dp.Finished.IsZero()
+}
+
+// Duration returns how long the drain took or is running for.
+func (dp *DrainProgress) Duration() time.Duration {
+	if dp.Finished.IsZero() {
+		return time.Since(dp.Started)
+	}
+	return dp.Finished.Sub(dp.Started)
+}
+
+// begin resets the progress if no drain is in progress.
+func (dp *DrainProgress) begin() {
+	if dp.InProgress() {
+		return
+	}
+	*dp = DrainProgress{Started: time.Now().UTC()}
+}
+
+func (dp *DrainProgress) finish() {
+	dp.Finished = time.Now().UTC()
+	dp.Remaining = nil
+	dp.Blocked = nil
+	dp.Reported = nil
+	dp.Replacements = nil
+}
+
+// Abort finishes a drain in progress, which is not going to be continued.
+func (dp *DrainProgress) Abort() {
+	if !dp.InProgress() {
+		return
+	}
+	dp.finish()
+	dp.Aborted = true
+}
+
+// report returns whether the pod has not been reported with the given reason during this drain and records it.
+func (dp *DrainProgress) report(pod *corev1.Pod, reason string) bool {
+	key := podKey(pod)
+	if reported, ok := dp.Reported[key]; ok && reported == reason {
+		return false
+	}
+	if dp.Reported == nil {
+		dp.Reported = make(map[string]string)
+	}
+	dp.Reported[key] = reason
+	return true
+}
+
+func (dp *DrainProgress) addForceDeleted(pods []corev1.Pod) {
+	for i := range pods {
+		key := podKey(&pods[i])
+		if !slices.Contains(dp.ForceDeleted, key) {
+			dp.ForceDeleted = append(dp.ForceDeleted, key)
+		}
+	}
+}
+
+func podKey(pod *corev1.Pod) string {
+	return types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}.String()
+}
+
+func podKeys(pods []corev1.Pod) []string {
+	keys := make([]string, 0, len(pods))
+	for i := range pods {
+		keys = append(keys, podKey(&pods[i]))
+	}
+	return keys
+}
+
+// blockedByBudgets determines the PodDisruptionBudgets, which likely rejected the eviction of the given pods.
+func blockedByBudgets(ctx context.Context, k8sClient client.Client, pods []corev1.Pod) ([]BlockedPod, error) {
+	blocked := make([]BlockedPod, 0, len(pods))
+	budgets := make(map[string][]policyv1.PodDisruptionBudget)
+	for i := range pods {
+		pod := &pods[i]
+		namespaced, ok := budgets[pod.Namespace]
+		if !ok {
+			var pdbList policyv1.PodDisruptionBudgetList
+			if err := k8sClient.List(ctx, &pdbList, client.InNamespace(pod.Namespace)); err != nil {
+				return nil, fmt.Errorf("failed to list pod disruption budgets: %w", err)
+			}
+			namespaced = pdbList.Items
+			budgets[pod.Namespace] = namespaced
+		}
+		entry := BlockedPod{Pod: podKey(pod)}
+		for j := range namespaced {
+			selector, err := metav1.LabelSelectorAsSelector(namespaced[j].Spec.Selector)
+			if err != nil || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			entry.PodDisruptionBudget = types.NamespacedName{Namespace: namespaced[j].Namespace, Name: namespaced[j].Name}.String()
+			break
+		}
+		blocked = append(blocked, entry)
+	}
+	return blocked, nil
+}
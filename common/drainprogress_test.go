@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("DrainProgress", func() {
+
+	It("starts a new drain only if none is in progress", func() {
+		var progress DrainProgress
+		Expect(progress.InProgress()).To(BeFalse())
+		progress.begin()
+		Expect(progress.InProgress()).To(BeTrue())
+		started := progress.Started
+		progress.Evicted = 3
+		progress.begin()
+		Expect(progress.Started).To(Equal(started))
+		Expect(progress.Evicted).To(Equal(3))
+
+		progress.Remaining = []string{"default/a"}
+		progress.finish()
+		Expect(progress.InProgress()).To(BeFalse())
+		Expect(progress.Remaining).To(BeEmpty())
+		Expect(progress.Duration()).To(BeNumerically(">=", 0))
+
+		progress.begin()
+		Expect(progress.InProgress()).To(BeTrue())
+		Expect(progress.Evicted).To(BeZero())
+	})
+
+	It("aborts drains in progress only", func() {
+		var progress DrainProgress
+		progress.Abort()
+		Expect(progress.Aborted).To(BeFalse())
+		progress.begin()
+		progress.Remaining = []string{"default/a"}
+		progress.Abort()
+		Expect(progress.InProgress()).To(BeFalse())
+		Expect(progress.Aborted).To(BeTrue())
+		Expect(progress.Remaining).To(BeEmpty())
+
+		progress.begin()
+		Expect(progress.Aborted).To(BeFalse())
+	})
+
+	It("tracks force deleted pods once", func() {
+		var progress DrainProgress
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"}}
+		progress.addForceDeleted([]corev1.Pod{pod})
+		progress.addForceDeleted([]corev1.Pod{pod})
+		Expect(progress.ForceDeleted).To(HaveExactElements("default/a"))
+	})
+
+	It("reports filtered pods once per drain and reason", func() {
+		pods := []client.Object{
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "skipped", Namespace: "kube-system"},
+				Spec:       corev1.PodSpec{NodeName: "node"},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: "default"},
+				Spec:       corev1.PodSpec{NodeName: "node"},
+			},
+		}
+		k8sClient := fake.NewClientBuilder().
+			WithObjects(pods...).
+			WithIndex(&corev1.Pod{}, "spec.nodeName", func(o client.Object) []string {
+				pod, ok := o.(*corev1.Pod)
+				if !ok {
+					return []string{}
+				}
+				return []string{pod.Spec.NodeName}
+			}).
+			Build()
+		recorder := events.NewFakeRecorder(10)
+		params := DrainParameters{
+			Client:   k8sClient,
+			Recorder: recorder,
+			Filter:   PodFilter{SkipNamespaces: []string{"kube-system"}, RefuseUnmanaged: true},
+			Progress: &DrainProgress{},
+		}
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}}
+		for range 3 {
+			_, err := FilterPodsForDrain(context.Background(), node, &params)
+			Expect(err).To(Succeed())
+		}
+		Expect(recorder.Events).To(HaveLen(2))
+		Expect(<-recorder.Events).To(HavePrefix("Normal DrainSkipped"))
+		Expect(<-recorder.Events).To(HavePrefix("Warning DrainBlocked"))
+
+		params.Filter.SkipNamespaces = []string{"kube-system", "default"}
+		_, err := FilterPodsForDrain(context.Background(), node, &params)
+		Expect(err).To(Succeed())
+		Expect(recorder.Events).To(HaveLen(1))
+		Expect(<-recorder.Events).To(HavePrefix("Normal DrainSkipped"))
+
+		params.Progress.finish()
+		params.Progress.begin()
+		_, err = FilterPodsForDrain(context.Background(), node, &params)
+		Expect(err).To(Succeed())
+		Expect(recorder.Events).To(HaveLen(2))
+	})
+
+	It("keeps the progress of drains per node", func() {
+		var progresses DrainProgresses
+		progress := progresses.Get("node")
+		progress.begin()
+		Expect(progresses.Get("node")).To(BeIdenticalTo(progress))
+		Expect(progresses.Get("other").InProgress()).To(BeFalse())
+		progresses.Forget("node")
+		Expect(progresses.Get("node")).ToNot(BeIdenticalTo(progress))
+	})
+
+	It("determines the budgets of blocked pods", func() {
+		pdb := &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: "pdb", Namespace: "default"},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "guarded"}},
+			},
+		}
+		k8sClient := fake.NewClientBuilder().WithObjects(pdb).Build()
+		pods := []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "guarded", Namespace: "default", Labels: map[string]string{"app": "guarded"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}},
+		}
+		blocked, err := blockedByBudgets(context.Background(), k8sClient, pods)
+		Expect(err).To(Succeed())
+		Expect(blocked).To(HaveExactElements(
+			BlockedPod{Pod: "default/guarded", PodDisruptionBudget: "default/pdb"},
+			BlockedPod{Pod: "default/other"},
+		))
+	})
+
+	It("serializes without empty fields", func() {
+		progress := DrainProgress{Started: time.Date(2026, time.March, 3, 10, 0, 0, 0, time.UTC)}
+		Expect(json.Marshal(&progress)).To(MatchJSON(`{"started":"2026-03-03T10:00:00Z","evicted":0}`))
+	})
+
+})
@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetNodeCondition adds or updates the given condition in memory.
+// The transition time is only updated if the status changes.
+func SetNodeCondition(node *corev1.Node, condition corev1.NodeCondition) {
+	now := metav1.Now()
+	for i := range node.Status.Conditions {
+		existing := &node.Status.Conditions[i]
+		if existing.Type != condition.Type {
+			continue
+		}
+		if existing.Status != condition.Status {
+			existing.LastTransitionTime = now
+		}
+		if existing.Status != condition.Status || existing.Reason != condition.Reason || existing.Message != condition.Message {
+			existing.LastHeartbeatTime = now
+		}
+		existing.Status = condition.Status
+		existing.Reason = condition.Reason
+		existing.Message = condition.Message
+		return
+	}
+	condition.LastTransitionTime = now
+	condition.LastHeartbeatTime = now
+	node.Status.Conditions = append(node.Status.Conditions, condition)
+}
+
+// RemoveNodeCondition removes the condition of the given type in memory.
+func RemoveNodeCondition(node *corev1.Node, conditionType corev1.NodeConditionType) {
+	node.Status.Conditions = slices.DeleteFunc(node.Status.Conditions, func(condition corev1.NodeCondition) bool {
+		return condition.Type == conditionType
+	})
+}
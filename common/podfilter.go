@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/sapcc/maintenance-controller/constants"
+)
+
+// EmptyDirPolicy defines how pods with emptyDir volumes are handled during a drain.
+type EmptyDirPolicy string
+
+const (
+	// EmptyDirDelete evicts pods with emptyDir volumes, which deletes the local data.
+	EmptyDirDelete EmptyDirPolicy = "delete"
+	// EmptyDirSkip leaves pods with emptyDir volumes on the node.
+	EmptyDirSkip EmptyDirPolicy = "skip"
+	// EmptyDirRefuse blocks the drain as long as pods with emptyDir volumes are on the node.
+	EmptyDirRefuse EmptyDirPolicy = "refuse"
+)
+
+// PodFilter resembles the pod filters of kubectl drain.
+// The zero value evicts all pods returned by GetPodsForDrain.
+type PodFilter struct {
+	// how to handle pods with emptyDir volumes, defaults to EmptyDirDelete
+	EmptyDir EmptyDirPolicy `config:"emptyDir"`
+	// block the drain on pods, which are not managed by a controller
+	RefuseUnmanaged bool `config:"refuseUnmanaged"`
+	// label selector of pods, which are left on the node
+	SkipSelector string `config:"skipSelector"`
+	// namespaces, which pods are left on the node
+	SkipNamespaces []string `config:"skipNamespaces"`
+	// leave pods, which are succeeded or failed, on the node
+	SkipCompleted bool `config:"skipCompleted"`
+}
+
+// Validate is invoked when unpacking configuration.
+func (pf *PodFilter) Validate() error {
+	switch pf.EmptyDir {
+	case "", EmptyDirDelete, EmptyDirSkip, EmptyDirRefuse:
+	default:
+		return fmt.Errorf("invalid emptyDir policy: %s", pf.EmptyDir)
+	}
+	if _, err := labels.Parse(pf.SkipSelector); err != nil {
+		return fmt.Errorf("invalid skipSelector: %w", err)
+	}
+	return nil
+}
+
+// FilteredPod is a pod, which is not evicted, with the reason why.
+type FilteredPod struct {
+	Pod    corev1.Pod
+	Reason string
+}
+
+// PodFilterResult partitions pods according to a PodFilter.
+type PodFilterResult struct {
+	// pods to evict
+	Drain []corev1.Pod
+	// pods, which are left on the node
+	Skipped []FilteredPod
+	// pods, which prevent the node from being drained
+	Blocking []FilteredPod
+}
+
+// Apply partitions the given pods.
+// Pods annotated with constants.DrainSkipAnnotationKey set to "true" are always skipped.
+func (pf *PodFilter) Apply(pods []corev1.Pod) (PodFilterResult, error) {
+	selector, err := labels.Parse(pf.SkipSelector)
+	if err != nil {
+		return PodFilterResult{}, fmt.Errorf("invalid skipSelector: %w", err)
+	}
+	result := PodFilterResult{
+		Drain:    make([]corev1.Pod, 0),
+		Skipped:  make([]FilteredPod, 0),
+		Blocking: make([]FilteredPod, 0),
+	}
+	for i := range pods {
+		pod := pods[i]
+		skipReason, blockReason := pf.evaluate(&pod, selector)
+		switch {
+		case skipReason != "":
+			result.Skipped = append(result.Skipped, FilteredPod{Pod: pod, Reason: skipReason})
+		case blockReason != "":
+			result.Blocking = append(result.Blocking, FilteredPod{Pod: pod, Reason: blockReason})
+		default:
+			result.Drain = append(result.Drain, pod)
+		}
+	}
+	return result, nil
+}
+
+func (pf *PodFilter) evaluate(pod *corev1.Pod, selector labels.Selector) (skipReason, blockReason string) {
+	if pod.Annotations[constants.DrainSkipAnnotationKey] == constants.TrueStr {
+		return fmt.Sprintf("pod is annotated with %s", constants.DrainSkipAnnotationKey), ""
+	}
+	if slices.Contains(pf.SkipNamespaces, pod.Namespace) {
+		return fmt.Sprintf("namespace %s is skipped", pod.Namespace), ""
+	}
+	if !selector.Empty() && selector.Matches(labels.Set(pod.Labels)) {
+		return fmt.Sprintf("pod matches selector %s", selector.String()), ""
+	}
+	if pf.SkipCompleted && (pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed) {
+		return fmt.Sprintf("pod is completed with phase %s", pod.Status.Phase), ""
+	}
+	if hasEmptyDir(pod) {
+		switch pf.EmptyDir {
+		case EmptyDirSkip:
+			return "pod uses emptyDir volumes", ""
+		case EmptyDirRefuse:
+			return "", "pod uses emptyDir volumes"
+		}
+	}
+	if pf.RefuseUnmanaged && metav1.GetControllerOf(pod) == nil {
+		return "", "pod is not managed by a controller"
+	}
+	return "", ""
+}
+
+func hasEmptyDir(pod *corev1.Pod) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.EmptyDir != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sapcc/maintenance-controller/constants"
+)
+
+var _ = Describe("PodFilter", func() {
+
+	controller := true
+	makePod := func(name string, mutate func(*corev1.Pod)) corev1.Pod {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: "ReplicaSet", Name: "rs", Controller: &controller},
+				},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		if mutate != nil {
+			mutate(&pod)
+		}
+		return pod
+	}
+	withEmptyDir := func(pod *corev1.Pod) {
+		pod.Spec.Volumes = []corev1.Volume{
+			{Name: "scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		}
+	}
+	names := func(pods []corev1.Pod) []string {
+		result := make([]string, 0)
+		for _, pod := range pods {
+			result = append(result, pod.Name)
+		}
+		return result
+	}
+
+	It("drains all pods by default", func() {
+		pods := []corev1.Pod{
+			makePod("plain", nil),
+			makePod("emptydir", withEmptyDir),
+			makePod("unmanaged", func(p *corev1.Pod) { p.OwnerReferences = nil }),
+		}
+		var filter PodFilter
+		result, err := filter.Apply(pods)
+		Expect(err).To(Succeed())
+		Expect(names(result.Drain)).To(HaveExactElements("plain", "emptydir", "unmanaged"))
+		Expect(result.Skipped).To(BeEmpty())
+		Expect(result.Blocking).To(BeEmpty())
+	})
+
+	It("always skips annotated pods", func() {
+		pods := []corev1.Pod{
+			makePod("plain", nil),
+			makePod("optout", func(p *corev1.Pod) {
+				p.Annotations = map[string]string{constants.DrainSkipAnnotationKey: constants.TrueStr}
+			}),
+		}
+		var filter PodFilter
+		result, err := filter.Apply(pods)
+		Expect(err).To(Succeed())
+		Expect(names(result.Drain)).To(HaveExactElements("plain"))
+		Expect(result.Skipped).To(HaveLen(1))
+		Expect(result.Skipped[0].Pod.Name).To(Equal("optout"))
+	})
+
+	It("skips pods by namespace, selector and phase", func() {
+		pods := []corev1.Pod{
+			makePod("plain", nil),
+			makePod("namespaced", func(p *corev1.Pod) { p.Namespace = "kube-system" }),
+			makePod("selected", func(p *corev1.Pod) { p.Labels = map[string]string{"app": "agent"} }),
+			makePod("completed", func(p *corev1.Pod) { p.Status.Phase = corev1.PodSucceeded }),
+		}
+		filter := PodFilter{
+			SkipNamespaces: []string{"kube-system"},
+			SkipSelector:   "app=agent",
+			SkipCompleted:  true,
+		}
+		result, err := filter.Apply(pods)
+		Expect(err).To(Succeed())
+		Expect(names(result.Drain)).To(HaveExactElements("plain"))
+		Expect(result.Skipped).To(HaveLen(3))
+	})
+
+	It("skips or refuses pods with emptyDir volumes", func() {
+		pods := []corev1.Pod{makePod("plain", nil), makePod("emptydir", withEmptyDir)}
+		filter := PodFilter{EmptyDir: EmptyDirSkip}
+		result, err := filter.Apply(pods)
+		Expect(err).To(Succeed())
+		Expect(names(result.Drain)).To(HaveExactElements("plain"))
+		Expect(result.Skipped).To(HaveLen(1))
+
+		filter = PodFilter{EmptyDir: EmptyDirRefuse}
+		result, err = filter.Apply(pods)
+		Expect(err).To(Succeed())
+		Expect(names(result.Drain)).To(HaveExactElements("plain"))
+		Expect(result.Blocking).To(HaveLen(1))
+		Expect(result.Blocking[0].Pod.Name).To(Equal("emptydir"))
+	})
+
+	It("refuses unmanaged pods", func() {
+		pods := []corev1.Pod{
+			makePod("plain", nil),
+			makePod("unmanaged", func(p *corev1.Pod) { p.OwnerReferences = nil }),
+		}
+		filter := PodFilter{RefuseUnmanaged: true}
+		result, err := filter.Apply(pods)
+		Expect(err).To(Succeed())
+		Expect(names(result.Drain)).To(HaveExactElements("plain"))
+		Expect(result.Blocking).To(HaveLen(1))
+		Expect(result.Blocking[0].Pod.Name).To(Equal("unmanaged"))
+	})
+
+	It("validates its configuration", func() {
+		Expect((&PodFilter{EmptyDir: "keep"}).Validate()).ToNot(Succeed())
+		Expect((&PodFilter{SkipSelector: "a in (b"}).Validate()).ToNot(Succeed())
+		Expect((&PodFilter{EmptyDir: EmptyDirRefuse, SkipSelector: "a=b"}).Validate()).To(Succeed())
+	})
+
+})
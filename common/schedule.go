@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/blang/semver/v4"
@@ -44,20 +45,6 @@ func fetchEvictionVersion(ki kubernetes.Interface) (evictionVersion, error) {
 }
 
 // Checks if the is Schedulable according to schedulable and patches the node if necessary.
-func EnsureSchedulable(ctx context.Context, k8sClient client.Client, node *corev1.Node, schedulable bool) error {
-	// If node already has the correct value
-	if node.Spec.Unschedulable != schedulable {
-		return nil
-	}
-	cloned := node.DeepCopy()
-	node.Spec.Unschedulable = !schedulable
-	err := k8sClient.Patch(ctx, node, client.MergeFrom(cloned))
-	if err != nil {
-		return fmt.Errorf("failed to set node %v as (un-)schedulable: %w", node.Name, err)
-	}
-	return nil
-}
-
 type DrainParameters struct {
 	// how long to wait for pods to vanish
 	AwaitDeletion WaitParameters
@@ -73,25 +60,84 @@ type DrainParameters struct {
 	GracePeriodSeconds *int64
 	// optional recorder to emit events on evicted pods; paired with the node passed to EnsureDrain
 	Recorder events.EventRecorder
+	// selects the pods, which are evicted
+	Filter PodFilter
+	// defines the batches in which pods are evicted
+	Order DrainOrder
+	// optional progress, which is carried over between invocations of EnsureDrain and updated by it.
+	// Without it, each invocation is considered a new drain.
+	Progress *DrainProgress
 }
 
-func recordDrainEvent(recorder events.EventRecorder, pod *corev1.Pod, node *corev1.Node, reason, note string) {
+func recordDrainEvent(recorder events.EventRecorder, pod *corev1.Pod, node *corev1.Node, eventType, reason, note string) {
 	if recorder == nil {
 		return
 	}
-	recorder.Eventf(pod, node, corev1.EventTypeNormal, reason, "Drain", note)
+	recorder.Eventf(pod, node, eventType, reason, "Drain", note)
+}
+
+// FilterPodsForDrain applies the PodFilter of the given parameters to the pods returned by GetPodsForDrain
+// and emits events for skipped and blocking pods. Events are emitted once per drain and pod,
+// unless the reason of the pod changes, if the parameters carry a progress.
+func FilterPodsForDrain(ctx context.Context, node *corev1.Node, params *DrainParameters) (PodFilterResult, error) {
+	pods, err := GetPodsForDrain(ctx, params.Client, node.Name)
+	if err != nil {
+		return PodFilterResult{}, fmt.Errorf("failed to fetch deletable pods: %w", err)
+	}
+	result, err := params.Filter.Apply(pods)
+	if err != nil {
+		return PodFilterResult{}, err
+	}
+	unreported := func(filtered *FilteredPod) bool {
+		return params.Progress == nil || params.Progress.report(&filtered.Pod, filtered.Reason)
+	}
+	for i := range result.Skipped {
+		skipped := &result.Skipped[i]
+		if unreported(skipped) {
+			recordDrainEvent(params.Recorder, &skipped.Pod, node, corev1.EventTypeNormal, "DrainSkipped", skipped.Reason)
+		}
+	}
+	for i := range result.Blocking {
+		blocking := &result.Blocking[i]
+		if unreported(blocking) {
+			recordDrainEvent(params.Recorder, &blocking.Pod, node, corev1.EventTypeWarning, "DrainBlocked", blocking.Reason)
+		}
+	}
+	return result, nil
 }
 
 func EnsureDrain(ctx context.Context, node *corev1.Node, log logr.Logger, params DrainParameters) (bool, error) {
 	checkReady(node, log)
-	pending, err := GetPodsForDrain(ctx, params.Client, node.Name)
+	if params.Progress == nil {
+		params.Progress = &DrainProgress{}
+	}
+	progress := params.Progress
+	progress.begin()
+	filtered, err := FilterPodsForDrain(ctx, node, &params)
 	if err != nil {
-		return false, fmt.Errorf("failed to fetch deletable pods: %w", err)
+		return false, err
 	}
-	active, terminating := splitDrainCandidates(pending)
-	if len(active) == 0 && len(terminating) == 0 {
+	progress.Remaining = podKeys(filtered.Drain)
+	progress.Blocked = nil
+	if len(filtered.Blocking) > 0 {
+		blocking := make([]string, 0, len(filtered.Blocking))
+		for _, pod := range filtered.Blocking {
+			blocking = append(blocking, fmt.Sprintf("%s/%s (%s)", pod.Pod.Namespace, pod.Pod.Name, pod.Reason))
+		}
+		return false, fmt.Errorf("drain of node %s is blocked by pods: %s", node.Name, strings.Join(blocking, ", "))
+	}
+	pending := filtered.Drain
+	if len(pending) == 0 {
+		progress.finish()
 		return true, nil
 	}
+	_, terminating := splitDrainCandidates(pending)
+	// only evict the pods of the first batch, which still has pods on the node
+	batch, err := params.Order.NextBatch(pending)
+	if err != nil {
+		return false, err
+	}
+	active, _ := splitDrainCandidates(batch)
 
 	// Check if terminating pods have exceeded their grace period and force delete them
 	if len(terminating) > 0 && params.ForceEviction {
@@ -110,41 +156,62 @@ func EnsureDrain(ctx context.Context, node *corev1.Node, log logr.Logger, params
 		if len(podsToForceDelete) > 0 {
 			log.Info("Force deleting pods that exceeded grace period", "count", len(podsToForceDelete), "node", node.Name)
 			for i := range podsToForceDelete {
-				recordDrainEvent(params.Recorder, &podsToForceDelete[i], node, "ForceDeleting", "Force deleting pod that exceeded grace period during node drain")
+				recordDrainEvent(params.Recorder, &podsToForceDelete[i], node, corev1.EventTypeNormal, "ForceDeleting", "Force deleting pod that exceeded grace period during node drain")
 			}
 			gracePeriodZero := int64(0)
-			err = deletePods(ctx, params.Client, podsToForceDelete, &gracePeriodZero)
+			_, err = deletePods(ctx, params.Client, podsToForceDelete, &gracePeriodZero)
+			progress.addForceDeleted(podsToForceDelete)
 			if err != nil {
 				log.Info("Force deletion had errors", "err", err)
 			}
 		}
 	}
 
+	if len(active) > 0 && params.Order.WaitForReplacement {
+		unavailable, err := awaitReplacements(ctx, params.Client, progress)
+		if err != nil {
+			log.Info("Failed to check availability of replacements", "err", err, "node", node.Name)
+		}
+		if len(unavailable) > 0 || err != nil {
+			log.Info("Waiting for replacements of evicted pods to become available.",
+				"workloads", unavailable, "node", node.Name)
+			return false, nil
+		}
+		trackReplacements(progress, active)
+	}
+
 	if len(active) > 0 {
 		version, err := fetchEvictionVersion(params.Clientset)
 		if err != nil {
 			return false, err
 		}
 		for i := range active {
-			recordDrainEvent(params.Recorder, &active[i], node, "Evicting", "Evicting pod during node drain")
+			recordDrainEvent(params.Recorder, &active[i], node, corev1.EventTypeNormal, "Evicting", "Evicting pod during node drain")
 		}
+		var evicted int
+		var rejected []corev1.Pod
 		if version == none {
 			log.Info("Going to delete pods from node.", "count", len(active), "node", node.Name)
-			err = deletePods(ctx, params.Client, active, params.GracePeriodSeconds)
+			evicted, err = deletePods(ctx, params.Client, active, params.GracePeriodSeconds)
 		} else {
 			log.Info("Going to evict pods from node.", "count", len(active), "node", node.Name)
-			err = evictPods(ctx, params.Clientset, active, version, params.GracePeriodSeconds)
+			evicted, rejected, err = evictPods(ctx, params.Clientset, active, version, params.GracePeriodSeconds)
+		}
+		progress.Evicted += evicted
+		if len(rejected) > 0 {
+			blocked, budgetErr := blockedByBudgets(ctx, params.Client, rejected)
+			if budgetErr != nil {
+				log.Info("Failed to determine pod disruption budgets of rejected evictions", "err", budgetErr, "node", node.Name)
+			}
+			progress.Blocked = blocked
 		}
 		if err != nil {
 			return false, fmt.Errorf("failed to delete/evict at least one pod: %w", err)
 		}
 	}
 
-	if len(pending) > 0 {
-		log.Info("Waiting for pods to terminate after eviction.", "count", len(pending), "node", node.Name)
-		return false, nil
-	}
-	return true, nil
+	log.Info("Waiting for pods to terminate after eviction.", "count", len(pending), "node", node.Name)
+	return false, nil
 }
 
 func checkReady(node *corev1.Node, log logr.Logger) {
@@ -198,35 +265,48 @@ func GetPodsForDrain(ctx context.Context, k8sClient client.Client, nodeName stri
 	return filtered, nil
 }
 
-func deletePods(ctx context.Context, k8sClient client.Client, pods []corev1.Pod, gracePeriodSeconds *int64) error {
+// deletePods deletes the given pods and returns how many deletions succeeded.
+func deletePods(ctx context.Context, k8sClient client.Client, pods []corev1.Pod, gracePeriodSeconds *int64) (int, error) {
 	var errs []error
+	deleted := 0
 	// Do not use a direct iteration variable loop due to implicit aliasing in for loops
 	for i := range pods {
 		pod := pods[i]
 		err := k8sClient.Delete(ctx, &pod, &client.DeleteOptions{GracePeriodSeconds: gracePeriodSeconds})
 		if err != nil && !k8serrors.IsNotFound(err) {
 			errs = append(errs, fmt.Errorf("failed to delete pod %s from node: %w", pod.Name, err))
+			continue
 		}
+		deleted++
 	}
-	return errors.Join(errs...)
+	return deleted, errors.Join(errs...)
 }
 
+// evictPods evicts the given pods and returns how many evictions succeeded
+// and the pods, which evictions have been rejected with 429 Too Many Requests, usually due to a PodDisruptionBudget.
 func evictPods(ctx context.Context, ki kubernetes.Interface, pods []corev1.Pod,
-	version evictionVersion, gracePeriodSeconds *int64) error {
+	version evictionVersion, gracePeriodSeconds *int64) (int, []corev1.Pod, error) {
 
 	if len(pods) == 0 {
-		return nil
+		return 0, nil, nil
 	}
 	var errs []error
+	evicted := 0
+	rejected := make([]corev1.Pod, 0)
 	// Do not use a direct iteration variable loop due to implicit aliasing in for loops
 	for i := range pods {
 		pod := pods[i]
 		err := evictPod(ctx, ki, pod, version, gracePeriodSeconds)
 		if err != nil && !k8serrors.IsNotFound(err) {
+			if k8serrors.IsTooManyRequests(err) {
+				rejected = append(rejected, pod)
+			}
 			errs = append(errs, fmt.Errorf("failed to evict pod %s: %w", pod.Name, err))
+			continue
 		}
+		evicted++
 	}
-	return errors.Join(errs...)
+	return evicted, rejected, errors.Join(errs...)
 }
 
 type WaitParameters struct {
@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"fmt"
+	"time"
+)
+
+// LoadTimezone loads the location of an IANA time zone name. An empty name refers to UTC.
+func LoadTimezone(name string) (*time.Location, error) {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %s: %w", name, err)
+	}
+	return location, nil
+}
+
+// InLocation converts the given time into the location. A nil location refers to UTC.
+func InLocation(t time.Time, location *time.Location) time.Time {
+	if location == nil {
+		return t.UTC()
+	}
+	return t.In(location)
+}
+
+// StartOfDay returns midnight of the day of the given time within its location.
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfDay returns midnight of the day following the given time within its location.
+// Due to daylight saving time a day may not last 24 hours.
+func EndOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, t.Location())
+}
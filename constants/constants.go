@@ -35,6 +35,56 @@ const (
 	// DataAnnotationKey is the full annotation key, to which the controller serializes internal data.
 	DataAnnotationKey string = "cloud.sap/maintenance-data"
 
+	// JobLabelKey is the full label key, which holds the name prefix of jobs created by the runJob plugin.
+	JobLabelKey string = "cloud.sap/maintenance-job"
+
+	// JobNodeAnnotationKey is the full annotation key, which holds the node a job created by the runJob plugin runs for.
+	JobNodeAnnotationKey string = "cloud.sap/maintenance-job-node"
+
+	// JobProfileAnnotationKey is the full annotation key, which holds the profile a job created by the runJob plugin runs for.
+	JobProfileAnnotationKey string = "cloud.sap/maintenance-job-profile"
+
+	// SmokeTestLabelKey is the full label key, which holds the name prefix of pods created by the smokeTest plugin.
+	SmokeTestLabelKey string = "cloud.sap/maintenance-smoke-test"
+
+	// SmokeTestNodeAnnotationKey is the full annotation key, which holds the node a smoke test pod runs on.
+	SmokeTestNodeAnnotationKey string = "cloud.sap/maintenance-smoke-test-node"
+
+	// DrainSkipAnnotationKey is the full annotation key, which excludes a pod from being evicted during drains, if set to "true".
+	DrainSkipAnnotationKey string = "cloud.sap/maintenance-drain-skip"
+
+	// TaintsAnnotationKey is the full annotation key, which lists the taints added by the alterTaint plugin as "key:effect".
+	TaintsAnnotationKey string = "cloud.sap/maintenance-taints"
+
+	// SilencesAnnotationKey is the full annotation key, which maps profiles to the ids of silences
+	// created by the alertmanagerSilence plugin as JSON.
+	SilencesAnnotationKey string = "cloud.sap/maintenance-silences"
+
+	// AlteredAnnotationKey is the full annotation key, which records the transitions, in which templated
+	// values of the alterLabel and alterAnnotation plugins have been applied, as JSON.
+	AlteredAnnotationKey string = "cloud.sap/maintenance-altered"
+
+	// CordonAnnotationKey is the full annotation key, which records who cordoned a node, why and when.
+	// Only nodes with a record owned by CordonOwner are uncordoned by the controller.
+	CordonAnnotationKey string = "cloud.sap/maintenance-cordon"
+
+	// ApprovalsAnnotationKey is the full annotation key, which maps profiles to the last approval decision
+	// made using the interactive messages of the slackThread plugin as JSON.
+	ApprovalsAnnotationKey string = "cloud.sap/maintenance-approvals"
+
+	// ContactAnnotationKey is the default annotation key on namespaces, which lists the contacts
+	// notified by the workloadOwners plugin about evictions of their workloads.
+	ContactAnnotationKey string = "cloud.sap/maintenance-contact"
+
+	// CordonOwner identifies cordons of the maintenance controller in the CordonAnnotationKey annotation.
+	CordonOwner string = "maintenance-controller"
+
+	// StateConditionType is the type of the node condition, which reflects the state of the most critical profile.
+	StateConditionType = "MaintenanceState"
+
+	// DrainConditionType is the type of the node condition, which reflects the progress of drains.
+	DrainConditionType = "MaintenanceDrain"
+
 	// ESX controller constants
 	// ConfigFilePath is the path to the configuration file.
 	EsxConfigFilePath string = "config/esx.yaml"
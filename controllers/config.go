@@ -4,10 +4,12 @@
 package controllers
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/sapcc/ucfgwrap"
 
+	"github.com/sapcc/maintenance-controller/common"
 	"github.com/sapcc/maintenance-controller/constants"
 	"github.com/sapcc/maintenance-controller/plugin"
 	"github.com/sapcc/maintenance-controller/plugin/impl"
@@ -43,6 +45,7 @@ type ConfigDescriptor struct {
 	Dashboard struct {
 		LabelFilter []string `config:"labelFilter"`
 	} `config:"dashboard"`
+	Calendars []common.CalendarDefinition `config:"calendars"`
 }
 
 // Config represents the controllers global configuration.
@@ -55,6 +58,8 @@ type Config struct {
 	Registry plugin.Registry
 	// Keys of labels to show on the dashboard
 	DashboardLabelFilter []string
+	// Named calendars shared across plugin instances
+	Calendars *common.Calendars
 }
 
 // LoadConfig (re-)initializes the config with values provided by the given ucfg.Config.
@@ -70,6 +75,14 @@ func LoadConfig(config *ucfgwrap.Config) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	calendars, err := common.NewCalendars(global.Calendars)
+	if err != nil {
+		return nil, err
+	}
+	err = validateCalendarRefs(&registry, calendars)
+	if err != nil {
+		return nil, err
+	}
 	profileMap, err := loadProfiles(global.Profiles, &registry)
 	if err != nil {
 		return nil, err
@@ -83,9 +96,39 @@ func LoadConfig(config *ucfgwrap.Config) (*Config, error) {
 		Profiles:             profileMap,
 		Registry:             registry,
 		DashboardLabelFilter: dashboardLabelFilter,
+		Calendars:            calendars,
 	}, nil
 }
 
+// validateCalendarRefs ensures that all calendars referenced by plugin instances are defined.
+func validateCalendarRefs(registry *plugin.Registry, calendars *common.Calendars) error {
+	refs := make(map[string][]string)
+	for name, instance := range registry.CheckInstances {
+		if referrer, ok := instance.Plugin.(plugin.CalendarReferrer); ok {
+			refs[name] = referrer.CalendarRefs()
+		}
+	}
+	for name, instance := range registry.TriggerInstances {
+		if referrer, ok := instance.Plugin.(plugin.CalendarReferrer); ok {
+			refs[name] = referrer.CalendarRefs()
+		}
+	}
+	for name, instance := range registry.NotificationInstances {
+		refs[name] = instance.Calendars
+		if referrer, ok := instance.Plugin.(plugin.CalendarReferrer); ok {
+			refs[name] = append(refs[name], referrer.CalendarRefs()...)
+		}
+	}
+	for instance, names := range refs {
+		for _, name := range names {
+			if !calendars.Has(name) {
+				return fmt.Errorf("instance %s references the undefined calendar %s", instance, name)
+			}
+		}
+	}
+	return nil
+}
+
 func loadProfiles(profiles []ProfileDescriptor, registry *plugin.Registry) (map[string]state.Profile, error) {
 	profileMap := make(map[string]state.Profile)
 	// add an empty default profile
@@ -110,6 +153,10 @@ func loadProfiles(profiles []ProfileDescriptor, registry *plugin.Registry) (map[
 		if err != nil {
 			return nil, err
 		}
+		stateChange := stateChangeChain(operationalChain, requiredChain, maintenanceChain)
+		operationalChain.StateChange = stateChange
+		requiredChain.StateChange = stateChange
+		maintenanceChain.StateChange = stateChange
 		profileMap[profile.Name] = state.Profile{
 			Name: profile.Name,
 			Chains: map[state.NodeStateLabel]state.PluginChains{
@@ -122,6 +169,26 @@ func loadProfiles(profiles []ProfileDescriptor, registry *plugin.Registry) (map[
 	return profileMap, nil
 }
 
+// stateChangeChain collects the notification instances of all states, which react to state changes.
+// Each instance is contained once, even if it is used in multiple states.
+func stateChangeChain(chains ...state.PluginChains) plugin.NotificationChain {
+	var stateChange plugin.NotificationChain
+	seen := make(map[string]struct{})
+	for _, chain := range chains {
+		for _, instance := range chain.Notification.Plugins {
+			if _, ok := instance.Plugin.(plugin.StateChangeNotifier); !ok {
+				continue
+			}
+			if _, ok := seen[instance.Name]; ok {
+				continue
+			}
+			seen[instance.Name] = struct{}{}
+			stateChange.Plugins = append(stateChange.Plugins, instance)
+		}
+	}
+	return stateChange
+}
+
 func loadPluginChains(config StateDescriptor, registry *plugin.Registry) (state.PluginChains, error) {
 	var chains state.PluginChains
 	notificationChain, err := registry.NewNotificationChain(config.Notify)
@@ -163,17 +230,25 @@ func loadPluginChains(config StateDescriptor, registry *plugin.Registry) (state.
 func addPluginsToRegistry(registry *plugin.Registry) {
 	checkers := []plugin.Checker{
 		&impl.Affinity{},
+		&impl.AlertmanagerAlerts{},
 		&impl.AnyLabel{},
+		&impl.CalendarWindow{},
 		&impl.CheckHypervisor{},
 		&impl.ClusterSemver{},
 		&impl.Condition{},
+		&impl.Drainable{},
 		&impl.HasAnnotation{},
 		&impl.HasLabel{},
+		&impl.HasTaint{},
 		&impl.HypervisorCondition{},
+		&impl.JobSucceeded{},
 		&impl.KubernikusCount{},
 		&impl.MaxMaintenance{},
 		&impl.NodeCount{},
 		&impl.PrometheusInstant{},
+		&impl.PrometheusRange{},
+		&impl.Schedulable{},
+		&impl.SmokeTest{},
 		&impl.Stagger{},
 		&impl.TimeWindow{},
 		&impl.Wait{},
@@ -183,17 +258,31 @@ func addPluginsToRegistry(registry *plugin.Registry) {
 		registry.CheckPlugins[checker.ID()] = checker
 	}
 
-	notifiers := []plugin.Notifier{&impl.Mail{}, &impl.SlackThread{}, &impl.SlackWebhook{}}
+	notifiers := []plugin.Notifier{
+		&impl.AlertmanagerNotifier{},
+		&impl.Digest{},
+		&impl.Incident{},
+		&impl.Mail{},
+		&impl.SlackThread{},
+		&impl.SlackWebhook{},
+		&impl.WebhookNotifier{},
+		&impl.WorkloadOwners{},
+	}
 	for _, notifier := range notifiers {
 		registry.NotificationPlugins[notifier.ID()] = notifier
 	}
 
 	triggers := []plugin.Trigger{
+		&impl.AlertmanagerSilence{},
 		&impl.AlterAnnotation{},
+		&impl.AlterCondition{},
 		&impl.AlterFinalizer{},
 		&impl.AlterHypervisor{},
 		&impl.AlterLabel{},
+		&impl.AlterTaint{},
 		&impl.Eviction{},
+		&impl.RunJob{},
+		&impl.Webhook{},
 	}
 	for _, trigger := range triggers {
 		registry.TriggerPlugins[trigger.ID()] = trigger
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/elastic/go-ucfg"
+	"github.com/go-logr/logr"
+	"github.com/sapcc/ucfgwrap"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sapcc/maintenance-controller/constants"
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+// FlushRunnable periodically flushes the notification instances, which aggregate the notifications
+// of many nodes, so their messages are sent even if no node is notified anymore.
+type FlushRunnable struct {
+	client.Client
+	Log      logr.Logger
+	Interval time.Duration
+}
+
+func (fr *FlushRunnable) NeedLeaderElection() bool {
+	return true
+}
+
+func (fr *FlushRunnable) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, fr.Flush, fr.Interval)
+	return nil
+}
+
+// Flush loads the configuration and flushes each notification instance, which implements plugin.Flusher.
+func (fr *FlushRunnable) Flush(ctx context.Context) {
+	conf, err := ucfgwrap.FromYAMLFile(constants.MaintenanceConfigFilePath, ucfg.VarExp, ucfg.ResolveEnv)
+	if err != nil {
+		fr.Log.Error(err, "Failed to parse configuration file (syntax error)")
+		return
+	}
+	config, err := LoadConfig(&conf)
+	if err != nil {
+		fr.Log.Error(err, "Failed to parse configuration file (semantic error)")
+		return
+	}
+	params := plugin.Parameters{Ctx: ctx, Client: fr.Client, Log: fr.Log}
+	for name, instance := range config.Registry.NotificationInstances {
+		flusher, ok := instance.Plugin.(plugin.Flusher)
+		if !ok {
+			continue
+		}
+		if err := flusher.Flush(params); err != nil {
+			fr.Log.Error(err, "Failed to flush notification instance", "instance", name)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"strconv"
 	"time"
 
@@ -29,6 +30,13 @@ import (
 	"github.com/sapcc/maintenance-controller/state"
 )
 
+const (
+	// limits how often the upcoming blackout periods shown on the dashboard are refreshed
+	blackoutRefreshInterval = time.Minute
+	// how far the dashboard looks ahead for blackout periods
+	blackoutHorizon = 30 * 24 * time.Hour
+)
+
 // NodeReconciler reconciles a Node object.
 type NodeReconciler struct {
 	client.Client
@@ -37,6 +45,7 @@ type NodeReconciler struct {
 	Scheme        *runtime.Scheme
 	Recorder      events.EventRecorder
 	NodeInfoCache cache.NodeInfoCache
+	BlackoutCache cache.BlackoutCache
 }
 
 type reconcileParameters struct {
@@ -51,8 +60,13 @@ type reconcileParameters struct {
 
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=nodes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;create;update
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;update;patch
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments;replicasets;statefulsets,verbs=get;list;watch
 
 // Reconcile reconciles the given request.
 func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -69,6 +83,7 @@ func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		// the controller is misconfigured, no need to requeue before the configuration is fixed
 		return ctrl.Result{}, nil
 	}
+	r.updateBlackouts(ctx, config)
 
 	// fetch the current node from the api server
 	var theNode corev1.Node
@@ -87,6 +102,7 @@ func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	err = reconcileInternal(ctx, r.makeParams(config, &theNode))
 	if err != nil {
 		r.Log.Error(err, "Failed to reconcile. Skipping node patching.", "node", req.NamespacedName)
+		r.patchMessages(ctx, unmodifiedNode, &theNode)
 		return ctrl.Result{RequeueAfter: config.RequeueInterval}, nil
 	}
 
@@ -95,23 +111,69 @@ func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return ctrl.Result{RequeueAfter: config.RequeueInterval}, nil
 	}
 
+	// the patch below replaces theNode with the response, so keep the modified status
+	statusNode := theNode.DeepCopy()
+
 	// patch node
 	err = r.Patch(ctx, &theNode, client.MergeFrom(unmodifiedNode))
 	if err != nil {
 		r.Log.Error(err, "Failed to patch node on the API server", "node", req.NamespacedName)
 	}
+	// the cache needs to catch up with the latest patch
+	targetVersion := theNode.ResourceVersion
+
+	// conditions are part of the status subresource, a strategic merge patch merges them by type
+	// and does not overwrite conditions maintained by the kubelet
+	if !equality.Semantic.DeepEqual(statusNode.Status.Conditions, unmodifiedNode.Status.Conditions) {
+		err = r.Status().Patch(ctx, statusNode, client.StrategicMergeFrom(unmodifiedNode))
+		if err != nil {
+			r.Log.Error(err, "Failed to patch node status on the API server", "node", req.NamespacedName)
+		} else {
+			targetVersion = statusNode.ResourceVersion
+		}
+	}
 
 	// await cache update
 	err = pollCacheUpdate(ctx, r.Client, types.NamespacedName{
 		Name:      theNode.Name,
 		Namespace: theNode.Namespace,
-	}, theNode.ResourceVersion)
+	}, targetVersion)
 	if err != nil {
 		r.Log.Error(err, "Failed to poll for cache update")
 	}
 	return ctrl.Result{RequeueAfter: config.RequeueInterval}, nil
 }
 
+// Persists the data annotation of a node, which failed to reconcile, if it has been changed to keep track
+// of messages posted in the meantime. Otherwise these messages would be posted again.
+func (r *NodeReconciler) patchMessages(ctx context.Context, unmodifiedNode, node *corev1.Node) {
+	dataStr, ok := node.Annotations[constants.DataAnnotationKey]
+	if !ok || dataStr == unmodifiedNode.Annotations[constants.DataAnnotationKey] {
+		return
+	}
+	patched := unmodifiedNode.DeepCopy()
+	if patched.Annotations == nil {
+		patched.Annotations = make(map[string]string)
+	}
+	patched.Annotations[constants.DataAnnotationKey] = dataStr
+	if err := r.Patch(ctx, patched, client.MergeFrom(unmodifiedNode)); err != nil {
+		r.Log.Error(err, "Failed to patch message references on the API server", "node", node.Name)
+	}
+}
+
+// Refreshes the upcoming blackout periods shown on the dashboard, if they are outdated.
+func (r *NodeReconciler) updateBlackouts(ctx context.Context, config *Config) {
+	if r.BlackoutCache == nil || time.Since(r.BlackoutCache.Updated()) < blackoutRefreshInterval {
+		return
+	}
+	now := time.Now().UTC()
+	periods, err := config.Calendars.Periods(ctx, r.Client, config.Calendars.Names(), now, now.Add(blackoutHorizon))
+	if err != nil {
+		r.Log.Error(err, "Failed to determine upcoming blackout periods")
+	}
+	r.BlackoutCache.Update(periods)
+}
+
 func (r *NodeReconciler) makeParams(config *Config, node *corev1.Node) reconcileParameters {
 	return reconcileParameters{
 		client:        r.Client,
@@ -151,11 +213,28 @@ func reconcileInternal(ctx context.Context, params reconcileParameters) error {
 	}
 	err = HandleNode(ctx, params, &data)
 	if err != nil {
+		if keepErr := keepMessages(params.node, dataStr, data.Messages); keepErr != nil {
+			params.log.Error(keepErr, "Failed to keep track of posted messages")
+		}
 		return err
 	}
 	return writeData(params.node, data)
 }
 
+// keepMessages records the given message references in the data annotation of the node,
+// which is otherwise left as it was before a failed reconciliation.
+func keepMessages(node *corev1.Node, dataStr string, messages map[string]string) error {
+	data, err := state.ParseData(dataStr)
+	if err != nil {
+		return err
+	}
+	if maps.Equal(data.Messages, messages) {
+		return nil
+	}
+	data.Messages = messages
+	return writeData(node, data)
+}
+
 func writeData(node *corev1.Node, data state.Data) error {
 	dataBytes, err := json.Marshal(&data)
 	if err != nil {
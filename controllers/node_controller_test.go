@@ -251,6 +251,72 @@ var _ = Describe("The controller", func() {
 		Expect(maintenance.Enter.Plugins).To(HaveLen(1))
 	})
 
+	It("should reject references to undefined calendars", func() {
+		configStr := `
+intervals:
+  requeue: 1m
+instances:
+  check:
+  - type: wait
+    name: wait
+    config:
+      duration: 1h
+      calendars: [holidays]
+`
+		conf, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		_, err = LoadConfig(&conf)
+		Expect(err).To(HaveOccurred())
+
+		conf, err = ucfgwrap.FromYAML([]byte(configStr + "calendars:\n- name: holidays\n  holidays: [\"Dec 24\"]\n"))
+		Expect(err).To(Succeed())
+		loaded, err := LoadConfig(&conf)
+		Expect(err).To(Succeed())
+		Expect(loaded.Calendars.Has("holidays")).To(BeTrue())
+	})
+
+	It("should collect the state change notifiers of all states of a profile", func() {
+		configStr := `
+intervals:
+  requeue: 1m
+instances:
+  notify:
+  - type: incident
+    name: page
+    config:
+      routingKey: key
+    schedule:
+      type: periodic
+      config:
+        interval: 1h
+  - type: slack
+    name: chat
+    config:
+      hook: http://slack
+      channel: "#maintenance"
+      message: hello
+    schedule:
+      type: periodic
+      config:
+        interval: 1h
+profiles:
+- name: paging
+  maintenance-required:
+    notify: page && chat
+  in-maintenance:
+    notify: page
+`
+		conf, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		loaded, err := LoadConfig(&conf)
+		Expect(err).To(Succeed())
+		for _, label := range []state.NodeStateLabel{state.Operational, state.Required, state.InMaintenance} {
+			stateChange := loaded.Profiles["paging"].Chains[label].StateChange
+			Expect(stateChange.Plugins).To(HaveLen(1))
+			Expect(stateChange.Plugins[0].Name).To(Equal("page"))
+		}
+	})
+
 })
 
 var _ = Describe("The MaxMaintenance plugin", func() {
@@ -1178,14 +1244,32 @@ var _ = Describe("The eviction plugin", func() {
 	})
 
 	It("should mark a node as schedulable with uncordon action", func(ctx SpecContext) {
+		eviction := impl.Eviction{Action: impl.Cordon}
+		err := eviction.Trigger(plugin.Parameters{Ctx: ctx, Client: k8sClient, Node: node})
+		Expect(err).To(Succeed())
+		Expect(node.Annotations).To(HaveKey(constants.CordonAnnotationKey))
+
+		eviction = impl.Eviction{Action: impl.Uncordon}
+		err = eviction.Trigger(plugin.Parameters{Ctx: ctx, Client: k8sClient, Node: node})
+		Expect(err).To(Succeed())
+		Expect(node.Spec.Unschedulable).To(BeFalse())
+		Expect(node.Annotations).ToNot(HaveKey(constants.CordonAnnotationKey))
+	})
+
+	It("should not uncordon a node cordoned by someone else", func(ctx SpecContext) {
 		originalNode := node.DeepCopy()
 		node.Spec.Unschedulable = true
 		Expect(k8sClient.Patch(ctx, node, client.MergeFrom(originalNode))).To(Succeed())
 
-		eviction := impl.Eviction{Action: impl.Uncordon}
+		eviction := impl.Eviction{Action: impl.Cordon}
 		err := eviction.Trigger(plugin.Parameters{Ctx: ctx, Client: k8sClient, Node: node})
 		Expect(err).To(Succeed())
-		Expect(node.Spec.Unschedulable).To(BeFalse())
+		Expect(node.Annotations).ToNot(HaveKey(constants.CordonAnnotationKey))
+
+		eviction = impl.Eviction{Action: impl.Uncordon}
+		err = eviction.Trigger(plugin.Parameters{Ctx: ctx, Client: k8sClient, Node: node, Log: GinkgoLogr})
+		Expect(err).To(Succeed())
+		Expect(node.Spec.Unschedulable).To(BeTrue())
 	})
 
 	It("should evict pods with the drain action", func(ctx SpecContext) {
@@ -1231,3 +1315,20 @@ var _ = Describe("The eviction plugin", func() {
 		}).Should(BeTrue())
 	})
 })
+
+var _ = Describe("keepMessages", func() {
+
+	It("records posted messages in the data left as before the failed reconciliation", func() {
+		node := &corev1.Node{}
+		dataStr := `{"Profiles":{"p":{"Current":"maintenance-required"}}}`
+		Expect(keepMessages(node, dataStr, map[string]string{})).To(Succeed())
+		Expect(node.Annotations).ToNot(HaveKey(constants.DataAnnotationKey))
+
+		Expect(keepMessages(node, dataStr, map[string]string{"slack/live/#channel/p": "C123/1.2"})).To(Succeed())
+		data, err := state.ParseData(node.Annotations[constants.DataAnnotationKey])
+		Expect(err).To(Succeed())
+		Expect(data.Messages).To(HaveKeyWithValue("slack/live/#channel/p", "C123/1.2"))
+		Expect(data.Profiles).To(HaveKey("p"))
+		Expect(data.Profiles["p"].Current).To(Equal(state.Required))
+	})
+})
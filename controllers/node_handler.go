@@ -9,6 +9,9 @@ import (
 	"fmt"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/sapcc/maintenance-controller/common"
 	"github.com/sapcc/maintenance-controller/constants"
 	"github.com/sapcc/maintenance-controller/metrics"
 	"github.com/sapcc/maintenance-controller/plugin"
@@ -22,6 +25,8 @@ var handlers []NodeHandler = []NodeHandler{
 	MaintainProfileStates,
 	ApplyProfiles,
 	UpdateMaintenanceStateLabel,
+	UpdateMaintenanceStateCondition,
+	UpdateDrainCondition,
 }
 
 func HandleNode(ctx context.Context, params reconcileParameters, data *state.Data) error {
@@ -53,6 +58,12 @@ func ApplyProfiles(ctx context.Context, params reconcileParameters, data *state.
 	profileStates := data.GetProfilesWithState(profilesStr, params.config.Profiles)
 	profileResults, errs := make([]state.ProfileResult, 0), make([]error, 0)
 	profilesWithRetryError := make(map[string]struct{})
+	if data.Drain == nil {
+		data.Drain = &common.DrainProgress{}
+	}
+	if data.Messages == nil {
+		data.Messages = make(map[string]string)
+	}
 
 	for _, ps := range profileStates {
 		err := metrics.TouchShuffles(ctx, params.client, params.node, ps.Profile.Name)
@@ -71,7 +82,9 @@ func ApplyProfiles(ctx context.Context, params reconcileParameters, data *state.
 		pluginParams := plugin.Parameters{Client: params.client, Clientset: params.clientset, Ctx: ctx,
 			Log: params.log, Profile: ps.Profile.Name, Node: params.node, InMaintenance: anyInMaintenance(profileStates),
 			State: string(ps.State), LastTransition: data.Profiles[ps.Profile.Name].Transition,
-			Recorder: params.recorder, LogDetails: logDetails}
+			Recorder: params.recorder, LogDetails: logDetails, Drain: data.Drain,
+			Calendars: params.config.Calendars, Messages: data.Messages,
+			LastCheckResults: params.nodeInfoCache.CheckResults(params.node.Name, ps.Profile.Name)}
 
 		applied, err := state.Apply(stateObj, params.node, data, pluginParams)
 		profileResults = append(profileResults, state.ProfileResult{
@@ -89,10 +102,15 @@ func ApplyProfiles(ctx context.Context, params reconcileParameters, data *state.
 			errs = append(errs, err)
 		}
 	}
+	// do not persist drain progress, if the node has never been drained
+	if data.Drain.Started.IsZero() {
+		data.Drain = nil
+	}
 	params.nodeInfoCache.Update(state.NodeInfo{
 		Node:     params.node.Name,
 		Profiles: profileResults,
 		Labels:   filterNodeLabels(params.node.Labels, params.config.DashboardLabelFilter),
+		Drain:    data.Drain,
 	})
 	if len(errs) > 0 {
 		return fmt.Errorf("failed to apply current state: %w", errors.Join(errs...))
@@ -109,6 +127,12 @@ func ApplyProfiles(ctx context.Context, params reconcileParameters, data *state.
 		}
 		data.Profiles[ps.Profile.Name].Previous = result.State
 	}
+	// drains are continued by retrying the eviction trigger, so nothing continues a drain in progress,
+	// if no profile retries and none is in maintenance anymore
+	if data.Drain != nil && len(profilesWithRetryError) == 0 &&
+		!anyInMaintenance(data.GetProfilesWithState(profilesStr, params.config.Profiles)) {
+		data.Drain.Abort()
+	}
 	return nil
 }
 
@@ -133,23 +157,83 @@ func filterNodeLabels(nodeLabels map[string]string, keys []string) map[string]st
 }
 
 func UpdateMaintenanceStateLabel(ctx context.Context, params reconcileParameters, data *state.Data) error {
-	profilesStr := params.node.Labels[constants.ProfileLabelKey]
-	profileStates := data.GetProfilesWithState(profilesStr, params.config.Profiles)
 	if params.node.Labels == nil {
 		params.node.Labels = make(map[string]string)
 	}
-	for _, ps := range profileStates {
+	if ps, ok := mostCriticalProfile(params, data); ok {
+		params.node.Labels[constants.StateLabelKey] = string(ps.State)
+		return nil
+	}
+	params.node.Labels[constants.StateLabelKey] = string(state.Operational)
+	return nil
+}
+
+// mostCriticalProfile returns the first profile in-maintenance or otherwise the first profile requiring maintenance.
+// If all profiles are operational false is returned.
+func mostCriticalProfile(params reconcileParameters, data *state.Data) (state.ProfileState, bool) {
+	profilesStr := params.node.Labels[constants.ProfileLabelKey]
+	profileStates := data.GetProfilesWithState(profilesStr, params.config.Profiles)
+	for _, critical := range []state.NodeStateLabel{state.InMaintenance, state.Required} {
+		for _, ps := range profileStates {
+			if ps.State == critical {
+				return ps, true
+			}
+		}
+	}
+	return state.ProfileState{}, false
+}
+
+// UpdateMaintenanceStateCondition reflects the state of the most critical profile in the MaintenanceState node condition.
+func UpdateMaintenanceStateCondition(ctx context.Context, params reconcileParameters, data *state.Data) error {
+	condition := corev1.NodeCondition{
+		Type:    constants.StateConditionType,
+		Status:  corev1.ConditionFalse,
+		Reason:  "Operational",
+		Message: "All maintenance profiles are operational",
+	}
+	if ps, ok := mostCriticalProfile(params, data); ok {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "MaintenanceRequired"
 		if ps.State == state.InMaintenance {
-			params.node.Labels[constants.StateLabelKey] = string(ps.State)
-			return nil
+			condition.Reason = "InMaintenance"
+		}
+		condition.Message = fmt.Sprintf("Profile %s is in state %s", ps.Profile.Name, ps.State)
+		if profileData, ok := data.Profiles[ps.Profile.Name]; ok && !profileData.Transition.IsZero() {
+			condition.Message += " since " + profileData.Transition.Format(time.RFC3339)
 		}
 	}
-	for _, ps := range profileStates {
-		if ps.State == state.Required {
-			params.node.Labels[constants.StateLabelKey] = string(ps.State)
-			return nil
+	common.SetNodeCondition(params.node, condition)
+	return nil
+}
+
+// UpdateDrainCondition reflects the drain progress in the MaintenanceDrain node condition.
+func UpdateDrainCondition(ctx context.Context, params reconcileParameters, data *state.Data) error {
+	if data.Drain == nil {
+		return nil
+	}
+	condition := corev1.NodeCondition{
+		Type:   constants.DrainConditionType,
+		Status: corev1.ConditionFalse,
+		Reason: "Drained",
+		Message: fmt.Sprintf("Drain finished after %v, %d pods evicted, %d pods force deleted",
+			data.Drain.Duration().Round(time.Second), data.Drain.Evicted, len(data.Drain.ForceDeleted)),
+	}
+	if data.Drain.InProgress() {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "Draining"
+		condition.Message = fmt.Sprintf("Drain running since %v, %d pods remaining",
+			data.Drain.Started.Format(time.RFC3339), len(data.Drain.Remaining))
+		if len(data.Drain.Blocked) > 0 {
+			condition.Reason = "DrainBlocked"
+			condition.Message = fmt.Sprintf("%s, %d pods blocked by pod disruption budgets",
+				condition.Message, len(data.Drain.Blocked))
 		}
 	}
-	params.node.Labels[constants.StateLabelKey] = string(state.Operational)
+	if data.Drain.Aborted {
+		condition.Reason = "DrainAborted"
+		condition.Message = fmt.Sprintf("Drain aborted after %v, %d pods evicted, %d pods force deleted",
+			data.Drain.Duration().Round(time.Second), data.Drain.Evicted, len(data.Drain.ForceDeleted))
+	}
+	common.SetNodeCondition(params.node, condition)
 	return nil
 }
@@ -4,8 +4,21 @@
 package controllers
 
 import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sapcc/maintenance-controller/cache"
+	"github.com/sapcc/maintenance-controller/common"
+	"github.com/sapcc/maintenance-controller/constants"
+	"github.com/sapcc/maintenance-controller/state"
 )
 
 var _ = Describe("filterNodeLabels", func() {
@@ -25,3 +38,132 @@ var _ = Describe("filterNodeLabels", func() {
 	})
 
 })
+
+var _ = Describe("UpdateDrainCondition", func() {
+
+	findCondition := func(node *corev1.Node) *corev1.NodeCondition {
+		for i := range node.Status.Conditions {
+			if node.Status.Conditions[i].Type == constants.DrainConditionType {
+				return &node.Status.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	It("does not add the condition to nodes, which have never been drained", func() {
+		node := &corev1.Node{}
+		Expect(UpdateDrainCondition(context.Background(), reconcileParameters{node: node}, &state.Data{})).To(Succeed())
+		Expect(node.Status.Conditions).To(BeEmpty())
+	})
+
+	It("reflects the progress of a drain", func() {
+		node := &corev1.Node{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+		}}}
+		data := state.Data{Drain: &common.DrainProgress{
+			Started:   time.Now().Add(-time.Minute),
+			Remaining: []string{"default/a", "default/b"},
+			Blocked:   []common.BlockedPod{{Pod: "default/a", PodDisruptionBudget: "default/pdb"}},
+		}}
+		params := reconcileParameters{node: node}
+		Expect(UpdateDrainCondition(context.Background(), params, &data)).To(Succeed())
+		Expect(node.Status.Conditions).To(HaveLen(2))
+		condition := findCondition(node)
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("DrainBlocked"))
+		transition := condition.LastTransitionTime
+
+		data.Drain.Finished = time.Now()
+		data.Drain.Remaining = nil
+		data.Drain.Blocked = nil
+		data.Drain.Evicted = 2
+		Expect(UpdateDrainCondition(context.Background(), params, &data)).To(Succeed())
+		Expect(node.Status.Conditions).To(HaveLen(2))
+		condition = findCondition(node)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("Drained"))
+		Expect(condition.Message).To(ContainSubstring("2 pods evicted"))
+		Expect(condition.LastTransitionTime.Before(&transition)).To(BeFalse())
+	})
+
+	It("reports aborted drains", func() {
+		node := &corev1.Node{}
+		data := state.Data{Drain: &common.DrainProgress{Started: time.Now().Add(-time.Minute), Evicted: 1}}
+		data.Drain.Abort()
+		Expect(UpdateDrainCondition(context.Background(), reconcileParameters{node: node}, &data)).To(Succeed())
+		condition := findCondition(node)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("DrainAborted"))
+		Expect(condition.Message).To(ContainSubstring("1 pods evicted"))
+	})
+
+})
+
+var _ = Describe("ApplyProfiles", func() {
+
+	It("aborts drains in progress once no profile is in maintenance", func() {
+		node := &corev1.Node{}
+		node.Name = "node"
+		node.Labels = map[string]string{constants.ProfileLabelKey: "first"}
+		params := reconcileParameters{
+			client: fake.NewClientBuilder().WithIndex(&corev1.Pod{}, "spec.nodeName", func(o client.Object) []string {
+				return []string{o.(*corev1.Pod).Spec.NodeName} //nolint:forcetypeassert
+			}).Build(),
+			config:        &Config{Profiles: map[string]state.Profile{"first": {Name: "first"}}},
+			log:           logr.Discard(),
+			recorder:      events.NewFakeRecorder(10),
+			node:          node,
+			nodeInfoCache: cache.NewNodeInfoCache(),
+		}
+		data := state.Data{
+			Profiles: map[string]*state.ProfileData{
+				"first": {Current: state.InMaintenance, Previous: state.InMaintenance},
+			},
+			Drain: &common.DrainProgress{Started: time.Now().Add(-time.Minute), Remaining: []string{"default/a"}},
+		}
+		Expect(ApplyProfiles(context.Background(), params, &data)).To(Succeed())
+		Expect(data.Drain.InProgress()).To(BeTrue())
+
+		data.Profiles["first"].Current = state.Operational
+		data.Profiles["first"].Previous = state.Operational
+		Expect(ApplyProfiles(context.Background(), params, &data)).To(Succeed())
+		Expect(data.Drain.InProgress()).To(BeFalse())
+		Expect(data.Drain.Aborted).To(BeTrue())
+		Expect(data.Drain.Remaining).To(BeEmpty())
+	})
+
+})
+
+var _ = Describe("UpdateMaintenanceStateCondition", func() {
+
+	It("reflects the most critical profile", func() {
+		node := &corev1.Node{}
+		node.Labels = map[string]string{constants.ProfileLabelKey: "first--second"}
+		params := reconcileParameters{
+			node: node,
+			config: &Config{Profiles: map[string]state.Profile{
+				"first":  {Name: "first"},
+				"second": {Name: "second"},
+			}},
+		}
+		data := state.Data{Profiles: map[string]*state.ProfileData{
+			"first":  {Current: state.Required},
+			"second": {Current: state.InMaintenance, Transition: time.Now()},
+		}}
+		Expect(UpdateMaintenanceStateCondition(context.Background(), params, &data)).To(Succeed())
+		Expect(node.Status.Conditions).To(HaveLen(1))
+		condition := node.Status.Conditions[0]
+		Expect(condition.Type).To(BeEquivalentTo(constants.StateConditionType))
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("InMaintenance"))
+		Expect(condition.Message).To(ContainSubstring("second"))
+
+		data.Profiles["first"].Current = state.Operational
+		data.Profiles["second"].Current = state.Operational
+		Expect(UpdateMaintenanceStateCondition(context.Background(), params, &data)).To(Succeed())
+		Expect(node.Status.Conditions).To(HaveLen(1))
+		Expect(node.Status.Conditions[0].Status).To(Equal(corev1.ConditionFalse))
+		Expect(node.Status.Conditions[0].Reason).To(Equal("Operational"))
+	})
+
+})
@@ -12,6 +12,8 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/vmware/govmomi"
+
+	"github.com/sapcc/maintenance-controller/common"
 )
 
 // Specifies the string in a vCenter URL, which is replaced by the availability zone.
@@ -37,8 +39,10 @@ type Config struct {
 			Timeout time.Duration `config:"timeout" validate:"required"`
 		} `config:"vmShutdown" validate:"required"`
 	} `config:"intervals" validate:"required"`
-	Alarms   []string
-	VCenters VCenters `config:"vCenters" validate:"required"`
+	Alarms     []string
+	VCenters   VCenters          `config:"vCenters" validate:"required"`
+	PodFilter  common.PodFilter  `config:"podFilter"`
+	DrainOrder common.DrainOrder `config:"drainOrder"`
 }
 
 func (c *Config) AlarmsAsSet() map[string]struct{} {
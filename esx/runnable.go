@@ -31,6 +31,8 @@ type Runnable struct {
 	Log      logr.Logger
 	Conf     *rest.Config
 	Recorder events.EventRecorder
+	// progress of drains, which have not finished yet
+	drains common.DrainProgresses
 }
 
 func (r *Runnable) NeedLeaderElection() bool {
@@ -161,7 +163,7 @@ func (r *Runnable) ShutdownNodes(ctx context.Context, conf *Config, esx *Host) e
 			r.Log.Error(err, "failed to annotate node", "node", node.Name)
 			continue
 		}
-		err = common.EnsureSchedulable(ctx, r.Client, node, false)
+		err = common.EnsureCordoned(ctx, r.Client, node, "ESX maintenance")
 		if err != nil {
 			r.Log.Error(err, "Failed to cordon node.", "node", node.Name)
 			continue
@@ -181,6 +183,9 @@ func (r *Runnable) ShutdownNodes(ctx context.Context, conf *Config, esx *Host) e
 				ForceEviction:      conf.Intervals.PodEviction.Force,
 				GracePeriodSeconds: nodeGracePeriod(node),
 				Recorder:           r.Recorder,
+				Filter:             conf.PodFilter,
+				Order:              conf.DrainOrder,
+				Progress:           r.drains.Get(node.Name),
 			},
 		)
 		if err != nil {
@@ -191,6 +196,7 @@ func (r *Runnable) ShutdownNodes(ctx context.Context, conf *Config, esx *Host) e
 			r.Log.Info("Node is still not empty after drain. Will retry in next reconcile.", "node", node.Name)
 			continue
 		}
+		r.drains.Forget(node.Name)
 
 		r.Log.Info("Ensuring VM is shut off. Will shutdown if necessary.", "node", node.Name)
 		err = EnsureVMOff(ctx, ShutdownParams{
@@ -232,11 +238,18 @@ func (r *Runnable) StartNodes(ctx context.Context, vCenters *VCenters, esx *Host
 			r.Log.Error(err, "Failed to start VM.", "node", node.Name)
 			continue
 		}
-		err = common.EnsureSchedulable(ctx, r.Client, node, true)
+		uncordoned, reason, err := common.EnsureUncordoned(ctx, r.Client, node)
 		if err != nil {
 			r.Log.Error(err, "Failed to uncordon node.", "node", node.Name)
 			continue
 		}
+		if !uncordoned {
+			r.Log.Info("Skipped uncordoning node.", "node", node.Name, "reason", reason)
+			if r.Recorder != nil {
+				r.Recorder.Eventf(node, nil, v1.EventTypeNormal, "UncordonSkipped", "Uncordon",
+					"Skipped uncordon after ESX maintenance: %s", reason)
+			}
+		}
 		// ESX Maintenance is finished => delete annotation
 		err = r.deleteAnnotation(ctx, node, constants.EsxRebootInitiatedAnnotationKey)
 		if err != nil {
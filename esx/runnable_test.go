@@ -19,6 +19,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/sapcc/maintenance-controller/common"
 	"github.com/sapcc/maintenance-controller/constants"
 )
 
@@ -278,8 +279,10 @@ var _ = Describe("The ESX controller", func() {
 
 		markInitiated := func(node *corev1.Node) error {
 			cloned := node.DeepCopy()
-			node.Spec.Unschedulable = true
 			node.Annotations = map[string]string{constants.EsxRebootInitiatedAnnotationKey: constants.TrueStr}
+			if err := common.Cordon(node, "ESX maintenance"); err != nil {
+				return err
+			}
 			return k8sClient.Patch(ctx, node, client.MergeFrom(cloned))
 		}
 		Expect(markInitiated(firstNode)).To(Succeed())
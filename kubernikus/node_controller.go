@@ -50,6 +50,8 @@ type Config struct {
 		Name      string `config:"name"`
 		Namespace string `config:"namespace"`
 	} `config:"cloudProviderSecret"`
+	PodFilter  common.PodFilter  `config:"podFilter"`
+	DrainOrder common.DrainOrder `config:"drainOrder"`
 }
 
 func (r *NodeReconciler) loadConfig() (Config, error) {
@@ -74,6 +76,8 @@ type NodeReconciler struct {
 	Log      logr.Logger
 	Scheme   *runtime.Scheme
 	Recorder events.EventRecorder
+	// progress of drains, which have not finished yet
+	drains common.DrainProgresses
 }
 
 // Reconcile reconciles the given request.
@@ -122,6 +126,9 @@ func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 				},
 				ForceEviction: conf.Intervals.PodEviction.Force,
 				Recorder:      r.Recorder,
+				Filter:        conf.PodFilter,
+				Order:         conf.DrainOrder,
+				Progress:      r.drains.Get(node.Name),
 			},
 		)
 		if err != nil {
@@ -177,7 +184,7 @@ func getAPIServerVersion(conf *rest.Config) (semver.Version, error) {
 
 func (r *NodeReconciler) deleteNode(ctx context.Context, node *v1.Node, secretKey client.ObjectKey, params common.DrainParameters) error {
 	r.Log.Info("Cordoning, draining and deleting node", "node", node.Name)
-	err := common.EnsureSchedulable(ctx, r.Client, node, false)
+	err := common.EnsureCordoned(ctx, r.Client, node, "kubernikus node deletion")
 	// In case of error just retry, cordoning is ensured again
 	if err != nil {
 		return fmt.Errorf("failed to cordon node %s: %w", node.Name, err)
@@ -191,6 +198,7 @@ func (r *NodeReconciler) deleteNode(ctx context.Context, node *v1.Node, secretKe
 		r.Log.Info("Node drain still in progress; will continue in next reconcile", "node", node.Name)
 		return nil
 	}
+	r.drains.Forget(node.Name)
 	osConf, err := common.LoadOSConfig(ctx, r.Client, secretKey)
 	if err != nil {
 		return fmt.Errorf("failed to load OpenStack config: %w", err)
@@ -58,6 +58,7 @@ func init() {
 type reconcilerConfig struct {
 	metricsAddr                 string
 	metricsTimeout              time.Duration
+	slackSigningSecret          string
 	enableESXMaintenance        bool
 	enableKubernikusMaintenance bool
 }
@@ -79,6 +80,9 @@ func main() {
 		"Enables an additional controller, which will indicate outdated kubelets and enable VM deletions.")
 	flag.DurationVar(&reconcilerCfg.metricsTimeout, "metrics-timeout", 65*time.Second,
 		"Maximum delay between SIGTERM and actual shutdown to scrape metrics one last time.")
+	flag.StringVar(&reconcilerCfg.slackSigningSecret, "slack-signing-secret", os.Getenv("SLACK_SIGNING_SECRET"),
+		"Signing secret of the slack app, which enables the endpoint for approvals using slack messages. "+
+			"Defaults to the SLACK_SIGNING_SECRET environment variable.")
 	opts := zap.Options{
 		Development: true,
 		TimeEncoder: zapcore.ISO8601TimeEncoder,
@@ -109,7 +113,9 @@ func main() {
 				// The only secret lookup is the optional lookup in the
 				// Kubernikus controller. To allow scoping RBAC to secrets
 				// with a resourceName, the cache needs to be disabled.
-				DisableFor: []client.Object{&v1.Secret{}},
+				// ConfigMaps are only read to load calendars and digests,
+				// which does not justify a cluster-wide informer.
+				DisableFor: []client.Object{&v1.Secret{}, &v1.ConfigMap{}},
 			},
 		},
 	})
@@ -160,6 +166,7 @@ func setupChecks(mgr manager.Manager) {
 
 func setupReconcilers(mgr manager.Manager, cfg *reconcilerConfig) error {
 	nodeInfoCache := cache.NewNodeInfoCache()
+	blackoutCache := cache.NewBlackoutCache()
 	if err := (&controllers.NodeReconciler{
 		Client:        mgr.GetClient(),
 		Clientset:     kubernetes.NewForConfigOrDie(mgr.GetConfig()),
@@ -167,10 +174,20 @@ func setupReconcilers(mgr manager.Manager, cfg *reconcilerConfig) error {
 		Scheme:        mgr.GetScheme(),
 		Recorder:      mgr.GetEventRecorder("maintenance"),
 		NodeInfoCache: nodeInfoCache,
+		BlackoutCache: blackoutCache,
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("failed to setup maintenance controller node reconciler: %w", err)
 	}
 
+	flushRunnable := controllers.FlushRunnable{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("flush"),
+		Interval: time.Minute,
+	}
+	if err := mgr.Add(&flushRunnable); err != nil {
+		return fmt.Errorf("failed to setup flushing of notifications: %w", err)
+	}
+
 	// Required for affinity check plugin as well as kubernikus and ESX integration
 	err := mgr.GetFieldIndexer().IndexField(context.Background(),
 		&v1.Pod{},
@@ -191,8 +208,12 @@ func setupReconcilers(mgr manager.Manager, cfg *reconcilerConfig) error {
 		Log:           ctrl.Log.WithName("metrics"),
 		WaitTimeout:   cfg.metricsTimeout,
 		NodeInfoCache: nodeInfoCache,
+		BlackoutCache: blackoutCache,
 		Elected:       mgr.Elected(),
 		Client:        mgr.GetClient(),
+
+		SlackSigningSecret: cfg.slackSigningSecret,
+		Recorder:           mgr.GetEventRecorder("maintenance"),
 	}
 	if err := mgr.Add(&apiServer); err != nil {
 		return fmt.Errorf("failed to attach prometheus metrics server: %w", err)
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	appsv1 "k8s.io/api/apps/v1"
@@ -34,10 +35,21 @@ var (
 		Name: "maintenance_controller_transition_failure_count",
 		Help: "Count of failed state transition evaluations due to plugin errors",
 	}, []string{"profile"})
+
+	drainDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "maintenance_controller_drain_duration_seconds",
+		Help:    "Duration of completed node drains",
+		Buckets: prometheus.ExponentialBuckets(30, 2, 10),
+	}, []string{"profile"})
+
+	drainedPods = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "maintenance_controller_drain_pod_count",
+		Help: "Count of pods removed from nodes during drains by method, which is either evicted or force_deleted",
+	}, []string{"profile", "method"})
 )
 
 func RegisterMaintenanceMetrics() {
-	metrics.Registry.MustRegister(shuffleCount, shufflesPerReplica, transitionFailures)
+	metrics.Registry.MustRegister(shuffleCount, shufflesPerReplica, transitionFailures, drainDuration, drainedPods)
 }
 
 type shuffleRecord struct {
@@ -220,3 +232,12 @@ func makeLabels(owner, profile string) prometheus.Labels {
 func RecordTransitionFailure(profile string) {
 	transitionFailures.With(prometheus.Labels{"profile": profile}).Inc()
 }
+
+func RecordDrainDuration(profile string, duration time.Duration) {
+	drainDuration.With(prometheus.Labels{"profile": profile}).Observe(duration.Seconds())
+}
+
+// RecordDrainedPods increments the drained pod counter for the given method, either "evicted" or "force_deleted".
+func RecordDrainedPods(profile, method string, count int) {
+	drainedPods.With(prometheus.Labels{"profile": profile, "method": method}).Add(float64(count))
+}
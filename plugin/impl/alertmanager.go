@@ -0,0 +1,619 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sapcc/ucfgwrap"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/sapcc/maintenance-controller/constants"
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+// maxReportedAlerts bounds the number of alerts added to the check info.
+const maxReportedAlerts = 10
+
+// openEndedSilence is the length of silences without a configured duration, which are expected to be expired
+// by another instance. The alertmanager requires silences to end.
+const openEndedSilence = 365 * 24 * time.Hour
+
+var matcherRegex = regexp.MustCompile(`^\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*(=~|!~|!=|=)\s*(.*?)\s*$`)
+
+// AlertMatcher matches alerts by a label, it uses the same syntax as alertmanager matchers.
+type AlertMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+// ParseAlertMatcher parses a matcher like `severity="critical"`, `node!=node01` or `alertname=~"Node.*"`.
+func ParseAlertMatcher(matcher string) (AlertMatcher, error) {
+	groups := matcherRegex.FindStringSubmatch(matcher)
+	if groups == nil {
+		return AlertMatcher{}, fmt.Errorf("invalid matcher: %s", matcher)
+	}
+	value := groups[3]
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return AlertMatcher{}, fmt.Errorf("invalid matcher value in %s: %w", matcher, err)
+		}
+		value = unquoted
+	}
+	parsed := AlertMatcher{Name: groups[1], Value: value}
+	switch groups[2] {
+	case "=":
+		parsed.IsEqual = true
+	case "=~":
+		parsed.IsEqual, parsed.IsRegex = true, true
+	case "!~":
+		parsed.IsRegex = true
+	}
+	if parsed.IsRegex {
+		if _, err := regexp.Compile(parsed.Value); err != nil {
+			return AlertMatcher{}, fmt.Errorf("invalid regex in matcher %s: %w", matcher, err)
+		}
+	}
+	return parsed, nil
+}
+
+func (am AlertMatcher) String() string {
+	operator := "="
+	switch {
+	case am.IsRegex && am.IsEqual:
+		operator = "=~"
+	case am.IsRegex:
+		operator = "!~"
+	case !am.IsEqual:
+		operator = "!="
+	}
+	return am.Name + operator + strconv.Quote(am.Value)
+}
+
+// renderMatchers renders the given matchers as templates and parses them.
+func renderMatchers(matchers []string, params *plugin.Parameters) ([]AlertMatcher, error) {
+	parsed := make([]AlertMatcher, 0, len(matchers))
+	for _, matcher := range matchers {
+		rendered, err := plugin.RenderNotificationTemplate(matcher, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render matcher %s: %w", matcher, err)
+		}
+		alertMatcher, err := ParseAlertMatcher(rendered)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, alertMatcher)
+	}
+	return parsed, nil
+}
+
+// alertmanagerClient issues requests against the v2 API of an alertmanager.
+type alertmanagerClient struct {
+	URL     string
+	Auth    SecretAuth
+	Timeout time.Duration
+}
+
+func newAlertmanagerClient(rawURL string, timeout time.Duration, authConf authConfig) (alertmanagerClient, error) {
+	auth, err := authConf.toSecretAuth()
+	if err != nil {
+		return alertmanagerClient{}, err
+	}
+	if auth.Type == MTLSAuth {
+		return alertmanagerClient{}, errors.New("the alertmanager plugins do not support mtls auth")
+	}
+	return alertmanagerClient{URL: strings.TrimSuffix(rawURL, "/"), Auth: auth, Timeout: timeout}, nil
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response into out, if it is not nil.
+func (ac *alertmanagerClient) do(params *plugin.Parameters, method, path string, query url.Values,
+	body, out any) (int, error) {
+
+	ctx, cancel := context.WithTimeout(params.Ctx, ac.Timeout)
+	defer cancel()
+	target := ac.URL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if err := ac.Auth.Apply(params, req); err != nil {
+		return 0, err
+	}
+	rsp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request to alertmanager %s: %w", ac.URL, err)
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		// drain the body, so the connection can be reused
+		_, err = io.Copy(io.Discard, rsp.Body)
+		return rsp.StatusCode, errors.Join(fmt.Errorf("alertmanager %s returned status code %d for %s %s",
+			ac.URL, rsp.StatusCode, method, path), err)
+	}
+	if out == nil {
+		_, err = io.Copy(io.Discard, rsp.Body)
+		return rsp.StatusCode, err
+	}
+	if err := json.NewDecoder(rsp.Body).Decode(out); err != nil {
+		return rsp.StatusCode, fmt.Errorf("failed to decode response of alertmanager %s: %w", ac.URL, err)
+	}
+	return rsp.StatusCode, nil
+}
+
+// AlertmanagerAlerts is a check plugin that passes if no active alert, which is neither silenced
+// nor inhibited, matches all of the configured matchers.
+type AlertmanagerAlerts struct {
+	Client alertmanagerClient
+	// rendered as templates before being parsed
+	Matchers []string
+}
+
+// New creates a new AlertmanagerAlerts instance with the given config.
+func (aa *AlertmanagerAlerts) New(config *ucfgwrap.Config) (plugin.Checker, error) {
+	conf := struct {
+		URL      string        `config:"url" validate:"required"`
+		Timeout  time.Duration `config:"timeout"`
+		Auth     authConfig    `config:"auth"`
+		Matchers []string      `config:"matchers"`
+	}{Timeout: 10 * time.Second}
+	if err := config.Unpack(&conf); err != nil {
+		return nil, err
+	}
+	client, err := newAlertmanagerClient(conf.URL, conf.Timeout, conf.Auth)
+	if err != nil {
+		return nil, err
+	}
+	return &AlertmanagerAlerts{Client: client, Matchers: conf.Matchers}, nil
+}
+
+func (aa *AlertmanagerAlerts) ID() string {
+	return "alertmanagerAlerts"
+}
+
+// Check queries the alertmanager for matching alerts and fails if there are any.
+func (aa *AlertmanagerAlerts) Check(params plugin.Parameters) (plugin.CheckResult, error) {
+	info := map[string]any{"url": aa.Client.URL}
+	matchers, err := renderMatchers(aa.Matchers, &params)
+	if err != nil {
+		return plugin.Failed(info), err
+	}
+	query := url.Values{
+		"active":    []string{"true"},
+		"silenced":  []string{"false"},
+		"inhibited": []string{"false"},
+	}
+	filters := make([]string, 0, len(matchers))
+	for _, matcher := range matchers {
+		filters = append(filters, matcher.String())
+	}
+	query["filter"] = filters
+	info["matchers"] = filters
+	var alerts []struct {
+		Labels map[string]string `json:"labels"`
+	}
+	if _, err := aa.Client.do(&params, http.MethodGet, "/api/v2/alerts", query, nil, &alerts); err != nil {
+		return plugin.Failed(info), err
+	}
+	info["alerts"] = len(alerts)
+	if len(alerts) == 0 {
+		return plugin.Passed(info), nil
+	}
+	names := make([]string, 0)
+	for _, alert := range alerts[:min(len(alerts), maxReportedAlerts)] {
+		names = append(names, alert.Labels["alertname"])
+	}
+	info["alertnames"] = names
+	return plugin.Failed(info), nil
+}
+
+func (aa *AlertmanagerAlerts) OnTransition(params plugin.Parameters) error {
+	return nil
+}
+
+// AlertmanagerSilence is a trigger plugin, which creates a silence or expires the previously created one.
+// The ids of created silences are recorded per profile in the constants.SilencesAnnotationKey annotation.
+type AlertmanagerSilence struct {
+	Client alertmanagerClient
+	// rendered as templates before being parsed
+	Matchers []string
+	// zero means the silence lasts until it is expired
+	Duration time.Duration
+	// rendered as template
+	Comment   string
+	CreatedBy string
+	Expire    bool
+}
+
+// New creates a new AlertmanagerSilence instance with the given config.
+func (as *AlertmanagerSilence) New(config *ucfgwrap.Config) (plugin.Trigger, error) {
+	conf := struct {
+		URL       string        `config:"url" validate:"required"`
+		Timeout   time.Duration `config:"timeout"`
+		Auth      authConfig    `config:"auth"`
+		Matchers  []string      `config:"matchers"`
+		Duration  time.Duration `config:"duration"`
+		Comment   string        `config:"comment"`
+		CreatedBy string        `config:"createdBy"`
+		Expire    bool          `config:"expire"`
+	}{
+		Timeout:   10 * time.Second,
+		Comment:   "Node {{ .Node.Name }} is in maintenance",
+		CreatedBy: "maintenance-controller",
+	}
+	if err := config.Unpack(&conf); err != nil {
+		return nil, err
+	}
+	if !conf.Expire && len(conf.Matchers) == 0 {
+		return nil, errors.New("an alertmanagerSilence needs matchers to create silences")
+	}
+	if conf.Duration < 0 {
+		return nil, errors.New("the duration of a silence must not be negative")
+	}
+	client, err := newAlertmanagerClient(conf.URL, conf.Timeout, conf.Auth)
+	if err != nil {
+		return nil, err
+	}
+	return &AlertmanagerSilence{
+		Client:    client,
+		Matchers:  conf.Matchers,
+		Duration:  conf.Duration,
+		Comment:   conf.Comment,
+		CreatedBy: conf.CreatedBy,
+		Expire:    conf.Expire,
+	}, nil
+}
+
+func (as *AlertmanagerSilence) ID() string {
+	return "alertmanagerSilence"
+}
+
+// alertSilence is a silence of the alertmanager v2 API.
+type alertSilence struct {
+	ID        string         `json:"id,omitempty"`
+	Matchers  []AlertMatcher `json:"matchers"`
+	StartsAt  time.Time      `json:"startsAt"`
+	EndsAt    time.Time      `json:"endsAt"`
+	CreatedBy string         `json:"createdBy"`
+	Comment   string         `json:"comment"`
+	Status    struct {
+		State string `json:"state"`
+	} `json:"status,omitzero"`
+}
+
+// isActive returns whether the silence is pending or active.
+func (s *alertSilence) isActive() bool {
+	return s.Status.State == "pending" || s.Status.State == "active"
+}
+
+// Trigger creates a silence or expires the recorded silence, if expire is set.
+// Instead of creating a silence, the silence recorded for the profile or another silence
+// of the node and profile with the same matchers and creator is reused and extended,
+// if it is still pending or active.
+// A silence, which is still recorded for another node or profile, is not expired.
+func (as *AlertmanagerSilence) Trigger(params plugin.Parameters) error {
+	silences, err := recordedSilences(params.Node)
+	if err != nil {
+		return err
+	}
+	silenceID, recorded := silences[params.Profile]
+	if as.Expire {
+		if !recorded {
+			return nil
+		}
+		delete(silences, params.Profile)
+		referenced, err := silenceReferenced(&params, silences, silenceID)
+		if err != nil {
+			return err
+		}
+		if referenced {
+			params.Log.Info("Keeping alertmanager silence, which is recorded for another node or profile",
+				"node", params.Node.Name, "silence", silenceID)
+			return setRecordedSilences(params.Node, silences)
+		}
+		status, err := as.Client.do(&params, http.MethodDelete, "/api/v2/silence/"+url.PathEscape(silenceID), nil, nil, nil)
+		if err != nil && status != http.StatusNotFound {
+			return fmt.Errorf("failed to expire silence %s: %w", silenceID, err)
+		}
+		return setRecordedSilences(params.Node, silences)
+	}
+	matchers, err := renderMatchers(as.Matchers, &params)
+	if err != nil {
+		return err
+	}
+	comment, err := plugin.RenderNotificationTemplate(as.Comment, &params)
+	if err != nil {
+		return fmt.Errorf("failed to render silence comment: %w", err)
+	}
+	var existing *alertSilence
+	if recorded {
+		existing, err = as.getSilence(&params, silenceID)
+		if err != nil {
+			return err
+		}
+	}
+	if existing == nil || !existing.isActive() {
+		existing, err = as.findSilence(&params, matchers, silenceMarker(&params))
+		if err != nil {
+			return err
+		}
+	}
+	now := time.Now().UTC()
+	duration := as.Duration
+	if duration == 0 {
+		duration = openEndedSilence
+	}
+	silence := alertSilence{
+		Matchers:  matchers,
+		StartsAt:  now,
+		EndsAt:    now.Add(duration),
+		CreatedBy: as.CreatedBy,
+		Comment:   comment + " " + silenceMarker(&params),
+	}
+	if existing != nil {
+		if existing.EndsAt.After(now.Add(duration / 2)) {
+			silences[params.Profile] = existing.ID
+			return setRecordedSilences(params.Node, silences)
+		}
+		// extend the existing silence, which ends within less than half of the duration
+		silence.ID = existing.ID
+		silence.Matchers = existing.Matchers
+		silence.StartsAt = existing.StartsAt
+	}
+	var created struct {
+		SilenceID string `json:"silenceID"`
+	}
+	if _, err := as.Client.do(&params, http.MethodPost, "/api/v2/silences", nil, silence, &created); err != nil {
+		return fmt.Errorf("failed to create silence: %w", err)
+	}
+	if existing != nil {
+		params.Log.Info("Extended alertmanager silence", "node", params.Node.Name, "silence", created.SilenceID)
+	} else {
+		params.Log.Info("Created alertmanager silence", "node", params.Node.Name, "silence", created.SilenceID)
+	}
+	silences[params.Profile] = created.SilenceID
+	return setRecordedSilences(params.Node, silences)
+}
+
+// getSilence returns the given silence or nil, if it does not exist.
+func (as *AlertmanagerSilence) getSilence(params *plugin.Parameters, silenceID string) (*alertSilence, error) {
+	var silence alertSilence
+	status, err := as.Client.do(params, http.MethodGet, "/api/v2/silence/"+url.PathEscape(silenceID), nil, nil, &silence)
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	silence.ID = silenceID
+	return &silence, nil
+}
+
+// silenceMarker identifies the node and profile a silence has been created for.
+// It is appended to the comment, as the same matchers may be used to silence multiple nodes.
+func silenceMarker(params *plugin.Parameters) string {
+	return fmt.Sprintf("(node %s, profile %s)", params.Node.Name, params.Profile)
+}
+
+// findSilence returns a pending or active silence of the configured creator with exactly the given matchers,
+// which has been created for the node and profile identified by the marker,
+// e.g. one created by a previous maintenance, which has not been recorded due to a failed node update.
+func (as *AlertmanagerSilence) findSilence(params *plugin.Parameters, matchers []AlertMatcher,
+	marker string) (*alertSilence, error) {
+
+	filters := make([]string, 0, len(matchers))
+	for _, matcher := range matchers {
+		filters = append(filters, matcher.String())
+	}
+	var silences []alertSilence
+	_, err := as.Client.do(params, http.MethodGet, "/api/v2/silences", url.Values{"filter": filters}, nil, &silences)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list silences: %w", err)
+	}
+	slices.Sort(filters)
+	for i := range silences {
+		silence := &silences[i]
+		if !silence.isActive() || silence.CreatedBy != as.CreatedBy || !strings.HasSuffix(silence.Comment, marker) ||
+			len(silence.Matchers) != len(matchers) {
+			continue
+		}
+		existing := make([]string, 0, len(silence.Matchers))
+		for _, matcher := range silence.Matchers {
+			existing = append(existing, matcher.String())
+		}
+		slices.Sort(existing)
+		if slices.Equal(existing, filters) {
+			return silence, nil
+		}
+	}
+	return nil, nil
+}
+
+// silenceReferenced returns whether the given silence is recorded for another profile of the node
+// or for any other node.
+func silenceReferenced(params *plugin.Parameters, silences map[string]string, silenceID string) (bool, error) {
+	for _, id := range silences {
+		if id == silenceID {
+			return true, nil
+		}
+	}
+	var nodes v1.NodeList
+	if err := params.Client.List(params.Ctx, &nodes); err != nil {
+		return false, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.Name == params.Node.Name {
+			continue
+		}
+		recorded, err := recordedSilences(node)
+		if err != nil {
+			return false, fmt.Errorf("failed to check silences of node %s: %w", node.Name, err)
+		}
+		for _, id := range recorded {
+			if id == silenceID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func recordedSilences(node *v1.Node) (map[string]string, error) {
+	silences := make(map[string]string)
+	raw := node.Annotations[constants.SilencesAnnotationKey]
+	if raw == "" {
+		return silences, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &silences); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s: %w", constants.SilencesAnnotationKey, err)
+	}
+	return silences, nil
+}
+
+func setRecordedSilences(node *v1.Node, silences map[string]string) error {
+	if len(silences) == 0 {
+		delete(node.Annotations, constants.SilencesAnnotationKey)
+		return nil
+	}
+	data, err := json.Marshal(silences)
+	if err != nil {
+		return err
+	}
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+	node.Annotations[constants.SilencesAnnotationKey] = string(data)
+	return nil
+}
+
+// AlertmanagerNotifier is a notification plugin, which posts an alert for the node and the profile
+// to an alertmanager. The alert is resolved, once the profile is operational again.
+// It implements plugin.StateChangeNotifier, so resolving does not depend on the schedule.
+type AlertmanagerNotifier struct {
+	Client    alertmanagerClient
+	AlertName string
+	// values are rendered as templates
+	Labels map[string]string
+	// values are rendered as templates
+	Annotations map[string]string
+	// how long a firing alert lasts, unless it is posted again
+	Duration time.Duration
+}
+
+// New creates a new AlertmanagerNotifier instance with the given config.
+func (an *AlertmanagerNotifier) New(config *ucfgwrap.Config) (plugin.Notifier, error) {
+	conf := struct {
+		URL         string            `config:"url" validate:"required"`
+		Timeout     time.Duration     `config:"timeout"`
+		Auth        authConfig        `config:"auth"`
+		AlertName   string            `config:"alertName"`
+		Labels      map[string]string `config:"labels"`
+		Annotations map[string]string `config:"annotations"`
+		Duration    time.Duration     `config:"duration"`
+	}{
+		Timeout:   10 * time.Second,
+		AlertName: "NodeMaintenance",
+		Duration:  time.Hour,
+	}
+	if err := config.Unpack(&conf); err != nil {
+		return nil, err
+	}
+	if conf.Duration <= 0 {
+		return nil, errors.New("the duration of an alert needs to be positive")
+	}
+	client, err := newAlertmanagerClient(conf.URL, conf.Timeout, conf.Auth)
+	if err != nil {
+		return nil, err
+	}
+	return &AlertmanagerNotifier{
+		Client:      client,
+		AlertName:   conf.AlertName,
+		Labels:      conf.Labels,
+		Annotations: conf.Annotations,
+		Duration:    conf.Duration,
+	}, nil
+}
+
+func (an *AlertmanagerNotifier) ID() string {
+	return "alertmanager"
+}
+
+// Notify posts a firing alert or resolves it, if the state is operational.
+// The alert is identified by the alertname, node and profile labels as well as the configured labels,
+// so the labels must not depend on the state.
+func (an *AlertmanagerNotifier) Notify(params plugin.Parameters) error {
+	labels, err := renderValues(an.Labels, &params)
+	if err != nil {
+		return fmt.Errorf("failed to render alert labels: %w", err)
+	}
+	labels["alertname"] = an.AlertName
+	labels["node"] = params.Node.Name
+	labels["profile"] = params.Profile
+	annotations, err := renderValues(an.Annotations, &params)
+	if err != nil {
+		return fmt.Errorf("failed to render alert annotations: %w", err)
+	}
+	annotations["state"] = params.State
+	now := time.Now().UTC()
+	alert := map[string]any{"labels": labels, "annotations": annotations}
+	if params.State == "operational" {
+		alert["endsAt"] = now.Format(time.RFC3339)
+	} else {
+		alert["startsAt"] = now.Format(time.RFC3339)
+		alert["endsAt"] = now.Add(an.Duration).Format(time.RFC3339)
+	}
+	if _, err := an.Client.do(&params, http.MethodPost, "/api/v2/alerts", nil, []any{alert}, nil); err != nil {
+		return fmt.Errorf("failed to post alert: %w", err)
+	}
+	return nil
+}
+
+// NotifyStateChange resolves the alert once the profile is operational again, regardless of the schedule.
+func (an *AlertmanagerNotifier) NotifyStateChange(params plugin.Parameters, previous string) error {
+	if params.State != "operational" || previous == "operational" {
+		return nil
+	}
+	return an.Notify(params)
+}
+
+func renderValues(templates map[string]string, params *plugin.Parameters) (map[string]string, error) {
+	rendered := make(map[string]string, len(templates))
+	for key, value := range templates {
+		result, err := plugin.RenderNotificationTemplate(value, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", key, err)
+		}
+		rendered[key] = result
+	}
+	return rendered, nil
+}
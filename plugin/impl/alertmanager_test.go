@@ -0,0 +1,450 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sapcc/ucfgwrap"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sapcc/maintenance-controller/constants"
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+// fakeAlertmanager implements the parts of the alertmanager v2 API used by the alertmanager plugins.
+type fakeAlertmanager struct {
+	mutex    sync.Mutex
+	filters  [][]string
+	query    map[string]string
+	alerts   map[string][]map[string]any
+	silences map[string]map[string]any
+	posted   []map[string]any
+}
+
+func (fa *fakeAlertmanager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer GinkgoRecover()
+	fa.mutex.Lock()
+	defer fa.mutex.Unlock()
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v2/alerts":
+		query := r.URL.Query()
+		fa.filters = append(fa.filters, query["filter"])
+		fa.query = map[string]string{
+			"active":    query.Get("active"),
+			"silenced":  query.Get("silenced"),
+			"inhibited": query.Get("inhibited"),
+		}
+		alerts := make([]map[string]any, 0)
+		for _, filter := range query["filter"] {
+			alerts = append(alerts, fa.alerts[filter]...)
+		}
+		Expect(json.NewEncoder(w).Encode(alerts)).To(Succeed())
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v2/alerts":
+		var alerts []map[string]any
+		Expect(json.NewDecoder(r.Body).Decode(&alerts)).To(Succeed())
+		fa.posted = append(fa.posted, alerts...)
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v2/silences":
+		fa.filters = append(fa.filters, r.URL.Query()["filter"])
+		silences := make([]map[string]any, 0)
+		for id, silence := range fa.silences {
+			listed := maps.Clone(silence)
+			listed["id"] = id
+			silences = append(silences, listed)
+		}
+		Expect(json.NewEncoder(w).Encode(silences)).To(Succeed())
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v2/silences":
+		var silence map[string]any
+		Expect(json.NewDecoder(r.Body).Decode(&silence)).To(Succeed())
+		id, ok := silence["id"].(string)
+		if !ok {
+			id = "silence-" + strconv.Itoa(len(fa.silences))
+		}
+		delete(silence, "id")
+		silence["status"] = map[string]any{"state": "active"}
+		fa.silences[id] = silence
+		Expect(json.NewEncoder(w).Encode(map[string]string{"silenceID": id})).To(Succeed())
+	case strings.HasPrefix(r.URL.Path, "/api/v2/silence/"):
+		silence, ok := fa.silences[strings.TrimPrefix(r.URL.Path, "/api/v2/silence/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			silence["status"] = map[string]any{"state": "expired"}
+			return
+		}
+		Expect(json.NewEncoder(w).Encode(silence)).To(Succeed())
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+var _ = Describe("Alertmanager matchers", func() {
+
+	DescribeTable("are parsed",
+		func(matcher string, expected AlertMatcher) {
+			parsed, err := ParseAlertMatcher(matcher)
+			Expect(err).To(Succeed())
+			Expect(parsed).To(Equal(expected))
+		},
+		Entry("quoted equality", `node="node01"`, AlertMatcher{Name: "node", Value: "node01", IsEqual: true}),
+		Entry("unquoted equality", "severity=critical", AlertMatcher{Name: "severity", Value: "critical", IsEqual: true}),
+		Entry("inequality", "node != node01", AlertMatcher{Name: "node", Value: "node01"}),
+		Entry("regex", `alertname=~"Node.*"`, AlertMatcher{Name: "alertname", Value: "Node.*", IsRegex: true, IsEqual: true}),
+		Entry("negative regex", `alertname!~"Kube.*"`, AlertMatcher{Name: "alertname", Value: "Kube.*", IsRegex: true}),
+	)
+
+	It("renders back to alertmanager syntax", func() {
+		parsed, err := ParseAlertMatcher("alertname=~Node.*")
+		Expect(err).To(Succeed())
+		Expect(parsed.String()).To(Equal(`alertname=~"Node.*"`))
+	})
+
+	It("rejects invalid matchers", func() {
+		for _, matcher := range []string{"node", `="x"`, `node="x`, `node=~"("`} {
+			_, err := ParseAlertMatcher(matcher)
+			Expect(err).To(HaveOccurred(), matcher)
+		}
+	})
+})
+
+var _ = Describe("The alertmanager plugins", func() {
+
+	It("can parse the alertmanagerAlerts configuration", func() {
+		configStr := "url: http://alertmanager/\nmatchers:\n- node=\"{{ .Node.Name }}\"\n- severity=critical"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base AlertmanagerAlerts
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(plugin).To(Equal(&AlertmanagerAlerts{
+			Client:   alertmanagerClient{URL: "http://alertmanager", Timeout: 10 * time.Second},
+			Matchers: []string{`node="{{ .Node.Name }}"`, "severity=critical"},
+		}))
+	})
+
+	It("can parse the alertmanagerSilence configuration", func() {
+		configStr := "url: http://alertmanager\nmatchers:\n- node=\"{{ .Node.Name }}\"\nduration: 2h\ncomment: reboot\n"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base AlertmanagerSilence
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(plugin).To(Equal(&AlertmanagerSilence{
+			Client:    alertmanagerClient{URL: "http://alertmanager", Timeout: 10 * time.Second},
+			Matchers:  []string{`node="{{ .Node.Name }}"`},
+			Duration:  2 * time.Hour,
+			Comment:   "reboot",
+			CreatedBy: "maintenance-controller",
+		}))
+	})
+
+	It("can parse the alertmanager notifier configuration", func() {
+		configStr := "url: http://alertmanager\nlabels:\n  severity: info\nannotations:\n  summary: '{{ .Node.Name }}'"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base AlertmanagerNotifier
+		notifier, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(notifier).To(Equal(&AlertmanagerNotifier{
+			Client:      alertmanagerClient{URL: "http://alertmanager", Timeout: 10 * time.Second},
+			AlertName:   "NodeMaintenance",
+			Labels:      map[string]string{"severity": "info"},
+			Annotations: map[string]string{"summary": "{{ .Node.Name }}"},
+			Duration:    time.Hour,
+		}))
+	})
+
+	It("requires matchers to create silences", func() {
+		config, err := ucfgwrap.FromYAML([]byte("url: http://alertmanager"))
+		Expect(err).To(Succeed())
+		var base AlertmanagerSilence
+		_, err = base.New(&config)
+		Expect(err).To(HaveOccurred())
+
+		config, err = ucfgwrap.FromYAML([]byte("url: http://alertmanager\nexpire: true"))
+		Expect(err).To(Succeed())
+		_, err = base.New(&config)
+		Expect(err).To(Succeed())
+	})
+
+	Context("with a fake alertmanager", func() {
+		var (
+			alertmanager *fakeAlertmanager
+			server       *httptest.Server
+			params       plugin.Parameters
+		)
+
+		BeforeEach(func() {
+			alertmanager = &fakeAlertmanager{
+				alerts: map[string][]map[string]any{
+					`node="busy"`: {{"labels": map[string]string{"alertname": "NodeDown", "node": "busy"}}},
+				},
+				silences: make(map[string]map[string]any),
+			}
+			server = httptest.NewServer(alertmanager)
+			params = plugin.Parameters{
+				Client:  fake.NewClientBuilder().Build(),
+				Ctx:     context.Background(),
+				Log:     GinkgoLogr,
+				Node:    &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "idle"}},
+				Profile: "reboot",
+			}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("passes if no alert matches", func() {
+			check := AlertmanagerAlerts{
+				Client:   alertmanagerClient{URL: server.URL, Timeout: time.Second},
+				Matchers: []string{`node="{{ .Node.Name }}"`},
+			}
+			result, err := check.Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeTrue())
+			Expect(alertmanager.filters).To(Equal([][]string{{`node="idle"`}}))
+			Expect(alertmanager.query).To(Equal(map[string]string{
+				"active": "true", "silenced": "false", "inhibited": "false",
+			}))
+		})
+
+		It("fails if an alert matches", func() {
+			params.Node.Name = "busy"
+			check := AlertmanagerAlerts{
+				Client:   alertmanagerClient{URL: server.URL, Timeout: time.Second},
+				Matchers: []string{`node="{{ .Node.Name }}"`},
+			}
+			result, err := check.Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.Info).To(HaveKeyWithValue("alertnames", []string{"NodeDown"}))
+		})
+
+		It("fails if the alertmanager cannot be reached", func() {
+			check := AlertmanagerAlerts{Client: alertmanagerClient{URL: server.URL + "/unknown", Timeout: time.Second}}
+			result, err := check.Check(params)
+			Expect(err).To(HaveOccurred())
+			Expect(result.Passed).To(BeFalse())
+		})
+
+		It("creates, reuses and expires silences", func() {
+			create := AlertmanagerSilence{
+				Client:    alertmanagerClient{URL: server.URL, Timeout: time.Second},
+				Matchers:  []string{`node="{{ .Node.Name }}"`, "severity=~warning|critical"},
+				Duration:  time.Hour,
+				Comment:   "{{ .Node.Name }} reboots",
+				CreatedBy: "maintenance-controller",
+			}
+			Expect(create.Trigger(params)).To(Succeed())
+			Expect(params.Node.Annotations).To(HaveKeyWithValue(constants.SilencesAnnotationKey, `{"reboot":"silence-0"}`))
+			silence := alertmanager.silences["silence-0"]
+			Expect(silence).To(HaveKeyWithValue("comment", "idle reboots (node idle, profile reboot)"))
+			Expect(silence).To(HaveKeyWithValue("matchers", ConsistOf(
+				map[string]any{"name": "node", "value": "idle", "isRegex": false, "isEqual": true},
+				map[string]any{"name": "severity", "value": "warning|critical", "isRegex": true, "isEqual": true},
+			)))
+
+			// an active silence is not recreated
+			Expect(create.Trigger(params)).To(Succeed())
+			Expect(alertmanager.silences).To(HaveLen(1))
+
+			expire := AlertmanagerSilence{
+				Client: alertmanagerClient{URL: server.URL, Timeout: time.Second},
+				Expire: true,
+			}
+			Expect(expire.Trigger(params)).To(Succeed())
+			Expect(alertmanager.silences["silence-0"]).To(HaveKeyWithValue("status", map[string]any{"state": "expired"}))
+			Expect(params.Node.Annotations).ToNot(HaveKey(constants.SilencesAnnotationKey))
+		})
+
+		It("replaces expired silences", func() {
+			alertmanager.silences["silence-0"] = map[string]any{"status": map[string]any{"state": "expired"}}
+			params.Node.Annotations = map[string]string{constants.SilencesAnnotationKey: `{"reboot":"silence-0"}`}
+			create := AlertmanagerSilence{
+				Client:   alertmanagerClient{URL: server.URL, Timeout: time.Second},
+				Matchers: []string{`node="{{ .Node.Name }}"`},
+				Duration: time.Hour,
+			}
+			Expect(create.Trigger(params)).To(Succeed())
+			Expect(params.Node.Annotations).To(HaveKeyWithValue(constants.SilencesAnnotationKey, `{"reboot":"silence-1"}`))
+		})
+
+		It("posts and resolves alerts", func() {
+			notifier := AlertmanagerNotifier{
+				Client:      alertmanagerClient{URL: server.URL, Timeout: time.Second},
+				AlertName:   "NodeMaintenance",
+				Labels:      map[string]string{"severity": "info"},
+				Annotations: map[string]string{"summary": "{{ .Node.Name }} is {{ .State }}"},
+				Duration:    time.Hour,
+			}
+			params.State = "in-maintenance"
+			Expect(notifier.Notify(params)).To(Succeed())
+			params.State = "operational"
+			Expect(notifier.Notify(params)).To(Succeed())
+
+			Expect(alertmanager.posted).To(HaveLen(2))
+			labels := map[string]any{"alertname": "NodeMaintenance", "node": "idle", "profile": "reboot", "severity": "info"}
+			firing := alertmanager.posted[0]
+			Expect(firing).To(HaveKeyWithValue("labels", labels))
+			Expect(firing).To(HaveKeyWithValue("annotations", map[string]any{
+				"summary": "idle is in-maintenance", "state": "in-maintenance",
+			}))
+			startsAt, err := time.Parse(time.RFC3339, firing["startsAt"].(string))
+			Expect(err).To(Succeed())
+			endsAt, err := time.Parse(time.RFC3339, firing["endsAt"].(string))
+			Expect(err).To(Succeed())
+			Expect(endsAt.Sub(startsAt)).To(Equal(time.Hour))
+
+			resolved := alertmanager.posted[1]
+			Expect(resolved).To(HaveKeyWithValue("labels", labels))
+			Expect(resolved).ToNot(HaveKey("startsAt"))
+			endsAt, err = time.Parse(time.RFC3339, resolved["endsAt"].(string))
+			Expect(err).To(Succeed())
+			Expect(endsAt).To(BeTemporally("~", time.Now(), time.Minute))
+		})
+
+		It("resolves alerts when entering the operational state", func() {
+			notifier := AlertmanagerNotifier{
+				Client:    alertmanagerClient{URL: server.URL, Timeout: time.Second},
+				AlertName: "NodeMaintenance",
+				Duration:  time.Hour,
+			}
+			params.State = "maintenance-required"
+			Expect(notifier.NotifyStateChange(params, "operational")).To(Succeed())
+			params.State = "operational"
+			Expect(notifier.NotifyStateChange(params, "operational")).To(Succeed())
+			Expect(alertmanager.posted).To(BeEmpty())
+			Expect(notifier.NotifyStateChange(params, "in-maintenance")).To(Succeed())
+			Expect(alertmanager.posted).To(HaveLen(1))
+			Expect(alertmanager.posted[0]).ToNot(HaveKey("startsAt"))
+		})
+
+		It("extends reused silences", func() {
+			endsAt := time.Now().Add(10 * time.Minute).UTC()
+			alertmanager.silences["silence-0"] = map[string]any{
+				"matchers":  []any{map[string]any{"name": "node", "value": "idle", "isRegex": false, "isEqual": true}},
+				"startsAt":  time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+				"endsAt":    endsAt.Format(time.RFC3339),
+				"createdBy": "maintenance-controller",
+				"status":    map[string]any{"state": "active"},
+			}
+			params.Node.Annotations = map[string]string{constants.SilencesAnnotationKey: `{"reboot":"silence-0"}`}
+			create := AlertmanagerSilence{
+				Client:    alertmanagerClient{URL: server.URL, Timeout: time.Second},
+				Matchers:  []string{`node="{{ .Node.Name }}"`},
+				Duration:  time.Hour,
+				CreatedBy: "maintenance-controller",
+			}
+			Expect(create.Trigger(params)).To(Succeed())
+			Expect(alertmanager.silences).To(HaveLen(1))
+			Expect(params.Node.Annotations).To(HaveKeyWithValue(constants.SilencesAnnotationKey, `{"reboot":"silence-0"}`))
+			extended, err := time.Parse(time.RFC3339, alertmanager.silences["silence-0"]["endsAt"].(string))
+			Expect(err).To(Succeed())
+			Expect(extended).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+		})
+
+		It("reuses silences with the same matchers and creator", func() {
+			matchers := []any{map[string]any{"name": "node", "value": "idle", "isRegex": false, "isEqual": true}}
+			endsAt := time.Now().Add(2 * time.Hour).UTC().Format(time.RFC3339)
+			alertmanager.silences["silence-0"] = map[string]any{
+				"matchers": matchers, "endsAt": endsAt, "createdBy": "someone",
+				"status": map[string]any{"state": "active"},
+			}
+			alertmanager.silences["silence-1"] = map[string]any{
+				"matchers": matchers, "endsAt": endsAt, "createdBy": "maintenance-controller",
+				"comment": "Node idle is in maintenance (node idle, profile reboot)",
+				"status":  map[string]any{"state": "active"},
+			}
+			create := AlertmanagerSilence{
+				Client:    alertmanagerClient{URL: server.URL, Timeout: time.Second},
+				Matchers:  []string{`node="{{ .Node.Name }}"`},
+				Duration:  time.Hour,
+				CreatedBy: "maintenance-controller",
+			}
+			Expect(create.Trigger(params)).To(Succeed())
+			Expect(alertmanager.silences).To(HaveLen(2))
+			Expect(alertmanager.filters).To(Equal([][]string{{`node="idle"`}}))
+			Expect(params.Node.Annotations).To(HaveKeyWithValue(constants.SilencesAnnotationKey, `{"reboot":"silence-1"}`))
+		})
+
+		It("does not share silences between nodes with the same matchers", func() {
+			create := AlertmanagerSilence{
+				Client:    alertmanagerClient{URL: server.URL, Timeout: time.Second},
+				Matchers:  []string{`alertname="NodeRebooted"`},
+				Duration:  time.Hour,
+				Comment:   "reboot",
+				CreatedBy: "maintenance-controller",
+			}
+			Expect(create.Trigger(params)).To(Succeed())
+			other := params
+			other.Node = &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "busy"}}
+			Expect(create.Trigger(other)).To(Succeed())
+			Expect(alertmanager.silences).To(HaveLen(2))
+			Expect(params.Node.Annotations).To(HaveKeyWithValue(constants.SilencesAnnotationKey, `{"reboot":"silence-0"}`))
+			Expect(other.Node.Annotations).To(HaveKeyWithValue(constants.SilencesAnnotationKey, `{"reboot":"silence-1"}`))
+
+			expire := AlertmanagerSilence{
+				Client: alertmanagerClient{URL: server.URL, Timeout: time.Second},
+				Expire: true,
+			}
+			Expect(expire.Trigger(params)).To(Succeed())
+			Expect(alertmanager.silences["silence-0"]).To(HaveKeyWithValue("status", map[string]any{"state": "expired"}))
+			Expect(alertmanager.silences["silence-1"]).To(HaveKeyWithValue("status", map[string]any{"state": "active"}))
+		})
+
+		It("does not expire silences recorded for other nodes", func() {
+			alertmanager.silences["silence-0"] = map[string]any{"status": map[string]any{"state": "active"}}
+			params.Node.Annotations = map[string]string{constants.SilencesAnnotationKey: `{"reboot":"silence-0"}`}
+			params.Client = fake.NewClientBuilder().WithObjects(params.Node.DeepCopy(), &corev1.Node{
+				ObjectMeta: v1.ObjectMeta{
+					Name:        "busy",
+					Annotations: map[string]string{constants.SilencesAnnotationKey: `{"reboot":"silence-0"}`},
+				},
+			}).Build()
+			expire := AlertmanagerSilence{
+				Client: alertmanagerClient{URL: server.URL, Timeout: time.Second},
+				Expire: true,
+			}
+			Expect(expire.Trigger(params)).To(Succeed())
+			Expect(alertmanager.silences["silence-0"]).To(HaveKeyWithValue("status", map[string]any{"state": "active"}))
+			Expect(params.Node.Annotations).ToNot(HaveKey(constants.SilencesAnnotationKey))
+		})
+
+		It("creates silences lasting until they are expired by default", func() {
+			create := AlertmanagerSilence{
+				Client:   alertmanagerClient{URL: server.URL, Timeout: time.Second},
+				Matchers: []string{`node="{{ .Node.Name }}"`},
+			}
+			Expect(create.Trigger(params)).To(Succeed())
+			endsAt, err := time.Parse(time.RFC3339, alertmanager.silences["silence-0"]["endsAt"].(string))
+			Expect(err).To(Succeed())
+			Expect(endsAt).To(BeTemporally(">", time.Now().Add(300*24*time.Hour)))
+		})
+
+		It("ignores unknown silences when expiring", func() {
+			params.Node.Annotations = map[string]string{constants.SilencesAnnotationKey: `{"reboot":"gone","other":"kept"}`}
+			expire := AlertmanagerSilence{
+				Client: alertmanagerClient{URL: server.URL, Timeout: time.Second},
+				Expire: true,
+			}
+			Expect(expire.Trigger(params)).To(Succeed())
+			Expect(params.Node.Annotations).To(HaveKeyWithValue(constants.SilencesAnnotationKey, `{"other":"kept"}`))
+		})
+	})
+})
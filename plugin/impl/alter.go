@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/sapcc/maintenance-controller/constants"
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+// alterConfig is the configuration shared by the alterLabel and alterAnnotation plugins.
+type alterConfig struct {
+	Key    string            `config:"key"`
+	Value  string            `config:"value"`
+	Remove bool              `config:"remove"`
+	Set    map[string]string `config:"set"`
+	Delete []string          `config:"delete"`
+}
+
+// Validate is invoked when unpacking configuration.
+func (ac *alterConfig) Validate() error {
+	if ac.Key == "" && len(ac.Set) == 0 && len(ac.Delete) == 0 {
+		return errors.New("either key, set or delete is required")
+	}
+	for _, key := range ac.Delete {
+		if _, ok := ac.Set[key]; ok {
+			return fmt.Errorf("key %s is to be set and deleted", key)
+		}
+	}
+	return nil
+}
+
+// alteration holds the changes applied by an alterLabel or alterAnnotation instance.
+type alteration struct {
+	set    map[string]string
+	delete []string
+	// record identifies the alteration within the AlteredAnnotationKey annotation.
+	// It is empty, if no value is templated, as applying static values is idempotent.
+	record string
+}
+
+// renderAlteration determines the rendered values to set and the keys to delete.
+// All values are rendered before any change is applied, so templates observe the unaltered node.
+// Trigger chains are executed again, if a later trigger fails, so templated values are only rendered
+// once per transition. The returned bool is true, if the alteration has already been applied
+// within the current transition of the profile.
+func renderAlteration(params *plugin.Parameters, kind, key, value string, remove bool,
+	set map[string]string, deleteKeys []string) (alteration, bool, error) {
+
+	templates := maps.Clone(set)
+	if templates == nil {
+		templates = make(map[string]string)
+	}
+	toDelete := slices.Clone(deleteKeys)
+	if key != "" && remove {
+		toDelete = append(toDelete, key)
+	} else if key != "" {
+		templates[key] = value
+	}
+	var alt alteration
+	if isTemplated(templates) {
+		alt.record = params.Profile + "/" + alterationFingerprint(kind, templates, toDelete)
+		records, err := recordedAlterations(params.Node)
+		if err != nil {
+			return alteration{}, false, err
+		}
+		if records[alt.record] == formatTransition(params.LastTransition) {
+			return alteration{}, true, nil
+		}
+	}
+	alt.set = make(map[string]string, len(templates))
+	for k, tmpl := range templates {
+		rendered, err := plugin.RenderNotificationTemplate(tmpl, params)
+		if err != nil {
+			return alteration{}, false, fmt.Errorf("failed to render value of %s: %w", k, err)
+		}
+		alt.set[k] = rendered
+	}
+	alt.delete = toDelete
+	return alt, false, nil
+}
+
+// recordApplied records that the alteration has been applied within the current transition.
+// Records of the profile from previous transitions are dropped.
+func (alt *alteration) recordApplied(params *plugin.Parameters) error {
+	if alt.record == "" {
+		return nil
+	}
+	records, err := recordedAlterations(params.Node)
+	if err != nil {
+		return err
+	}
+	transition := formatTransition(params.LastTransition)
+	maps.DeleteFunc(records, func(record, recordTransition string) bool {
+		return strings.HasPrefix(record, params.Profile+"/") && recordTransition != transition
+	})
+	records[alt.record] = transition
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	if params.Node.Annotations == nil {
+		params.Node.Annotations = make(map[string]string)
+	}
+	params.Node.Annotations[constants.AlteredAnnotationKey] = string(data)
+	return nil
+}
+
+func isTemplated(templates map[string]string) bool {
+	for _, tmpl := range templates {
+		if strings.Contains(tmpl, "{{") {
+			return true
+		}
+	}
+	return false
+}
+
+// alterationFingerprint identifies an alteration by its configuration,
+// so changing the configuration causes the alteration to be applied again.
+func alterationFingerprint(kind string, templates map[string]string, deleteKeys []string) string {
+	hash := sha256.New()
+	hash.Write([]byte(kind))
+	for _, key := range slices.Sorted(maps.Keys(templates)) {
+		fmt.Fprintf(hash, "\x00set\x00%s\x00%s", key, templates[key])
+	}
+	for _, key := range slices.Sorted(slices.Values(deleteKeys)) {
+		fmt.Fprintf(hash, "\x00delete\x00%s", key)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:alterationFingerprintLength]
+}
+
+const alterationFingerprintLength = 12
+
+func recordedAlterations(node *v1.Node) (map[string]string, error) {
+	records := make(map[string]string)
+	raw := node.Annotations[constants.AlteredAnnotationKey]
+	if raw == "" {
+		return records, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &records); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s: %w", constants.AlteredAnnotationKey, err)
+	}
+	return records, nil
+}
+
+func formatTransition(transition time.Time) string {
+	return transition.UTC().Format(time.RFC3339Nano)
+}
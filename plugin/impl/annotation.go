@@ -4,7 +4,7 @@
 package impl
 
 import (
-	"fmt"
+	"maps"
 
 	"github.com/sapcc/ucfgwrap"
 
@@ -12,21 +12,24 @@ import (
 )
 
 // HasAnnotation is a check plugin that checks whether a node has an annotation or an annotation with a certain value.
+// Alternatively the value can be matched against a regex or a semver range and the annotations against a selector.
 type HasAnnotation struct {
 	Key   string
 	Value string
+	Match ValueMatch
 }
 
 // New creates a new HasAnnotation instance with the given config.
 func (h *HasAnnotation) New(config *ucfgwrap.Config) (plugin.Checker, error) {
-	conf := struct {
-		Key   string `config:"key" validate:"required"`
-		Value string `config:"value"`
-	}{}
+	var conf matchConfig
 	if err := config.Unpack(&conf); err != nil {
 		return nil, err
 	}
-	return &HasAnnotation{Key: conf.Key, Value: conf.Value}, nil
+	match, err := conf.build()
+	if err != nil {
+		return nil, err
+	}
+	return &HasAnnotation{Key: conf.Key, Value: conf.Value, Match: match}, nil
 }
 
 func (h *HasAnnotation) ID() string {
@@ -35,39 +38,37 @@ func (h *HasAnnotation) ID() string {
 
 // Check checks whether a node has an annotation (if h.Value == "")
 // or an annotation with a certain value (if h.Value != "").
+// Additionally the regex, semver range and selector have to match, if configured.
 func (h *HasAnnotation) Check(params plugin.Parameters) (plugin.CheckResult, error) {
-	val, ok := params.Node.Annotations[h.Key]
-	if !ok {
-		return plugin.FailedWithReason(fmt.Sprintf("annotation %s not present", h.Key)), nil
-	}
-	if h.Value == "" {
-		return plugin.Passed(nil), nil
+	if ok, reason := h.Match.matches(params.Node.Annotations, h.Key, h.Value); !ok {
+		return plugin.FailedWithReason("annotation " + reason), nil
 	}
-	return plugin.CheckResult{Passed: val == h.Value}, nil
+	return plugin.Passed(nil), nil
 }
 
 func (h *HasAnnotation) OnTransition(params plugin.Parameters) error {
 	return nil
 }
 
-// AlterAnnotation is a trigger plugin, which can add, change or remove an annotation.
+// AlterAnnotation is a trigger plugin, which can add, change or remove annotations.
+// Values are rendered as templates before being applied.
 type AlterAnnotation struct {
 	Key    string
 	Value  string
 	Remove bool
+	// batch of annotations to add or change
+	Set map[string]string
+	// batch of annotations to remove
+	Delete []string
 }
 
 // New creates a new AlterAnnotation instance with the given config.
 func (a *AlterAnnotation) New(config *ucfgwrap.Config) (plugin.Trigger, error) {
-	conf := struct {
-		Key    string `config:"key" validate:"required"`
-		Value  string `config:"value"`
-		Remove bool   `config:"remove"`
-	}{}
+	var conf alterConfig
 	if err := config.Unpack(&conf); err != nil {
 		return nil, err
 	}
-	return &AlterAnnotation{Key: conf.Key, Remove: conf.Remove, Value: conf.Value}, nil
+	return &AlterAnnotation{Key: conf.Key, Remove: conf.Remove, Value: conf.Value, Set: conf.Set, Delete: conf.Delete}, nil
 }
 
 func (a *AlterAnnotation) ID() string {
@@ -76,14 +77,22 @@ func (a *AlterAnnotation) ID() string {
 
 // Trigger ensures the annotation with the provided key is removed if removes is set to true.
 // Otherwise it sets the annotation with the provided key to the provided value adding the annotation if required.
+// The same applies to the batches of annotations to set and to delete.
+// If any value is templated, the changes are applied only once per transition of the profile.
 func (a *AlterAnnotation) Trigger(params plugin.Parameters) error {
-	_, ok := params.Node.Annotations[a.Key]
-	if !a.Remove {
-		params.Node.Annotations[a.Key] = a.Value
+	alt, applied, err := renderAlteration(&params, "annotation", a.Key, a.Value, a.Remove, a.Set, a.Delete)
+	if err != nil {
+		return err
+	}
+	if applied {
 		return nil
 	}
-	if ok {
-		delete(params.Node.Annotations, a.Key)
+	if params.Node.Annotations == nil {
+		params.Node.Annotations = make(map[string]string)
 	}
-	return nil
+	for _, key := range alt.delete {
+		delete(params.Node.Annotations, key)
+	}
+	maps.Copy(params.Node.Annotations, alt.set)
+	return alt.recordApplied(&params)
 }
@@ -4,12 +4,16 @@
 package impl
 
 import (
+	"regexp"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/sapcc/ucfgwrap"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/sapcc/maintenance-controller/constants"
 	"github.com/sapcc/maintenance-controller/plugin"
 )
 
@@ -67,6 +71,17 @@ var _ = Describe("The HasAnnotation plugin", func() {
 			Expect(result.Passed).To(BeFalse())
 		})
 
+		It("matches the annotation with a regex", func() {
+			plugin := HasAnnotation{Key: "key", Match: ValueMatch{Regex: regexp.MustCompile("^val")}}
+			result, err := plugin.Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeTrue())
+			plugin = HasAnnotation{Key: "key", Match: ValueMatch{Regex: regexp.MustCompile("^other")}}
+			result, err = plugin.Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeFalse())
+		})
+
 	})
 
 })
@@ -124,5 +139,44 @@ var _ = Describe("The AlterAnnotation plugin", func() {
 			Expect(params.Node.Annotations).To(HaveLen(1))
 			Expect(params.Node.Annotations["key"]).To(Equal("abc"))
 		})
+
+		It("renders values and applies batches", func() {
+			plugin := AlterAnnotation{
+				Key:    "maintained-at",
+				Value:  "{{ now | rfc3339 }}",
+				Set:    map[string]string{"previous": `{{ index .Node.Annotations "key" }}`},
+				Delete: []string{"key"},
+			}
+			err := plugin.Trigger(params)
+			Expect(err).To(Succeed())
+			Expect(params.Node.Annotations).To(HaveLen(3))
+			Expect(params.Node.Annotations).To(HaveKeyWithValue("previous", "value"))
+			Expect(params.Node.Annotations).To(HaveKey(constants.AlteredAnnotationKey))
+			_, err = time.Parse(time.RFC3339, params.Node.Annotations["maintained-at"])
+			Expect(err).To(Succeed())
+		})
+
+		It("renders templated values once per transition", func() {
+			params.Profile = "profile"
+			params.LastTransition = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			plugin := AlterAnnotation{Key: "maintained-at", Value: "{{ now.UnixNano }}"}
+			Expect(plugin.Trigger(params)).To(Succeed())
+			first := params.Node.Annotations["maintained-at"]
+			// a later trigger of the chain returned a RetryError, so the chain is executed again
+			Expect(plugin.Trigger(params)).To(Succeed())
+			Expect(params.Node.Annotations["maintained-at"]).To(Equal(first))
+
+			params.LastTransition = params.LastTransition.Add(time.Hour)
+			Expect(plugin.Trigger(params)).To(Succeed())
+			Expect(params.Node.Annotations["maintained-at"]).ToNot(Equal(first))
+		})
+
+		It("requires a key or a batch", func() {
+			config, err := ucfgwrap.FromYAML([]byte("value: value"))
+			Expect(err).To(Succeed())
+			var base AlterAnnotation
+			_, err = base.New(&config)
+			Expect(err).ToNot(Succeed())
+		})
 	})
 })
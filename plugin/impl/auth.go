@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+// AuthType defines how credentials sourced from a secret are attached to a http request.
+type AuthType string
+
+const (
+	NoAuth     AuthType = ""
+	BearerAuth AuthType = "bearer"
+	BasicAuth  AuthType = "basic"
+	// MTLSAuth presents a client certificate, it is only supported by plugins, which manage their own transport.
+	MTLSAuth AuthType = "mtls"
+
+	// secret keys read by SecretAuth
+	tokenSecretKey    string = "token"
+	usernameSecretKey string = "username"
+	passwordSecretKey string = "password"
+	certSecretKey     string = corev1.TLSCertKey
+	keySecretKey      string = corev1.TLSPrivateKeyKey
+	caSecretKey       string = "ca.crt"
+)
+
+// authConfig is the configuration structure shared by plugins, which support SecretAuth.
+type authConfig struct {
+	Type   string `config:"type"`
+	Secret struct {
+		Name      string `config:"name"`
+		Namespace string `config:"namespace"`
+	} `config:"secret"`
+}
+
+func (ac *authConfig) toSecretAuth() (SecretAuth, error) {
+	authType := AuthType(ac.Type)
+	switch authType {
+	case NoAuth:
+		return SecretAuth{}, nil
+	case BearerAuth, BasicAuth, MTLSAuth:
+	default:
+		return SecretAuth{}, fmt.Errorf("got invalid auth type: %s", ac.Type)
+	}
+	if ac.Secret.Name == "" || ac.Secret.Namespace == "" {
+		return SecretAuth{}, fmt.Errorf("auth type %s requires a secret name and namespace", ac.Type)
+	}
+	return SecretAuth{
+		Type:   authType,
+		Secret: client.ObjectKey{Name: ac.Secret.Name, Namespace: ac.Secret.Namespace},
+	}, nil
+}
+
+// SecretAuth attaches credentials stored in a Kubernetes secret to http requests.
+// Bearer auth reads the "token" key, basic auth reads the "username" and "password" keys.
+// Mutual TLS auth reads the "tls.crt" and "tls.key" keys.
+type SecretAuth struct {
+	Type   AuthType
+	Secret client.ObjectKey
+}
+
+// Apply fetches the referenced secret and sets the authorization header of req accordingly.
+func (sa *SecretAuth) Apply(params *plugin.Parameters, req *http.Request) error {
+	if sa.Type == NoAuth {
+		return nil
+	}
+	secret, err := sa.fetch(params)
+	if err != nil {
+		return err
+	}
+	authorization, err := sa.authorization(secret)
+	if err != nil {
+		return err
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return nil
+}
+
+func (sa *SecretAuth) fetch(params *plugin.Parameters) (*corev1.Secret, error) {
+	secret, err := secrets.get(params, sa.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve auth secret %s: %w", sa.Secret, err)
+	}
+	return secret, nil
+}
+
+// authorization returns the value of the authorization header, which is empty for mutual TLS.
+func (sa *SecretAuth) authorization(secret *corev1.Secret) (string, error) {
+	switch sa.Type {
+	case BearerAuth:
+		token, err := secretValue(secret, tokenSecretKey)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	case BasicAuth:
+		username, err := secretValue(secret, usernameSecretKey)
+		if err != nil {
+			return "", err
+		}
+		password, err := secretValue(secret, passwordSecretKey)
+		if err != nil {
+			return "", err
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)), nil
+	}
+	return "", nil
+}
+
+// tlsConfig returns the client certificate for mutual TLS and the optional "ca.crt" key
+// as trusted certificate authority, which applies to all auth types.
+func (sa *SecretAuth) tlsConfig(secret *corev1.Secret) (*tls.Config, error) {
+	config := tls.Config{MinVersion: tls.VersionTLS12}
+	if sa.Type == MTLSAuth {
+		cert, err := secretValue(secret, certSecretKey)
+		if err != nil {
+			return nil, err
+		}
+		key, err := secretValue(secret, keySecretKey)
+		if err != nil {
+			return nil, err
+		}
+		keyPair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate in secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+		config.Certificates = []tls.Certificate{keyPair}
+	}
+	if ca, ok := secret.Data[caSecretKey]; ok {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("failed to parse certificate authority in secret %s/%s", secret.Namespace, secret.Name)
+		}
+		config.RootCAs = pool
+	}
+	return &config, nil
+}
+
+// secretCacheTTL is how long fetched secrets are reused. Secrets are not cached by the manager,
+// so without it every check or notification would request credentials from the API server.
+const secretCacheTTL = time.Minute
+
+// secrets is shared by all plugins, as plugin instances are recreated whenever the configuration is loaded.
+var secrets = secretCache{entries: make(map[client.ObjectKey]*cachedSecret)}
+
+type cachedSecret struct {
+	secret  *corev1.Secret
+	fetched time.Time
+}
+
+// secretCache holds recently fetched secrets. Failed fetches are not cached.
+type secretCache struct {
+	mutex   sync.Mutex
+	entries map[client.ObjectKey]*cachedSecret
+}
+
+// get returns the given secret, which is fetched again once it is older than secretCacheTTL.
+// The returned secret is shared and must not be modified.
+func (sc *secretCache) get(params *plugin.Parameters, key client.ObjectKey) (*corev1.Secret, error) {
+	sc.mutex.Lock()
+	cached, ok := sc.entries[key]
+	sc.mutex.Unlock()
+	if ok && time.Since(cached.fetched) < secretCacheTTL {
+		return cached.secret, nil
+	}
+	var secret corev1.Secret
+	if err := params.Client.Get(params.Ctx, key, &secret); err != nil {
+		return nil, err
+	}
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	sc.entries[key] = &cachedSecret{secret: &secret, fetched: time.Now()}
+	return &secret, nil
+}
+
+func secretValue(secret *corev1.Secret, key string) (string, error) {
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s does not contain key '%s'", secret.Namespace, secret.Name, key)
+	}
+	return string(value), nil
+}
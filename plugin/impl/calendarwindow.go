@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sapcc/ucfgwrap"
+
+	"github.com/sapcc/maintenance-controller/common"
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+// maxBlackoutSkips bounds the search for the next window, which is not within a blackout.
+const maxBlackoutSkips = 1000
+
+// CalendarWindow is a check plugin that passes within recurring windows defined by cron expressions,
+// unless an event of the blackout calendar is active.
+type CalendarWindow struct {
+	Windows  []common.CronWindow
+	Location *time.Location
+	Blackout *common.CalendarSource
+	// names of shared calendars, which are blackouts as well
+	Calendars []string
+}
+
+// New creates a new CalendarWindow instance with the given config.
+func (cw *CalendarWindow) New(config *ucfgwrap.Config) (plugin.Checker, error) {
+	conf := struct {
+		Timezone string `config:"timezone"`
+		Windows  []struct {
+			Cron     string        `config:"cron" validate:"required"`
+			Duration time.Duration `config:"duration" validate:"required"`
+		} `config:"windows"`
+		Blackout  *common.CalendarSource `config:"blackout"`
+		Calendars []string               `config:"calendars"`
+	}{}
+	if err := config.Unpack(&conf); err != nil {
+		return nil, err
+	}
+	if len(conf.Windows) == 0 {
+		return nil, errors.New("a calendarWindow needs to have windows specified")
+	}
+	location, err := common.LoadTimezone(conf.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	calendarWindow := &CalendarWindow{Location: location, Blackout: conf.Blackout, Calendars: conf.Calendars}
+	for _, windowConf := range conf.Windows {
+		window, err := common.ParseCronWindow(windowConf.Cron, windowConf.Duration)
+		if err != nil {
+			return nil, err
+		}
+		calendarWindow.Windows = append(calendarWindow.Windows, window)
+	}
+	if conf.Blackout != nil && conf.Blackout.Inline != "" {
+		// fail early on invalid inline calendars
+		if _, err := common.ParseCalendar(conf.Blackout.Inline, location); err != nil {
+			return nil, fmt.Errorf("invalid blackout calendar: %w", err)
+		}
+	}
+	return calendarWindow, nil
+}
+
+func (cw *CalendarWindow) ID() string {
+	return "calendarWindow"
+}
+
+// Check passes if the current time is within a window and not within a blackout event.
+func (cw *CalendarWindow) Check(params plugin.Parameters) (plugin.CheckResult, error) {
+	var blackout *common.Calendar
+	if cw.Blackout != nil {
+		var err error
+		blackout, err = cw.Blackout.Load(params.Ctx, params.Client, cw.Location)
+		if err != nil {
+			return plugin.Failed(nil), fmt.Errorf("failed to load blackout calendar: %w", err)
+		}
+	}
+	for _, name := range cw.Calendars {
+		calendar, err := params.Calendars.Get(params.Ctx, params.Client, name)
+		if err != nil {
+			return plugin.Failed(nil), err
+		}
+		events := calendar.Events
+		if blackout != nil {
+			events = slices.Concat(blackout.Events, events)
+		}
+		blackout = &common.Calendar{Events: events}
+	}
+	return cw.checkInternal(time.Now(), blackout), nil
+}
+
+func (cw *CalendarWindow) checkInternal(now time.Time, blackout *common.Calendar) plugin.CheckResult {
+	now = now.In(cw.Location)
+	info := map[string]any{"timezone": cw.Location.String()}
+	if next, ok := cw.nextWindow(now, blackout); ok {
+		info["nextWindow"] = next.Format(time.RFC3339)
+	}
+	end, inWindow := cw.activeWindowEnd(now)
+	if !inWindow {
+		info["reason"] = "not within a window"
+		return plugin.Failed(info)
+	}
+	info["windowEnd"] = end.Format(time.RFC3339)
+	if blackout != nil {
+		if event, ok := blackout.EventAt(now); ok {
+			info["reason"] = "within blackout"
+			info["blackout"] = event.Summary
+			return plugin.Failed(info)
+		}
+	}
+	return plugin.Passed(info)
+}
+
+// activeWindowEnd returns the latest end of all windows containing the given time.
+func (cw *CalendarWindow) activeWindowEnd(now time.Time) (time.Time, bool) {
+	var end time.Time
+	for _, window := range cw.Windows {
+		start, ok := window.Active(now)
+		if !ok {
+			continue
+		}
+		if windowEnd := start.Add(window.Duration); windowEnd.After(end) {
+			end = windowEnd
+		}
+	}
+	return end, !end.IsZero()
+}
+
+// nextWindow returns the next window start after the given time, which is not within a blackout.
+// Window starts within a blackout are skipped by continuing at the end of the blackout.
+func (cw *CalendarWindow) nextWindow(now time.Time, blackout *common.Calendar) (time.Time, bool) {
+	after := now
+	for range maxBlackoutSkips {
+		var next time.Time
+		for _, window := range cw.Windows {
+			start := window.Schedule.Next(after)
+			if !start.IsZero() && (next.IsZero() || start.Before(next)) {
+				next = start
+			}
+		}
+		if next.IsZero() {
+			return next, false
+		}
+		if blackout == nil {
+			return next, true
+		}
+		periods := blackout.Periods(next, next.Add(time.Nanosecond))
+		if len(periods) == 0 {
+			return next, true
+		}
+		// continue with the first window start, which is not before the end of the blackouts
+		after = next
+		for _, period := range periods {
+			if end := period.End.Add(-time.Nanosecond).In(now.Location()); end.After(after) {
+				after = end
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func (cw *CalendarWindow) CalendarRefs() []string {
+	return cw.Calendars
+}
+
+// blackoutResult is the result of time based checks during a period of a shared calendar.
+func blackoutResult(period common.CalendarPeriod) plugin.CheckResult {
+	return plugin.Failed(map[string]any{
+		"reason":      "within blackout",
+		"calendar":    period.Calendar,
+		"blackout":    period.Summary,
+		"blackoutEnd": period.End.Format(time.RFC3339),
+	})
+}
+
+func (cw *CalendarWindow) OnTransition(params plugin.Parameters) error {
+	return nil
+}
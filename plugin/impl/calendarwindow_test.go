@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sapcc/ucfgwrap"
+
+	"github.com/sapcc/maintenance-controller/common"
+)
+
+var _ = Describe("The CalendarWindow plugin", func() {
+
+	It("can parse its config", func() {
+		configStr := `
+timezone: Europe/Berlin
+windows:
+- cron: "0 22 * * MON-FRI"
+  duration: 4h
+blackout:
+  inline: |
+    BEGIN:VEVENT
+    DTSTART:20261220T000000Z
+    DTEND:20270102T000000Z
+    END:VEVENT
+`
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base CalendarWindow
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		calendarWindow, ok := plugin.(*CalendarWindow)
+		Expect(ok).To(BeTrue())
+		Expect(calendarWindow.Location.String()).To(Equal("Europe/Berlin"))
+		Expect(calendarWindow.Windows).To(HaveLen(1))
+		Expect(calendarWindow.Windows[0].Duration).To(Equal(4 * time.Hour))
+		Expect(calendarWindow.Blackout).ToNot(BeNil())
+	})
+
+	It("should fail creation without windows", func() {
+		config, err := ucfgwrap.FromYAML([]byte("timezone: UTC"))
+		Expect(err).To(Succeed())
+		var base CalendarWindow
+		_, err = base.New(&config)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail creation with an invalid time zone", func() {
+		configStr := "timezone: Mars/Olympus\nwindows:\n- cron: \"0 22 * * *\"\n  duration: 4h"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base CalendarWindow
+		_, err = base.New(&config)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail creation with multiple blackout sources", func() {
+		configStr := "windows:\n- cron: \"0 22 * * *\"\n  duration: 4h\nblackout:\n  file: /a.ics\n  inline: abc"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base CalendarWindow
+		_, err = base.New(&config)
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("with a nightly window in Berlin", func() {
+
+		var berlin *time.Location
+		var plugin CalendarWindow
+
+		BeforeEach(func() {
+			var err error
+			berlin, err = time.LoadLocation("Europe/Berlin")
+			Expect(err).To(Succeed())
+			window, err := common.ParseCronWindow("0 22 * * *", 4*time.Hour)
+			Expect(err).To(Succeed())
+			plugin = CalendarWindow{Windows: []common.CronWindow{window}, Location: berlin}
+		})
+
+		It("passes within the window", func() {
+			result := plugin.checkInternal(time.Date(2026, time.October, 18, 23, 0, 0, 0, berlin), nil)
+			Expect(result.Passed).To(BeTrue())
+			Expect(result.Info["windowEnd"]).To(Equal("2026-10-19T02:00:00+02:00"))
+			Expect(result.Info["nextWindow"]).To(Equal("2026-10-19T22:00:00+02:00"))
+		})
+
+		It("evaluates the window in its time zone", func() {
+			result := plugin.checkInternal(time.Date(2026, time.October, 18, 21, 0, 0, 0, time.UTC), nil)
+			Expect(result.Passed).To(BeTrue())
+		})
+
+		It("fails outside the window", func() {
+			result := plugin.checkInternal(time.Date(2026, time.October, 18, 12, 0, 0, 0, berlin), nil)
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.Info["nextWindow"]).To(Equal("2026-10-18T22:00:00+02:00"))
+		})
+
+		It("fails within a blackout and skips blacked out windows", func() {
+			data := "BEGIN:VEVENT\nSUMMARY:Freeze\nDTSTART;VALUE=DATE:20261018\nDTEND;VALUE=DATE:20261020\nEND:VEVENT\n"
+			blackout, err := common.ParseCalendar(data, berlin)
+			Expect(err).To(Succeed())
+			result := plugin.checkInternal(time.Date(2026, time.October, 18, 23, 0, 0, 0, berlin), blackout)
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.Info["blackout"]).To(Equal("Freeze"))
+			Expect(result.Info["nextWindow"]).To(Equal("2026-10-20T22:00:00+02:00"))
+		})
+
+		It("skips long blackouts of frequent windows", func() {
+			window, err := common.ParseCronWindow("*/5 * * * *", time.Minute)
+			Expect(err).To(Succeed())
+			plugin.Windows = []common.CronWindow{window}
+			data := "BEGIN:VEVENT\nSUMMARY:Freeze\nDTSTART;VALUE=DATE:20261010\nDTEND;VALUE=DATE:20261120\nEND:VEVENT\n"
+			blackout, err := common.ParseCalendar(data, berlin)
+			Expect(err).To(Succeed())
+			result := plugin.checkInternal(time.Date(2026, time.October, 18, 12, 0, 0, 0, berlin), blackout)
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.Info["nextWindow"]).To(Equal("2026-11-20T00:00:00+01:00"))
+		})
+
+	})
+
+})
@@ -5,10 +5,13 @@ package impl
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/sapcc/ucfgwrap"
 	v1 "k8s.io/api/core/v1"
 
+	"github.com/sapcc/maintenance-controller/common"
+	"github.com/sapcc/maintenance-controller/constants"
 	"github.com/sapcc/maintenance-controller/plugin"
 )
 
@@ -49,3 +52,72 @@ func (c *Condition) Check(params plugin.Parameters) (plugin.CheckResult, error)
 func (c *Condition) OnTransition(params plugin.Parameters) error {
 	return nil
 }
+
+// reservedConditions are maintained by the kubelet or the controller itself and cannot be altered.
+var reservedConditions = []v1.NodeConditionType{
+	v1.NodeReady, v1.NodeMemoryPressure, v1.NodeDiskPressure, v1.NodePIDPressure, v1.NodeNetworkUnavailable,
+	constants.StateConditionType, constants.DrainConditionType,
+}
+
+// AlterCondition is a trigger plugin, which can set or remove a node condition.
+type AlterCondition struct {
+	Type   string
+	Status string
+	Reason string
+	// rendered as template before being applied
+	Message string
+	Remove  bool
+}
+
+// New creates a new AlterCondition instance with the given config.
+func (a *AlterCondition) New(config *ucfgwrap.Config) (plugin.Trigger, error) {
+	conf := struct {
+		Type    string `config:"type" validate:"required"`
+		Status  string `config:"status"`
+		Reason  string `config:"reason"`
+		Message string `config:"message"`
+		Remove  bool   `config:"remove"`
+	}{Status: string(v1.ConditionTrue)}
+	if err := config.Unpack(&conf); err != nil {
+		return nil, err
+	}
+	if slices.Contains(reservedConditions, v1.NodeConditionType(conf.Type)) {
+		return nil, fmt.Errorf("condition %s is reserved and cannot be altered", conf.Type)
+	}
+	switch v1.ConditionStatus(conf.Status) {
+	case v1.ConditionTrue, v1.ConditionFalse, v1.ConditionUnknown:
+	default:
+		return nil, fmt.Errorf("invalid condition status: %s", conf.Status)
+	}
+	return &AlterCondition{
+		Type:    conf.Type,
+		Status:  conf.Status,
+		Reason:  conf.Reason,
+		Message: conf.Message,
+		Remove:  conf.Remove,
+	}, nil
+}
+
+func (a *AlterCondition) ID() string {
+	return "alterCondition"
+}
+
+// Trigger sets the condition on the node or removes it, if remove is set.
+// The node status is patched at the end of the reconciliation.
+func (a *AlterCondition) Trigger(params plugin.Parameters) error {
+	if a.Remove {
+		common.RemoveNodeCondition(params.Node, v1.NodeConditionType(a.Type))
+		return nil
+	}
+	message, err := plugin.RenderNotificationTemplate(a.Message, &params)
+	if err != nil {
+		return fmt.Errorf("failed to render condition message: %w", err)
+	}
+	common.SetNodeCondition(params.Node, v1.NodeCondition{
+		Type:    v1.NodeConditionType(a.Type),
+		Status:  v1.ConditionStatus(a.Status),
+		Reason:  a.Reason,
+		Message: message,
+	})
+	return nil
+}
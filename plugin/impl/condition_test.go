@@ -8,6 +8,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/sapcc/ucfgwrap"
 	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/sapcc/maintenance-controller/plugin"
 )
@@ -65,3 +66,50 @@ var _ = Describe("The Condition plugin", func() {
 		})
 	})
 })
+
+var _ = Describe("The AlterCondition plugin", func() {
+
+	It("can parse its config", func() {
+		config, err := ucfgwrap.FromYAML([]byte("type: Maintenance\nreason: Planned\nmessage: msg"))
+		Expect(err).To(Succeed())
+		var base AlterCondition
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(plugin).To(Equal(&AlterCondition{Type: "Maintenance", Status: "True", Reason: "Planned", Message: "msg"}))
+	})
+
+	It("refuses reserved conditions and invalid states", func() {
+		var base AlterCondition
+		config, err := ucfgwrap.FromYAML([]byte("type: Ready"))
+		Expect(err).To(Succeed())
+		_, err = base.New(&config)
+		Expect(err).ToNot(Succeed())
+		config, err = ucfgwrap.FromYAML([]byte("type: Maintenance\nstatus: Maybe"))
+		Expect(err).To(Succeed())
+		_, err = base.New(&config)
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("sets and removes a condition", func() {
+		params := plugin.Parameters{
+			Node: &corev1.Node{
+				ObjectMeta: v1.ObjectMeta{Name: "targetnode"},
+				Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				}},
+			},
+			Log: GinkgoLogr,
+		}
+		alter := AlterCondition{Type: "Maintenance", Status: "True", Reason: "Planned", Message: "{{ .Node.Name }}"}
+		Expect(alter.Trigger(params)).To(Succeed())
+		Expect(params.Node.Status.Conditions).To(HaveLen(2))
+		Expect(params.Node.Status.Conditions[1].Message).To(Equal("targetnode"))
+		Expect(params.Node.Status.Conditions[1].LastTransitionTime.IsZero()).To(BeFalse())
+
+		alter.Remove = true
+		Expect(alter.Trigger(params)).To(Succeed())
+		Expect(params.Node.Status.Conditions).To(HaveLen(1))
+		Expect(params.Node.Status.Conditions[0].Type).To(Equal(corev1.NodeReady))
+	})
+
+})
@@ -0,0 +1,335 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sapcc/ucfgwrap"
+	"github.com/slack-go/slack"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+const (
+	// key of the configmap holding the collected notifications
+	digestDataKey string = "digest"
+
+	defaultDigestMessage string = `Maintenance digest from {{ .Start | rfc3339 }} to {{ .End | rfc3339 }}
+{{ range .Groups }}{{ .Profile }} {{ .State }}: {{ .Count }} node(s): {{ .Nodes | join ", " }}
+{{ end }}`
+)
+
+// Digest is a notification plugin, which collects the notifications of all nodes within a period
+// in a configmap and sends them as one aggregated message afterwards.
+type Digest struct {
+	ConfigMap types.NamespacedName
+	Period    time.Duration
+	// rendered as template using DigestData
+	Message string
+	// exactly one of Slack and Mail is set
+	Slack   *DigestSlack
+	Mail    *Mail
+	testURL string
+}
+
+// DigestSlack configures the slack channel a digest is posted to.
+type DigestSlack struct {
+	Token   string
+	Channel string
+}
+
+// DigestEntry is the state of a node and profile as of its last notification within a period.
+type DigestEntry struct {
+	Node           string    `json:"node"`
+	Profile        string    `json:"profile"`
+	State          string    `json:"state"`
+	LastTransition time.Time `json:"lastTransition"`
+	// time of the first notification in the state
+	Time time.Time `json:"time"`
+}
+
+func (de *DigestEntry) key() string {
+	return de.Node + "/" + de.Profile
+}
+
+// DigestGroup contains the nodes of a profile in a state.
+type DigestGroup struct {
+	Profile string
+	State   string
+	Count   int
+	Nodes   []string
+}
+
+// DigestData is passed to the message template.
+type DigestData struct {
+	Start   time.Time
+	End     time.Time
+	Total   int
+	Groups  []DigestGroup
+	Entries []DigestEntry
+}
+
+// digestState is persisted in the configmap.
+type digestState struct {
+	Start   time.Time              `json:"start"`
+	Entries map[string]DigestEntry `json:"entries"`
+}
+
+// New creates a new Digest instance with the given config.
+func (d *Digest) New(config *ucfgwrap.Config) (plugin.Notifier, error) {
+	conf := struct {
+		ConfigMapName      string        `config:"configMapName" validate:"required"`
+		ConfigMapNamespace string        `config:"configMapNamespace" validate:"required"`
+		Period             time.Duration `config:"period" validate:"required"`
+		Message            string        `config:"message"`
+		Slack              struct {
+			Token   string `config:"token"`
+			Channel string `config:"channel"`
+		} `config:"slack"`
+		Mail struct {
+			Auth     bool     `config:"auth"`
+			Subject  string   `config:"subject"`
+			Address  string   `config:"address"`
+			From     string   `config:"from"`
+			To       []string `config:"to"`
+			Identity string   `config:"identity"`
+			User     string   `config:"user"`
+			Password string   `config:"password"` // #nosec G117 - intentional passing of secret credential
+		} `config:"mail"`
+	}{Message: defaultDigestMessage}
+	conf.Mail.Subject = "Maintenance digest"
+	if err := config.Unpack(&conf); err != nil {
+		return nil, err
+	}
+	if conf.Period <= 0 {
+		return nil, errors.New("the period of a digest needs to be positive")
+	}
+	digest := &Digest{
+		ConfigMap: types.NamespacedName{Name: conf.ConfigMapName, Namespace: conf.ConfigMapNamespace},
+		Period:    conf.Period,
+		Message:   conf.Message,
+	}
+	if conf.Slack.Token != "" && conf.Slack.Channel != "" {
+		digest.Slack = &DigestSlack{Token: conf.Slack.Token, Channel: conf.Slack.Channel}
+	}
+	if conf.Mail.Address != "" && conf.Mail.From != "" && len(conf.Mail.To) > 0 {
+		digest.Mail = &Mail{
+			Auth:     conf.Mail.Auth,
+			Subject:  conf.Mail.Subject,
+			Address:  conf.Mail.Address,
+			From:     conf.Mail.From,
+			To:       conf.Mail.To,
+			Identity: conf.Mail.Identity,
+			User:     conf.Mail.User,
+			Password: conf.Mail.Password,
+		}
+	}
+	if (digest.Slack == nil) == (digest.Mail == nil) {
+		return nil, errors.New("a digest needs either a slack token and channel or a mail address, from and to")
+	}
+	return digest, nil
+}
+
+func (d *Digest) ID() string {
+	return "digest"
+}
+
+func (d *Digest) SetTestURL(url string) {
+	d.testURL = url
+}
+
+// Notify records the notification of the node and sends the digest, if the period has passed.
+// The collection is reset before sending, so a digest is sent at most once even if sending fails.
+// Failures are logged, but do not fail the notification, so the digest cannot keep nodes from progressing.
+func (d *Digest) Notify(params plugin.Parameters) error {
+	now := time.Now().UTC()
+	entry := DigestEntry{
+		Node:           params.Node.Name,
+		Profile:        params.Profile,
+		State:          params.State,
+		LastTransition: params.LastTransition,
+		Time:           now,
+	}
+	due, err := d.update(&params, &entry, now)
+	if err != nil {
+		params.Log.Error(err, "Failed to record notification in digest", "configMap", d.ConfigMap)
+		return nil
+	}
+	if err := d.sendDue(&params, due, now); err != nil {
+		params.Log.Error(err, "Failed to send digest", "configMap", d.ConfigMap)
+	}
+	return nil
+}
+
+// Flush sends the digest, if the period has passed, regardless of nodes being notified.
+// It is invoked periodically by the controller, only Ctx, Client and Log of the parameters are set.
+func (d *Digest) Flush(params plugin.Parameters) error {
+	now := time.Now().UTC()
+	due, err := d.update(&params, nil, now)
+	if err != nil {
+		return fmt.Errorf("failed to flush digest %s: %w", d.ConfigMap, err)
+	}
+	return d.sendDue(&params, due, now)
+}
+
+// digestBackoff spreads the retries of concurrent reconciliations updating the same configmap.
+var digestBackoff = wait.Backoff{Steps: 8, Duration: 10 * time.Millisecond, Factor: 2, Jitter: 0.5}
+
+// update records the given entry, if any, and returns the collection to send, if the period has passed.
+// The configmap is only updated if the entry is new, i.e. the node is notified for the first time
+// within its current state, or the period has passed.
+func (d *Digest) update(params *plugin.Parameters, entry *DigestEntry, now time.Time) (*digestState, error) {
+	var due *digestState
+	// concurrent reconciliations of other nodes may have created or updated the configmap meanwhile
+	retriable := func(err error) bool { return k8serrors.IsConflict(err) || k8serrors.IsAlreadyExists(err) }
+	err := retry.OnError(digestBackoff, retriable, func() error {
+		due = nil
+		var configMap corev1.ConfigMap
+		err := params.Client.Get(params.Ctx, d.ConfigMap, &configMap)
+		if k8serrors.IsNotFound(err) {
+			if entry == nil {
+				return nil
+			}
+			configMap.Name = d.ConfigMap.Name
+			configMap.Namespace = d.ConfigMap.Namespace
+			current := digestState{Start: now, Entries: map[string]DigestEntry{entry.key(): *entry}}
+			if err := setDigestState(&configMap, &current); err != nil {
+				return err
+			}
+			return params.Client.Create(params.Ctx, &configMap)
+		} else if err != nil {
+			return err
+		}
+		current, err := getDigestState(&configMap)
+		if err != nil {
+			return err
+		}
+		changed := false
+		if current.Start.IsZero() {
+			current.Start = now
+			changed = true
+		}
+		if entry != nil {
+			recorded, ok := current.Entries[entry.key()]
+			if !ok || recorded.State != entry.State || !recorded.LastTransition.Equal(entry.LastTransition) {
+				current.Entries[entry.key()] = *entry
+				changed = true
+			}
+		}
+		if now.Sub(current.Start) >= d.Period {
+			if len(current.Entries) > 0 {
+				collected := current
+				due = &collected
+			}
+			current = digestState{Start: now, Entries: make(map[string]DigestEntry)}
+			changed = true
+		}
+		if !changed {
+			return nil
+		}
+		if err := setDigestState(&configMap, &current); err != nil {
+			return err
+		}
+		return params.Client.Update(params.Ctx, &configMap)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return due, nil
+}
+
+func (d *Digest) sendDue(params *plugin.Parameters, due *digestState, now time.Time) error {
+	if due == nil {
+		return nil
+	}
+	message, err := plugin.RenderTemplate(d.Message, aggregateDigest(due, now))
+	if err != nil {
+		return fmt.Errorf("failed to render digest: %w", err)
+	}
+	if err := d.send(params, message); err != nil {
+		return fmt.Errorf("failed to send digest: %w", err)
+	}
+	params.Log.Info("Sent digest", "configMap", d.ConfigMap, "entries", len(due.Entries))
+	return nil
+}
+
+func (d *Digest) send(params *plugin.Parameters, message string) error {
+	if d.Mail != nil {
+		return d.Mail.send(message)
+	}
+	options := []slack.Option{}
+	if d.testURL != "" {
+		options = append(options, slack.OptionAPIURL(d.testURL))
+	}
+	api := slack.New(d.Slack.Token, options...)
+	_, _, err := api.PostMessageContext(params.Ctx, d.Slack.Channel, slack.MsgOptionText(message, false))
+	return err
+}
+
+func getDigestState(configMap *corev1.ConfigMap) (digestState, error) {
+	current := digestState{Entries: make(map[string]DigestEntry)}
+	raw, ok := configMap.Data[digestDataKey]
+	if !ok {
+		return current, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &current); err != nil {
+		return current, fmt.Errorf("failed to parse digest in configmap %s/%s: %w",
+			configMap.Namespace, configMap.Name, err)
+	}
+	if current.Entries == nil {
+		current.Entries = make(map[string]DigestEntry)
+	}
+	return current, nil
+}
+
+func setDigestState(configMap *corev1.ConfigMap, current *digestState) error {
+	raw, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+	configMap.Data[digestDataKey] = string(raw)
+	return nil
+}
+
+// aggregateDigest groups the entries by profile and state, both sorted by name.
+func aggregateDigest(current *digestState, end time.Time) DigestData {
+	data := DigestData{Start: current.Start, End: end, Total: len(current.Entries)}
+	groups := make(map[[2]string]*DigestGroup)
+	for _, entry := range current.Entries {
+		data.Entries = append(data.Entries, entry)
+		key := [2]string{entry.Profile, entry.State}
+		group, ok := groups[key]
+		if !ok {
+			group = &DigestGroup{Profile: entry.Profile, State: entry.State}
+			groups[key] = group
+		}
+		group.Count++
+		group.Nodes = append(group.Nodes, entry.Node)
+	}
+	for _, group := range groups {
+		slices.Sort(group.Nodes)
+		data.Groups = append(data.Groups, *group)
+	}
+	slices.SortFunc(data.Groups, func(a, b DigestGroup) int {
+		return cmp.Or(cmp.Compare(a.Profile, b.Profile), cmp.Compare(a.State, b.State))
+	})
+	slices.SortFunc(data.Entries, func(a, b DigestEntry) int {
+		return cmp.Or(cmp.Compare(a.Profile, b.Profile), cmp.Compare(a.Node, b.Node))
+	})
+	return data
+}
@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sapcc/ucfgwrap"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+var _ = Describe("The digest plugin", func() {
+
+	It("should parse its config", func() {
+		configStr := "configMapName: digest\nconfigMapNamespace: default\nperiod: 1h\n" +
+			"slack:\n  token: token\n  channel: thechannel\n"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base Digest
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(plugin).To(Equal(&Digest{
+			ConfigMap: types.NamespacedName{Name: "digest", Namespace: "default"},
+			Period:    time.Hour,
+			Message:   defaultDigestMessage,
+			Slack:     &DigestSlack{Token: "token", Channel: "thechannel"},
+		}))
+	})
+
+	It("should require exactly one target", func() {
+		configStr := "configMapName: digest\nconfigMapNamespace: default\nperiod: 1h\n"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base Digest
+		_, err = base.New(&config)
+		Expect(err).ToNot(Succeed())
+
+		configStr += "slack:\n  token: token\n  channel: thechannel\n" +
+			"mail:\n  address: localhost:25\n  from: a@example.com\n  to: [b@example.com]\n"
+		config, err = ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		_, err = base.New(&config)
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("should group entries by profile and state", func() {
+		start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		data := aggregateDigest(&digestState{Start: start, Entries: map[string]DigestEntry{
+			"c/first": {Node: "c", Profile: "first", State: "in-maintenance"},
+			"b/first": {Node: "b", Profile: "first", State: "maintenance-required"},
+			"a/first": {Node: "a", Profile: "first", State: "maintenance-required"},
+			"a/other": {Node: "a", Profile: "other", State: "operational"},
+		}}, start.Add(time.Hour))
+		Expect(data.Total).To(Equal(4))
+		Expect(data.Groups).To(Equal([]DigestGroup{
+			{Profile: "first", State: "in-maintenance", Count: 1, Nodes: []string{"c"}},
+			{Profile: "first", State: "maintenance-required", Count: 2, Nodes: []string{"a", "b"}},
+			{Profile: "other", State: "operational", Count: 1, Nodes: []string{"a"}},
+		}))
+		message, err := plugin.RenderTemplate(defaultDigestMessage, data)
+		Expect(err).To(Succeed())
+		Expect(message).To(ContainSubstring("first maintenance-required: 2 node(s): a, b"))
+	})
+
+	Context("with a slack target", func() {
+		var server *httptest.Server
+		var texts []string
+		var k8sClient client.Client
+		var digest Digest
+
+		BeforeEach(func() {
+			texts = make([]string, 0)
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				Expect(r.ParseForm()).To(Succeed())
+				texts = append(texts, r.Form.Get("text"))
+				w.Header().Set("Content-Type", "application/json")
+				_, err := w.Write([]byte(`{"ok":true,"channel":"C123","ts":"1700000000.000100"}`))
+				Expect(err).To(Succeed())
+			}))
+			k8sClient = fake.NewClientBuilder().Build()
+			digest = Digest{
+				ConfigMap: types.NamespacedName{Name: "digest", Namespace: "default"},
+				Period:    time.Hour,
+				Message:   defaultDigestMessage,
+				Slack:     &DigestSlack{Token: "token", Channel: "thechannel"},
+			}
+			digest.SetTestURL(server.URL + "/")
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		notify := func(node, state string) {
+			Expect(digest.Notify(plugin.Parameters{
+				Ctx:     context.Background(),
+				Client:  k8sClient,
+				Node:    &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: node}},
+				Profile: "profile",
+				State:   state,
+				Log:     GinkgoLogr,
+			})).To(Succeed())
+		}
+
+		It("collects notifications until the period passed", func() {
+			notify("a", "maintenance-required")
+			notify("b", "maintenance-required")
+			Expect(texts).To(BeEmpty())
+
+			// move the start of the collection into the past
+			var configMap corev1.ConfigMap
+			Expect(k8sClient.Get(context.Background(), digest.ConfigMap, &configMap)).To(Succeed())
+			current, err := getDigestState(&configMap)
+			Expect(err).To(Succeed())
+			Expect(current.Entries).To(HaveLen(2))
+			current.Start = current.Start.Add(-2 * time.Hour)
+			Expect(setDigestState(&configMap, &current)).To(Succeed())
+			Expect(k8sClient.Update(context.Background(), &configMap)).To(Succeed())
+
+			notify("c", "in-maintenance")
+			Expect(texts).To(HaveLen(1))
+			Expect(texts[0]).To(ContainSubstring("profile maintenance-required: 2 node(s): a, b"))
+			Expect(texts[0]).To(ContainSubstring("profile in-maintenance: 1 node(s): c"))
+
+			Expect(k8sClient.Get(context.Background(), digest.ConfigMap, &configMap)).To(Succeed())
+			current, err = getDigestState(&configMap)
+			Expect(err).To(Succeed())
+			Expect(current.Entries).To(BeEmpty())
+		})
+
+		It("sends the digest on flush without further notifications", func() {
+			notify("a", "maintenance-required")
+			params := plugin.Parameters{Ctx: context.Background(), Client: k8sClient, Log: GinkgoLogr}
+			Expect(digest.Flush(params)).To(Succeed())
+			Expect(texts).To(BeEmpty())
+
+			var configMap corev1.ConfigMap
+			Expect(k8sClient.Get(context.Background(), digest.ConfigMap, &configMap)).To(Succeed())
+			current, err := getDigestState(&configMap)
+			Expect(err).To(Succeed())
+			current.Start = current.Start.Add(-2 * time.Hour)
+			Expect(setDigestState(&configMap, &current)).To(Succeed())
+			Expect(k8sClient.Update(context.Background(), &configMap)).To(Succeed())
+
+			Expect(digest.Flush(params)).To(Succeed())
+			Expect(texts).To(HaveLen(1))
+			Expect(texts[0]).To(ContainSubstring("profile maintenance-required: 1 node(s): a"))
+			Expect(digest.Flush(params)).To(Succeed())
+			Expect(texts).To(HaveLen(1))
+		})
+
+		It("updates the configmap only if a node is notified in a new state", func() {
+			notify("a", "maintenance-required")
+			var configMap corev1.ConfigMap
+			Expect(k8sClient.Get(context.Background(), digest.ConfigMap, &configMap)).To(Succeed())
+			version := configMap.ResourceVersion
+
+			notify("a", "maintenance-required")
+			Expect(k8sClient.Get(context.Background(), digest.ConfigMap, &configMap)).To(Succeed())
+			Expect(configMap.ResourceVersion).To(Equal(version))
+
+			notify("a", "in-maintenance")
+			Expect(k8sClient.Get(context.Background(), digest.ConfigMap, &configMap)).To(Succeed())
+			Expect(configMap.ResourceVersion).ToNot(Equal(version))
+		})
+
+		It("does not fail notifications, if the digest can not be recorded", func() {
+			k8sClient = fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					return k8serrors.NewServiceUnavailable("unavailable")
+				},
+			}).Build()
+			notify("a", "maintenance-required")
+			Expect(texts).To(BeEmpty())
+		})
+	})
+
+})
@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/sapcc/ucfgwrap"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/sapcc/maintenance-controller/common"
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+// Drainable is a check plugin, which passes if all pods of a node could be evicted
+// right now without violating a PodDisruptionBudget.
+type Drainable struct {
+	// should match the filter of the eviction trigger instance, which drains the node
+	PodFilter common.PodFilter
+}
+
+// New creates a new Drainable instance with the given config.
+func (d *Drainable) New(config *ucfgwrap.Config) (plugin.Checker, error) {
+	if config == nil {
+		return &Drainable{}, nil
+	}
+	conf := struct {
+		PodFilter common.PodFilter `config:"podFilter"`
+	}{}
+	if err := config.Unpack(&conf); err != nil {
+		return nil, err
+	}
+	return &Drainable{PodFilter: conf.PodFilter}, nil
+}
+
+func (d *Drainable) ID() string {
+	return "drainable"
+}
+
+type disruptionBudget struct {
+	pdb      *policyv1.PodDisruptionBudget
+	selector labels.Selector
+	// disruptions, which are still allowed after simulating the previous evictions
+	remaining int32
+}
+
+// Check simulates the eviction of all pods, which would be evicted during a drain,
+// against the currently allowed disruptions of the matching PodDisruptionBudgets.
+func (d *Drainable) Check(params plugin.Parameters) (plugin.CheckResult, error) {
+	candidates, err := common.GetPodsForDrain(params.Ctx, params.Client, params.Node.Name)
+	if err != nil {
+		return plugin.Failed(nil), fmt.Errorf("failed to fetch pods for drain: %w", err)
+	}
+	filtered, err := d.PodFilter.Apply(candidates)
+	if err != nil {
+		return plugin.Failed(nil), err
+	}
+	budgets, err := fetchDisruptionBudgets(&params)
+	if err != nil {
+		return plugin.Failed(nil), err
+	}
+	blockingPDBs := make([]string, 0)
+	blockingPods := make([]string, 0)
+	for _, blocking := range filtered.Blocking {
+		key := types.NamespacedName{Namespace: blocking.Pod.Namespace, Name: blocking.Pod.Name}
+		blockingPods = append(blockingPods, fmt.Sprintf("%s (%s)", key, blocking.Reason))
+	}
+	pods := filtered.Drain
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		blocking := simulateEviction(pod, budgets[pod.Namespace])
+		if len(blocking) == 0 {
+			continue
+		}
+		blockingPods = append(blockingPods, types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}.String())
+		for _, budget := range blocking {
+			key := types.NamespacedName{Namespace: budget.pdb.Namespace, Name: budget.pdb.Name}.String()
+			if !slices.Contains(blockingPDBs, key) {
+				blockingPDBs = append(blockingPDBs, key)
+			}
+		}
+	}
+	info := map[string]any{
+		"pods":         len(pods),
+		"skippedPods":  len(filtered.Skipped),
+		"blockingPDBs": blockingPDBs,
+		"blockingPods": blockingPods,
+	}
+	if len(blockingPods) > 0 {
+		return plugin.Failed(info), nil
+	}
+	return plugin.Passed(info), nil
+}
+
+func (d *Drainable) OnTransition(params plugin.Parameters) error {
+	return nil
+}
+
+// fetchDisruptionBudgets returns all PodDisruptionBudgets grouped by namespace.
+func fetchDisruptionBudgets(params *plugin.Parameters) (map[string][]*disruptionBudget, error) {
+	var pdbList policyv1.PodDisruptionBudgetList
+	if err := params.Client.List(params.Ctx, &pdbList); err != nil {
+		return nil, fmt.Errorf("failed to list pod disruption budgets: %w", err)
+	}
+	budgets := make(map[string][]*disruptionBudget)
+	for i := range pdbList.Items {
+		pdb := &pdbList.Items[i]
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			params.Log.Error(err, "failed to parse selector of pod disruption budget",
+				"namespace", pdb.Namespace, "name", pdb.Name)
+			continue
+		}
+		budgets[pdb.Namespace] = append(budgets[pdb.Namespace], &disruptionBudget{
+			pdb:       pdb,
+			selector:  selector,
+			remaining: pdb.Status.DisruptionsAllowed,
+		})
+	}
+	return budgets, nil
+}
+
+// simulateEviction mirrors the decision of the eviction API and returns the budgets,
+// which would reject the eviction of the given pod.
+// Evictions, which would be admitted, consume a disruption of the matching budget.
+func simulateEviction(pod *corev1.Pod, budgets []*disruptionBudget) []*disruptionBudget {
+	matching := make([]*disruptionBudget, 0)
+	for _, budget := range budgets {
+		if budget.selector.Matches(labels.Set(pod.Labels)) {
+			matching = append(matching, budget)
+		}
+	}
+	switch len(matching) {
+	case 0:
+		return nil
+	case 1:
+	default:
+		// the eviction API refuses to evict pods covered by multiple budgets
+		return matching
+	}
+	budget := matching[0]
+	if budget.pdb.Status.ObservedGeneration < budget.pdb.Generation {
+		return matching
+	}
+	if !isPodReady(pod) && canEvictUnhealthy(budget.pdb) {
+		return nil
+	}
+	if budget.remaining <= 0 {
+		return matching
+	}
+	budget.remaining--
+	return nil
+}
+
+// canEvictUnhealthy returns whether the budget allows evicting pods, which are not ready,
+// without consuming a disruption.
+func canEvictUnhealthy(pdb *policyv1.PodDisruptionBudget) bool {
+	policy := pdb.Spec.UnhealthyPodEvictionPolicy
+	if policy != nil && *policy == policyv1.AlwaysAllow {
+		return true
+	}
+	return pdb.Status.DesiredHealthy > 0 && pdb.Status.CurrentHealthy >= pdb.Status.DesiredHealthy
+}
@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sapcc/maintenance-controller/common"
+	"github.com/sapcc/maintenance-controller/constants"
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+var _ = Describe("The drainable plugin", func() {
+
+	var params plugin.Parameters
+
+	makePod := func(name string, ready bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"app": "guarded"},
+			},
+			Spec: corev1.PodSpec{NodeName: "targetnode"},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+
+	makePDB := func(name string, allowed int32) *policyv1.PodDisruptionBudget {
+		return &policyv1.PodDisruptionBudget{
+			ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector: &v1.LabelSelector{MatchLabels: map[string]string{"app": "guarded"}},
+			},
+			Status: policyv1.PodDisruptionBudgetStatus{
+				DisruptionsAllowed: allowed,
+				CurrentHealthy:     1,
+				DesiredHealthy:     2,
+			},
+		}
+	}
+
+	withObjects := func(objs ...client.Object) {
+		k8sClient := fake.NewClientBuilder().
+			WithObjects(objs...).
+			WithIndex(&corev1.Pod{}, "spec.nodeName", func(o client.Object) []string {
+				pod, ok := o.(*corev1.Pod)
+				if !ok {
+					return []string{}
+				}
+				return []string{pod.Spec.NodeName}
+			}).
+			Build()
+		params = plugin.Parameters{
+			Client: k8sClient,
+			Ctx:    context.Background(),
+			Log:    GinkgoLogr,
+			Node:   &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "targetnode"}},
+		}
+	}
+
+	It("can be created without configuration", func() {
+		var base Drainable
+		plugin, err := base.New(nil)
+		Expect(err).To(Succeed())
+		Expect(plugin).To(Equal(&Drainable{}))
+	})
+
+	It("passes if pods are not covered by a budget", func() {
+		withObjects(makePod("first", true))
+		result, err := (&Drainable{}).Check(params)
+		Expect(err).To(Succeed())
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("passes if the budget allows all disruptions", func() {
+		withObjects(makePod("first", true), makePod("second", true), makePDB("pdb", 2))
+		result, err := (&Drainable{}).Check(params)
+		Expect(err).To(Succeed())
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("fails if the budget does not allow enough disruptions", func() {
+		withObjects(makePod("first", true), makePod("second", true), makePDB("pdb", 1))
+		result, err := (&Drainable{}).Check(params)
+		Expect(err).To(Succeed())
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Info["blockingPDBs"]).To(HaveExactElements("default/pdb"))
+		Expect(result.Info["blockingPods"]).To(HaveLen(1))
+	})
+
+	It("fails if a pod is covered by multiple budgets", func() {
+		withObjects(makePod("first", true), makePDB("pdb", 1), makePDB("other", 1))
+		result, err := (&Drainable{}).Check(params)
+		Expect(err).To(Succeed())
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Info["blockingPDBs"]).To(ConsistOf("default/pdb", "default/other"))
+		Expect(result.Info["blockingPods"]).To(HaveExactElements("default/first"))
+	})
+
+	It("passes for unhealthy pods with the AlwaysAllow policy", func() {
+		pdb := makePDB("pdb", 0)
+		policy := policyv1.AlwaysAllow
+		pdb.Spec.UnhealthyPodEvictionPolicy = &policy
+		withObjects(makePod("first", false), pdb)
+		result, err := (&Drainable{}).Check(params)
+		Expect(err).To(Succeed())
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("fails for unhealthy pods if the budget is not healthy", func() {
+		withObjects(makePod("first", false), makePDB("pdb", 0))
+		result, err := (&Drainable{}).Check(params)
+		Expect(err).To(Succeed())
+		Expect(result.Passed).To(BeFalse())
+	})
+
+	It("ignores pods on other nodes and finished pods", func() {
+		other := makePod("other", true)
+		other.Spec.NodeName = "othernode"
+		finished := makePod("finished", true)
+		finished.Status.Phase = corev1.PodSucceeded
+		withObjects(other, finished, makePDB("pdb", 0))
+		result, err := (&Drainable{}).Check(params)
+		Expect(err).To(Succeed())
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("applies the pod filter", func() {
+		optOut := makePod("optout", true)
+		optOut.Annotations = map[string]string{constants.DrainSkipAnnotationKey: constants.TrueStr}
+		unmanaged := makePod("unmanaged", true)
+		unmanaged.Labels = nil
+		withObjects(optOut, unmanaged, makePDB("pdb", 0))
+		drainable := Drainable{PodFilter: common.PodFilter{RefuseUnmanaged: true}}
+		result, err := drainable.Check(params)
+		Expect(err).To(Succeed())
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Info["blockingPDBs"]).To(BeEmpty())
+		Expect(result.Info["blockingPods"]).To(HaveExactElements(HavePrefix("default/unmanaged")))
+		Expect(result.Info["skippedPods"]).To(Equal(1))
+	})
+
+})
@@ -9,8 +9,10 @@ import (
 	"time"
 
 	"github.com/sapcc/ucfgwrap"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/sapcc/maintenance-controller/common"
+	"github.com/sapcc/maintenance-controller/metrics"
 	"github.com/sapcc/maintenance-controller/plugin"
 )
 
@@ -31,14 +33,18 @@ type Eviction struct {
 	DeletionTimeout time.Duration
 	EvictionTimeout time.Duration
 	ForceEviction   bool
+	PodFilter       common.PodFilter
+	DrainOrder      common.DrainOrder
 }
 
 func (e *Eviction) New(config *ucfgwrap.Config) (plugin.Trigger, error) {
 	conf := struct {
-		Action          string        `config:"action" validate:"required"`
-		DeletionTimeout time.Duration `config:"deletionTimeout"`
-		EvictionTimeout time.Duration `config:"evictionTimeout"`
-		ForceEviction   bool          `config:"forceEviction"`
+		Action          string            `config:"action" validate:"required"`
+		DeletionTimeout time.Duration     `config:"deletionTimeout"`
+		EvictionTimeout time.Duration     `config:"evictionTimeout"`
+		ForceEviction   bool              `config:"forceEviction"`
+		PodFilter       common.PodFilter  `config:"podFilter"`
+		DrainOrder      common.DrainOrder `config:"drainOrder"`
 	}{
 		DeletionTimeout: 10 * time.Minute,
 		EvictionTimeout: 10 * time.Minute,
@@ -54,6 +60,8 @@ func (e *Eviction) New(config *ucfgwrap.Config) (plugin.Trigger, error) {
 		DeletionTimeout: conf.DeletionTimeout,
 		EvictionTimeout: conf.EvictionTimeout,
 		ForceEviction:   conf.ForceEviction,
+		PodFilter:       conf.PodFilter,
+		DrainOrder:      conf.DrainOrder,
 	}, nil
 }
 
@@ -64,10 +72,16 @@ func (e *Eviction) ID() string {
 func (e *Eviction) Trigger(params plugin.Parameters) error {
 	switch e.Action {
 	case Cordon:
-		params.Node.Spec.Unschedulable = true
-		return nil
+		return common.Cordon(params.Node, e.cordonReason(params))
 	case Uncordon:
-		params.Node.Spec.Unschedulable = false
+		uncordoned, reason := common.Uncordon(params.Node)
+		if !uncordoned {
+			params.Log.Info("Skipped uncordoning node", "node", params.Node.Name, "reason", reason)
+			if params.Recorder != nil {
+				params.Recorder.Eventf(params.Node, nil, corev1.EventTypeNormal, "UncordonSkipped", "Uncordon",
+					"Skipped uncordon for profile %s: %s", params.Profile, reason)
+			}
+		}
 		return nil
 	case Drain:
 		// original comment:
@@ -79,7 +93,17 @@ func (e *Eviction) Trigger(params plugin.Parameters) error {
 		// which consistently drops state.
 		// Assigning true to unschedulable here is a sanity action.
 		// The user should configure to run the cordon action before running the drain action.
-		params.Node.Spec.Unschedulable = true
+		if err := common.Cordon(params.Node, e.cordonReason(params)); err != nil {
+			return err
+		}
+		progress := params.Drain
+		if progress == nil {
+			progress = &common.DrainProgress{}
+		}
+		before := *progress
+		if !before.InProgress() {
+			before = common.DrainProgress{}
+		}
 		drained, err := common.EnsureDrain(params.Ctx, params.Node, params.Log, common.DrainParameters{
 			AwaitDeletion: common.WaitParameters{
 				Period:  defaultPeriod,
@@ -93,7 +117,11 @@ func (e *Eviction) Trigger(params plugin.Parameters) error {
 			Clientset:     params.Clientset,
 			ForceEviction: e.ForceEviction,
 			Recorder:      params.Recorder,
+			Filter:        e.PodFilter,
+			Order:         e.DrainOrder,
+			Progress:      progress,
 		})
+		recordDrainMetrics(params.Profile, &before, progress)
 		if err != nil {
 			params.Log.Error(err, "Drain encountered errors; will retry next reconcile", "node", params.Node.Name)
 			return &plugin.RetryError{Message: err.Error()}
@@ -106,3 +134,20 @@ func (e *Eviction) Trigger(params plugin.Parameters) error {
 	}
 	return fmt.Errorf("invalid eviction action: %s", e.Action)
 }
+
+func (e *Eviction) cordonReason(params plugin.Parameters) string {
+	return fmt.Sprintf("%s by profile %s", e.Action, params.Profile)
+}
+
+// recordDrainMetrics records the pods drained since the before snapshot and the duration of a finished drain.
+func recordDrainMetrics(profile string, before, after *common.DrainProgress) {
+	if evicted := after.Evicted - before.Evicted; evicted > 0 {
+		metrics.RecordDrainedPods(profile, "evicted", evicted)
+	}
+	if forceDeleted := len(after.ForceDeleted) - len(before.ForceDeleted); forceDeleted > 0 {
+		metrics.RecordDrainedPods(profile, "force_deleted", forceDeleted)
+	}
+	if before.Finished.IsZero() && !after.Finished.IsZero() {
+		metrics.RecordDrainDuration(profile, after.Duration())
+	}
+}
@@ -9,6 +9,8 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/sapcc/ucfgwrap"
+
+	"github.com/sapcc/maintenance-controller/common"
 )
 
 var _ = Describe("The eviction plugin", func() {
@@ -60,4 +62,47 @@ var _ = Describe("The eviction plugin", func() {
 		}))
 	})
 
+	It("can parse it's pod filter configuration", func() {
+		configStr := "action: drain\npodFilter:\n  emptyDir: refuse\n  skipNamespaces: [kube-system]\n  skipCompleted: true"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+
+		var base Eviction
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		eviction, ok := plugin.(*Eviction)
+		Expect(ok).To(BeTrue())
+		Expect(eviction.PodFilter).To(Equal(common.PodFilter{
+			EmptyDir:       common.EmptyDirRefuse,
+			SkipNamespaces: []string{"kube-system"},
+			SkipCompleted:  true,
+		}))
+	})
+
+	It("can parse it's drain order configuration", func() {
+		configStr := "action: drain\ndrainOrder:\n  by: ownerKind\n  ownerKinds: [Job, StatefulSet]\n  waitForReplacement: true"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+
+		var base Eviction
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		eviction, ok := plugin.(*Eviction)
+		Expect(ok).To(BeTrue())
+		Expect(eviction.DrainOrder).To(Equal(common.DrainOrder{
+			By:                 common.OrderOwnerKind,
+			OwnerKinds:         []string{"Job", "StatefulSet"},
+			WaitForReplacement: true,
+		}))
+	})
+
+	It("rejects an invalid pod filter configuration", func() {
+		config, err := ucfgwrap.FromYAML([]byte("action: drain\npodFilter:\n  emptyDir: keep"))
+		Expect(err).To(Succeed())
+
+		var base Eviction
+		_, err = base.New(&config)
+		Expect(err).To(HaveOccurred())
+	})
+
 })
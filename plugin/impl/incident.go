@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/sapcc/ucfgwrap"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+const (
+	pagerDutyEventsURL string = "https://events.pagerduty.com/v2/enqueue"
+	// secret key read for the routing key
+	routingKeySecretKey string = "routingKey"
+)
+
+var incidentSeverities = []string{"critical", "error", "warning", "info"}
+
+// Incident is a notification plugin, which triggers incidents using the PagerDuty Events v2 API.
+// Incidents are deduplicated per node and profile and resolved once the profile is operational again.
+type Incident struct {
+	URL        string
+	RoutingKey string
+	// secret holding the routing key, used if RoutingKey is empty
+	Secret client.ObjectKey
+	// rendered as template
+	Summary  string
+	Severity string
+	// rendered as template
+	Source    string
+	Component string
+	Group     string
+	Class     string
+	// values are rendered as templates
+	Details map[string]string
+	Timeout time.Duration
+}
+
+// New creates a new Incident instance with the given config.
+func (i *Incident) New(config *ucfgwrap.Config) (plugin.Notifier, error) {
+	conf := struct {
+		URL        string `config:"url"`
+		RoutingKey string `config:"routingKey"`
+		Secret     struct {
+			Name      string `config:"name"`
+			Namespace string `config:"namespace"`
+		} `config:"secret"`
+		Summary   string            `config:"summary"`
+		Severity  string            `config:"severity"`
+		Source    string            `config:"source"`
+		Component string            `config:"component"`
+		Group     string            `config:"group"`
+		Class     string            `config:"class"`
+		Details   map[string]string `config:"details"`
+		Timeout   time.Duration     `config:"timeout"`
+	}{
+		URL:      pagerDutyEventsURL,
+		Summary:  "Maintenance of node {{ .Node.Name }} requires attention in state {{ .State }} of profile {{ .Profile }}",
+		Severity: "error",
+		Source:   "{{ .Node.Name }}",
+		Timeout:  10 * time.Second,
+	}
+	if err := config.Unpack(&conf); err != nil {
+		return nil, err
+	}
+	if conf.RoutingKey == "" && (conf.Secret.Name == "" || conf.Secret.Namespace == "") {
+		return nil, errors.New("an incident notifier needs either a routingKey or a secret name and namespace")
+	}
+	if !slices.Contains(incidentSeverities, conf.Severity) {
+		return nil, fmt.Errorf("got invalid severity %s, expected one of %v", conf.Severity, incidentSeverities)
+	}
+	return &Incident{
+		URL:        conf.URL,
+		RoutingKey: conf.RoutingKey,
+		Secret:     client.ObjectKey{Name: conf.Secret.Name, Namespace: conf.Secret.Namespace},
+		Summary:    conf.Summary,
+		Severity:   conf.Severity,
+		Source:     conf.Source,
+		Component:  conf.Component,
+		Group:      conf.Group,
+		Class:      conf.Class,
+		Details:    conf.Details,
+		Timeout:    conf.Timeout,
+	}, nil
+}
+
+func (i *Incident) ID() string {
+	return "incident"
+}
+
+// Notify triggers an incident, unless the state is operational, in which case the incident is resolved.
+func (i *Incident) Notify(params plugin.Parameters) error {
+	if params.State == "operational" {
+		return i.resolve(&params)
+	}
+	summary, err := plugin.RenderNotificationTemplate(i.Summary, &params)
+	if err != nil {
+		return fmt.Errorf("failed to render incident summary: %w", err)
+	}
+	source, err := plugin.RenderNotificationTemplate(i.Source, &params)
+	if err != nil {
+		return fmt.Errorf("failed to render incident source: %w", err)
+	}
+	details, err := renderValues(i.Details, &params)
+	if err != nil {
+		return fmt.Errorf("failed to render incident details: %w", err)
+	}
+	details["profile"] = params.Profile
+	details["state"] = params.State
+	details["lastTransition"] = params.LastTransition.UTC().Format(time.RFC3339)
+	payload := map[string]any{
+		"summary":        summary,
+		"source":         source,
+		"severity":       i.Severity,
+		"timestamp":      time.Now().UTC().Format(time.RFC3339),
+		"custom_details": details,
+	}
+	for key, value := range map[string]string{"component": i.Component, "group": i.Group, "class": i.Class} {
+		if value != "" {
+			payload[key] = value
+		}
+	}
+	return i.send(&params, "trigger", payload)
+}
+
+// NotifyStateChange resolves the incident once the profile is operational again.
+func (i *Incident) NotifyStateChange(params plugin.Parameters, previous string) error {
+	if params.State != "operational" || previous == "operational" {
+		return nil
+	}
+	return i.resolve(&params)
+}
+
+func (i *Incident) resolve(params *plugin.Parameters) error {
+	return i.send(params, "resolve", nil)
+}
+
+func (i *Incident) send(params *plugin.Parameters, action string, payload map[string]any) error {
+	routingKey, err := i.routingKey(params)
+	if err != nil {
+		return err
+	}
+	event := map[string]any{
+		"routing_key":  routingKey,
+		"event_action": action,
+		"dedup_key":    DedupKey(params),
+		"client":       "maintenance-controller",
+	}
+	if payload != nil {
+		event["payload"] = payload
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(params.Ctx, i.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, i.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	rsp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %s event to %s: %w", action, i.URL, err)
+	}
+	defer rsp.Body.Close()
+	rspBody, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return err
+	}
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return fmt.Errorf("events api %s returned status code %d for %s event: %s", i.URL, rsp.StatusCode, action, rspBody)
+	}
+	params.Log.Info("Sent incident event", "node", params.Node.Name, "action", action, "dedupKey", DedupKey(params))
+	return nil
+}
+
+func (i *Incident) routingKey(params *plugin.Parameters) (string, error) {
+	if i.RoutingKey != "" {
+		return i.RoutingKey, nil
+	}
+	secret, err := secrets.get(params, i.Secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve routing key secret %s: %w", i.Secret, err)
+	}
+	return secretValue(secret, routingKeySecretKey)
+}
+
+// DedupKey identifies the incident of a node and a profile, so repeated notifications
+// update the same incident and a resolve event closes it.
+func DedupKey(params *plugin.Parameters) string {
+	return fmt.Sprintf("maintenance-controller/%s/%s", params.Node.Name, params.Profile)
+}
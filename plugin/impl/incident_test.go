@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sapcc/ucfgwrap"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+var _ = Describe("The incident plugin", func() {
+
+	It("can parse its configuration", func() {
+		configStr := "url: http://events.local/v2/enqueue\nroutingKey: key\nseverity: critical\n" +
+			"component: kubelet\ndetails:\n  zone: '{{ index .Node.Labels \"zone\" }}'"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base Incident
+		notifier, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(notifier).To(Equal(&Incident{
+			URL:        "http://events.local/v2/enqueue",
+			RoutingKey: "key",
+			Summary:    "Maintenance of node {{ .Node.Name }} requires attention in state {{ .State }} of profile {{ .Profile }}",
+			Severity:   "critical",
+			Source:     "{{ .Node.Name }}",
+			Component:  "kubelet",
+			Details:    map[string]string{"zone": "{{ index .Node.Labels \"zone\" }}"},
+			Timeout:    10 * time.Second,
+		}))
+	})
+
+	It("rejects invalid configurations", func() {
+		for _, configStr := range []string{
+			"url: http://events.local",
+			"routingKey: key\nseverity: fatal",
+			"secret:\n  name: pagerduty",
+		} {
+			config, err := ucfgwrap.FromYAML([]byte(configStr))
+			Expect(err).To(Succeed())
+			var base Incident
+			_, err = base.New(&config)
+			Expect(err).To(HaveOccurred(), configStr)
+		}
+	})
+
+	Context("with a fake events api", func() {
+		var (
+			server *httptest.Server
+			events chan map[string]any
+			status int
+			params plugin.Parameters
+		)
+
+		BeforeEach(func() {
+			events = make(chan map[string]any, 10)
+			status = http.StatusAccepted
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				var event map[string]any
+				Expect(json.NewDecoder(r.Body).Decode(&event)).To(Succeed())
+				events <- event
+				w.WriteHeader(status)
+			}))
+			k8sClient := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+				ObjectMeta: v1.ObjectMeta{Name: "pagerduty", Namespace: "default"},
+				Data:       map[string][]byte{"routingKey": []byte("secret-key")},
+			}).Build()
+			params = plugin.Parameters{
+				Client:         k8sClient,
+				Ctx:            context.Background(),
+				Log:            GinkgoLogr,
+				Node:           &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "node01"}},
+				Profile:        "reboot",
+				State:          "in-maintenance",
+				LastTransition: time.Date(2026, time.March, 3, 10, 0, 0, 0, time.UTC),
+			}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		makeIncident := func() Incident {
+			return Incident{
+				URL:      server.URL,
+				Secret:   client.ObjectKey{Name: "pagerduty", Namespace: "default"},
+				Summary:  "{{ .Node.Name }} is stuck",
+				Severity: "error",
+				Source:   "{{ .Node.Name }}",
+				Class:    "maintenance",
+				Timeout:  time.Second,
+			}
+		}
+
+		It("triggers an incident with a dedup key", func() {
+			incident := makeIncident()
+			Expect(incident.Notify(params)).To(Succeed())
+			var event map[string]any
+			Eventually(events).Should(Receive(&event))
+			Expect(event).To(HaveKeyWithValue("routing_key", "secret-key"))
+			Expect(event).To(HaveKeyWithValue("event_action", "trigger"))
+			Expect(event).To(HaveKeyWithValue("dedup_key", "maintenance-controller/node01/reboot"))
+			Expect(event).To(HaveKeyWithValue("payload", SatisfyAll(
+				HaveKeyWithValue("summary", "node01 is stuck"),
+				HaveKeyWithValue("source", "node01"),
+				HaveKeyWithValue("severity", "error"),
+				HaveKeyWithValue("class", "maintenance"),
+				HaveKeyWithValue("custom_details", map[string]any{
+					"profile":        "reboot",
+					"state":          "in-maintenance",
+					"lastTransition": "2026-03-03T10:00:00Z",
+				}),
+			)))
+		})
+
+		It("resolves the incident once the profile is operational", func() {
+			incident := makeIncident()
+			params.State = "operational"
+			Expect(incident.NotifyStateChange(params, "operational")).To(Succeed())
+			Expect(events).To(BeEmpty())
+			Expect(incident.NotifyStateChange(params, "in-maintenance")).To(Succeed())
+			var event map[string]any
+			Eventually(events).Should(Receive(&event))
+			Expect(event).To(HaveKeyWithValue("event_action", "resolve"))
+			Expect(event).To(HaveKeyWithValue("dedup_key", "maintenance-controller/node01/reboot"))
+			Expect(event).ToNot(HaveKey("payload"))
+		})
+
+		It("does not trigger on state changes to other states", func() {
+			incident := makeIncident()
+			Expect(incident.NotifyStateChange(params, "maintenance-required")).To(Succeed())
+			Expect(events).To(BeEmpty())
+		})
+
+		It("fails on rejected events", func() {
+			status = http.StatusBadRequest
+			incident := makeIncident()
+			Expect(incident.Notify(params)).ToNot(Succeed())
+		})
+	})
+
+})
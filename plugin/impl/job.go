@@ -0,0 +1,303 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sapcc/ucfgwrap"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sapcc/maintenance-controller/constants"
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+// job names end up as label values on their pods, which are limited to 63 characters.
+const maxJobPrefixLength int = 52
+
+const jobHashLength int = 10
+
+// JobRef identifies the job, which runs for a node during its current state.
+type JobRef struct {
+	// name prefix of the job, the full name is made unique per node, profile and transition
+	Name      string
+	Namespace string
+}
+
+type jobRefConfig struct {
+	Name      string `config:"name" validate:"required"`
+	Namespace string `config:"namespace" validate:"required"`
+}
+
+func (jc *jobRefConfig) toJobRef() (JobRef, error) {
+	if len(jc.Name) > maxJobPrefixLength {
+		return JobRef{}, fmt.Errorf("job name %s exceeds %d characters", jc.Name, maxJobPrefixLength)
+	}
+	return JobRef{Name: jc.Name, Namespace: jc.Namespace}, nil
+}
+
+// Key returns the namespaced name of the job for the given parameters.
+func (jr *JobRef) Key(params *plugin.Parameters) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: jr.Namespace,
+		Name:      fmt.Sprintf("%s-%s", jr.Name, IdempotencyKey(params)[:jobHashLength]),
+	}
+}
+
+// fetch returns the job for the current transition or nil if it does not exist.
+func (jr *JobRef) fetch(params *plugin.Parameters) (*batchv1.Job, error) {
+	var job batchv1.Job
+	err := params.Client.Get(params.Ctx, jr.Key(params), &job)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// latest returns the newest job created for the node and profile of the given parameters or nil if none exists.
+// Unlike fetch it does not depend on the time of the last transition, so a job created by a trigger
+// while transitioning into the current state is found as well.
+func (jr *JobRef) latest(params *plugin.Parameters) (*batchv1.Job, error) {
+	var jobs batchv1.JobList
+	err := params.Client.List(params.Ctx, &jobs,
+		client.InNamespace(jr.Namespace), client.MatchingLabels{constants.JobLabelKey: jr.Name})
+	if err != nil {
+		return nil, err
+	}
+	var newest *batchv1.Job
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if job.Annotations[constants.JobNodeAnnotationKey] != params.Node.Name ||
+			job.Annotations[constants.JobProfileAnnotationKey] != params.Profile {
+			continue
+		}
+		if newest == nil || newest.CreationTimestamp.Before(&job.CreationTimestamp) {
+			newest = job
+		}
+	}
+	return newest, nil
+}
+
+// jobFinished returns whether the job either completed or failed.
+func jobFinished(job *batchv1.Job) (batchv1.JobConditionType, bool) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		if condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed {
+			return condition.Type, true
+		}
+	}
+	return "", false
+}
+
+// RunJob is a trigger plugin, which runs a job for the node and awaits its completion.
+type RunJob struct {
+	Job JobRef
+	// job manifest, which is rendered as template before parsing
+	Template string
+}
+
+// New creates a new RunJob instance with the given config.
+func (r *RunJob) New(config *ucfgwrap.Config) (plugin.Trigger, error) {
+	conf := struct {
+		Name      string `config:"name" validate:"required"`
+		Namespace string `config:"namespace" validate:"required"`
+		Template  string `config:"template" validate:"required"`
+	}{}
+	if err := config.Unpack(&conf); err != nil {
+		return nil, err
+	}
+	refConf := jobRefConfig{Name: conf.Name, Namespace: conf.Namespace}
+	job, err := refConf.toJobRef()
+	if err != nil {
+		return nil, err
+	}
+	return &RunJob{Job: job, Template: conf.Template}, nil
+}
+
+func (r *RunJob) ID() string {
+	return "runJob"
+}
+
+// Trigger creates the job for the current transition if required and
+// returns a plugin.RetryError until the job completed.
+// Jobs of previous transitions for the same node and profile are deleted.
+func (r *RunJob) Trigger(params plugin.Parameters) error {
+	if err := r.collectGarbage(&params); err != nil {
+		params.Log.Error(err, "failed to delete stale jobs", "job", r.Job.Name)
+	}
+	job, err := r.Job.fetch(&params)
+	if err != nil {
+		return fmt.Errorf("failed to fetch job: %w", err)
+	}
+	if job == nil {
+		job, err = r.renderJob(&params)
+		if err != nil {
+			return err
+		}
+		err := params.Client.Create(params.Ctx, job)
+		// the cache may not contain a job, which has been created by a recent reconcile
+		if err != nil && !k8serrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create job %s/%s: %w", job.Namespace, job.Name, err)
+		}
+		if err == nil {
+			params.Log.Info("Created job", "job", job.Name, "namespace", job.Namespace)
+		}
+		return &plugin.RetryError{Message: fmt.Sprintf("job %s/%s has been created", job.Namespace, job.Name)}
+	}
+	conditionType, finished := jobFinished(job)
+	if !finished {
+		return &plugin.RetryError{Message: fmt.Sprintf("job %s/%s is still running", job.Namespace, job.Name)}
+	}
+	if conditionType == batchv1.JobFailed {
+		return fmt.Errorf("job %s/%s failed", job.Namespace, job.Name)
+	}
+	return nil
+}
+
+func (r *RunJob) renderJob(params *plugin.Parameters) (*batchv1.Job, error) {
+	var job batchv1.Job
+	if err := renderManifest(r.Template, params, &job); err != nil {
+		return nil, fmt.Errorf("failed to render job template: %w", err)
+	}
+	key := r.Job.Key(params)
+	job.Name = key.Name
+	job.Namespace = key.Namespace
+	if job.Labels == nil {
+		job.Labels = make(map[string]string)
+	}
+	job.Labels[constants.JobLabelKey] = r.Job.Name
+	if job.Annotations == nil {
+		job.Annotations = make(map[string]string)
+	}
+	job.Annotations[constants.JobNodeAnnotationKey] = params.Node.Name
+	job.Annotations[constants.JobProfileAnnotationKey] = params.Profile
+	injectNodeScheduling(&job.Spec.Template.Spec, params.Node.Name)
+	if job.Spec.Template.Spec.RestartPolicy == "" {
+		job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+	return &job, nil
+}
+
+// renderManifest renders the given template and decodes the resulting YAML or JSON into obj.
+func renderManifest(templateStr string, params *plugin.Parameters, obj any) error {
+	rendered, err := plugin.RenderNotificationTemplate(templateStr, params)
+	if err != nil {
+		return err
+	}
+	return yaml.NewYAMLOrJSONDecoder(strings.NewReader(rendered), len(rendered)).Decode(obj)
+}
+
+// injectNodeScheduling pins the pod onto the given node, even if it is cordoned.
+func injectNodeScheduling(spec *corev1.PodSpec, nodeName string) {
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	spec.Affinity.NodeAffinity = &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchFields: []corev1.NodeSelectorRequirement{{
+					Key:      "metadata.name",
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{nodeName},
+				}},
+			}},
+		},
+	}
+	spec.Tolerations = append(spec.Tolerations, corev1.Toleration{
+		Key:      corev1.TaintNodeUnschedulable,
+		Operator: corev1.TolerationOpExists,
+		Effect:   corev1.TaintEffectNoSchedule,
+	})
+}
+
+// collectGarbage deletes jobs, which have been created for the same node and profile during previous transitions.
+func (r *RunJob) collectGarbage(params *plugin.Parameters) error {
+	var jobs batchv1.JobList
+	err := params.Client.List(params.Ctx, &jobs,
+		client.InNamespace(r.Job.Namespace), client.MatchingLabels{constants.JobLabelKey: r.Job.Name})
+	if err != nil {
+		return err
+	}
+	current := r.Job.Key(params).Name
+	var errs []error
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if job.Name == current || job.Annotations[constants.JobNodeAnnotationKey] != params.Node.Name ||
+			job.Annotations[constants.JobProfileAnnotationKey] != params.Profile {
+			continue
+		}
+		err := params.Client.Delete(params.Ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !k8serrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete job %s/%s: %w", job.Namespace, job.Name, err))
+			continue
+		}
+		params.Log.Info("Deleted stale job", "job", job.Name, "namespace", job.Namespace)
+	}
+	return errors.Join(errs...)
+}
+
+// JobSucceeded is a check plugin, which passes once the latest job created for the node and profile completed.
+// The job is looked up using its labels and annotations instead of its name, as the name depends on the time
+// of the last transition, which changes once the runJob trigger succeeded.
+type JobSucceeded struct {
+	Job JobRef
+}
+
+// New creates a new JobSucceeded instance with the given config.
+func (js *JobSucceeded) New(config *ucfgwrap.Config) (plugin.Checker, error) {
+	var conf jobRefConfig
+	if err := config.Unpack(&conf); err != nil {
+		return nil, err
+	}
+	job, err := conf.toJobRef()
+	if err != nil {
+		return nil, err
+	}
+	return &JobSucceeded{Job: job}, nil
+}
+
+func (js *JobSucceeded) ID() string {
+	return "jobSucceeded"
+}
+
+// Check passes if the latest job for the node and profile has the Complete condition.
+func (js *JobSucceeded) Check(params plugin.Parameters) (plugin.CheckResult, error) {
+	info := map[string]any{"job": js.Job.Namespace + "/" + js.Job.Name}
+	job, err := js.Job.latest(&params)
+	if err != nil {
+		return plugin.Failed(info), err
+	}
+	if job == nil {
+		info["reason"] = "job does not exist"
+		return plugin.Failed(info), nil
+	}
+	info["job"] = job.Namespace + "/" + job.Name
+	conditionType, finished := jobFinished(job)
+	if !finished {
+		info["reason"] = "job is still running"
+		return plugin.Failed(info), nil
+	}
+	if conditionType == batchv1.JobFailed {
+		info["reason"] = "job failed"
+		return plugin.Failed(info), nil
+	}
+	return plugin.Passed(info), nil
+}
+
+func (js *JobSucceeded) OnTransition(params plugin.Parameters) error {
+	return nil
+}
@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sapcc/ucfgwrap"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/sapcc/maintenance-controller/constants"
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+const jobTemplate string = `
+metadata:
+  labels:
+    node: "{{ .Node.Name }}"
+spec:
+  template:
+    spec:
+      containers:
+      - name: check
+        image: busybox
+        command: ["echo", "{{ .Node.Name }}"]
+`
+
+var _ = Describe("The runJob plugin", func() {
+
+	It("can parse its configuration", func() {
+		config, err := ucfgwrap.FromYAML([]byte("name: check\nnamespace: default\ntemplate: abc"))
+		Expect(err).To(Succeed())
+		var base RunJob
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(plugin).To(Equal(&RunJob{
+			Job:      JobRef{Name: "check", Namespace: "default"},
+			Template: "abc",
+		}))
+	})
+
+	It("rejects too long name prefixes", func() {
+		configStr := "name: " + strings.Repeat("a", 53) + "\nnamespace: default\ntemplate: abc"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base RunJob
+		_, err = base.New(&config)
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("with a fake client", func() {
+		var (
+			k8sClient client.WithWatch
+			params    plugin.Parameters
+			runJob    RunJob
+		)
+
+		BeforeEach(func() {
+			k8sClient = fake.NewClientBuilder().WithStatusSubresource(&batchv1.Job{}).Build()
+			params = plugin.Parameters{
+				Client:         k8sClient,
+				Ctx:            context.Background(),
+				Log:            GinkgoLogr,
+				Node:           &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "targetnode"}},
+				Profile:        "someprofile",
+				LastTransition: time.Date(2026, time.March, 3, 10, 0, 0, 0, time.UTC),
+			}
+			runJob = RunJob{
+				Job:      JobRef{Name: "check", Namespace: "default"},
+				Template: jobTemplate,
+			}
+		})
+
+		setCondition := func(ctx SpecContext, conditionType batchv1.JobConditionType) {
+			var job batchv1.Job
+			Expect(k8sClient.Get(ctx, runJob.Job.Key(&params), &job)).To(Succeed())
+			job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+				Type:   conditionType,
+				Status: corev1.ConditionTrue,
+			})
+			Expect(k8sClient.Status().Update(ctx, &job)).To(Succeed())
+		}
+
+		It("creates a job pinned to the node", func(ctx SpecContext) {
+			err := runJob.Trigger(params)
+			var retryErr *plugin.RetryError
+			Expect(errors.As(err, &retryErr)).To(BeTrue())
+
+			var job batchv1.Job
+			Expect(k8sClient.Get(ctx, runJob.Job.Key(&params), &job)).To(Succeed())
+			Expect(job.Name).To(HavePrefix("check-"))
+			Expect(job.Labels).To(HaveKeyWithValue("node", "targetnode"))
+			Expect(job.Labels).To(HaveKeyWithValue(constants.JobLabelKey, "check"))
+			Expect(job.Annotations).To(HaveKeyWithValue(constants.JobNodeAnnotationKey, "targetnode"))
+			podSpec := job.Spec.Template.Spec
+			Expect(podSpec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+			Expect(podSpec.Containers[0].Command).To(HaveExactElements("echo", "targetnode"))
+			terms := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+			Expect(terms).To(HaveLen(1))
+			Expect(terms[0].MatchFields[0].Values).To(HaveExactElements("targetnode"))
+			Expect(podSpec.Tolerations).To(ContainElement(HaveField("Key", corev1.TaintNodeUnschedulable)))
+		})
+
+		It("succeeds once the job completed", func(ctx SpecContext) {
+			var retryErr *plugin.RetryError
+			Expect(errors.As(runJob.Trigger(params), &retryErr)).To(BeTrue())
+			Expect(errors.As(runJob.Trigger(params), &retryErr)).To(BeTrue())
+			setCondition(ctx, batchv1.JobComplete)
+			Expect(runJob.Trigger(params)).To(Succeed())
+		})
+
+		It("fails if the job failed", func(ctx SpecContext) {
+			var retryErr *plugin.RetryError
+			Expect(errors.As(runJob.Trigger(params), &retryErr)).To(BeTrue())
+			setCondition(ctx, batchv1.JobFailed)
+			err := runJob.Trigger(params)
+			Expect(err).To(HaveOccurred())
+			Expect(errors.As(err, &retryErr)).To(BeFalse())
+		})
+
+		It("deletes jobs of previous transitions", func(ctx SpecContext) {
+			var retryErr *plugin.RetryError
+			Expect(errors.As(runJob.Trigger(params), &retryErr)).To(BeTrue())
+			staleKey := runJob.Job.Key(&params)
+
+			params.LastTransition = params.LastTransition.Add(time.Hour)
+			Expect(errors.As(runJob.Trigger(params), &retryErr)).To(BeTrue())
+			Expect(runJob.Job.Key(&params)).ToNot(Equal(staleKey))
+
+			err := k8sClient.Get(ctx, staleKey, &batchv1.Job{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			Expect(k8sClient.Get(ctx, runJob.Job.Key(&params), &batchv1.Job{})).To(Succeed())
+		})
+
+		It("keeps the jobs of other profiles", func(ctx SpecContext) {
+			var retryErr *plugin.RetryError
+			Expect(errors.As(runJob.Trigger(params), &retryErr)).To(BeTrue())
+			firstKey := runJob.Job.Key(&params)
+
+			params.Profile = "otherprofile"
+			params.LastTransition = params.LastTransition.Add(time.Hour)
+			Expect(errors.As(runJob.Trigger(params), &retryErr)).To(BeTrue())
+			Expect(k8sClient.Get(ctx, firstKey, &batchv1.Job{})).To(Succeed())
+			Expect(k8sClient.Get(ctx, runJob.Job.Key(&params), &batchv1.Job{})).To(Succeed())
+		})
+
+		It("retries if the created job is not cached yet", func(ctx SpecContext) {
+			var retryErr *plugin.RetryError
+			Expect(errors.As(runJob.Trigger(params), &retryErr)).To(BeTrue())
+			// simulate a cache, which does not contain the job yet
+			params.Client = interceptor.NewClient(k8sClient, interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					return k8serrors.NewNotFound(batchv1.Resource("jobs"), key.Name)
+				},
+			})
+			err := runJob.Trigger(params)
+			Expect(errors.As(err, &retryErr)).To(BeTrue())
+			Expect(retryErr.Message).To(HaveSuffix("has been created"))
+		})
+
+		It("is still running for the jobSucceeded plugin", func() {
+			var retryErr *plugin.RetryError
+			Expect(errors.As(runJob.Trigger(params), &retryErr)).To(BeTrue())
+			params.LastTransition = params.LastTransition.Add(time.Minute)
+			check := JobSucceeded{Job: runJob.Job}
+			result, err := check.Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.Info).To(HaveKeyWithValue("reason", "job is still running"))
+		})
+
+		It("is checked by the jobSucceeded plugin", func(ctx SpecContext) {
+			check := JobSucceeded{Job: runJob.Job}
+			result, err := check.Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeFalse())
+
+			var retryErr *plugin.RetryError
+			Expect(errors.As(runJob.Trigger(params), &retryErr)).To(BeTrue())
+			setCondition(ctx, batchv1.JobComplete)
+			Expect(runJob.Trigger(params)).To(Succeed())
+
+			// the check runs in the next state after the trigger allowed the transition
+			params.LastTransition = params.LastTransition.Add(time.Minute)
+			result, err = check.Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeTrue())
+
+			params.Profile = "otherprofile"
+			result, err = check.Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeFalse())
+		})
+	})
+
+})
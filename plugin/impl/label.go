@@ -4,30 +4,37 @@
 package impl
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"strings"
 
 	"github.com/sapcc/ucfgwrap"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/sapcc/maintenance-controller/plugin"
 )
 
 // HasLabel is a check plugin that checks whether a node has a label or a label with a certain value.
+// Alternatively the value can be matched against a regex or a semver range and the labels against a selector.
 type HasLabel struct {
 	Key   string
 	Value string
+	Match ValueMatch
 }
 
 // New creates a new HasLabel instance with the given config.
 func (h *HasLabel) New(config *ucfgwrap.Config) (plugin.Checker, error) {
-	conf := struct {
-		Key   string `config:"key" validate:"required"`
-		Value string `config:"value"`
-	}{}
+	var conf matchConfig
 	if err := config.Unpack(&conf); err != nil {
 		return nil, err
 	}
-	return &HasLabel{Key: conf.Key, Value: conf.Value}, nil
+	match, err := conf.build()
+	if err != nil {
+		return nil, err
+	}
+	return &HasLabel{Key: conf.Key, Value: conf.Value, Match: match}, nil
 }
 
 func (h *HasLabel) ID() string {
@@ -35,81 +42,118 @@ func (h *HasLabel) ID() string {
 }
 
 // Check checks whether a node has a label (if h.Value == "") or a label with a certain value (if h.Value != "").
+// Additionally the regex, semver range and selector have to match, if configured.
 func (h *HasLabel) Check(params plugin.Parameters) (plugin.CheckResult, error) {
-	val, ok := params.Node.Labels[h.Key]
-	if !ok {
-		return plugin.FailedWithReason(fmt.Sprintf("label %s not present", h.Key)), nil
-	}
-	if h.Value == "" {
-		return plugin.Passed(nil), nil
+	if ok, reason := h.Match.matches(params.Node.Labels, h.Key, h.Value); !ok {
+		return plugin.FailedWithReason("label " + reason), nil
 	}
-	return plugin.CheckResult{Passed: val == h.Value}, nil
+	return plugin.Passed(nil), nil
 }
 
 func (h *HasLabel) OnTransition(params plugin.Parameters) error {
 	return nil
 }
 
+// AnyLabel is a check plugin that checks how many nodes in the cluster match a label.
+// Without bounds it passes if at least one node matches.
 type AnyLabel struct {
 	Key   string
 	Value string
+	Match ValueMatch
+	// nil means 1, if AtMost is nil too, or 0 otherwise
+	AtLeast *int
+	// nil means unbounded
+	AtMost *int
 }
 
 func (a *AnyLabel) New(config *ucfgwrap.Config) (plugin.Checker, error) {
 	conf := struct {
-		Key   string `config:"key" validate:"required"`
-		Value string `config:"value"`
+		Key      string `config:"key"`
+		Value    string `config:"value"`
+		Regex    string `config:"regex"`
+		Semver   string `config:"semver"`
+		Selector string `config:"selector"`
+		AtLeast  *int   `config:"atLeast"`
+		AtMost   *int   `config:"atMost"`
 	}{}
 	if err := config.Unpack(&conf); err != nil {
 		return nil, err
 	}
-	return &AnyLabel{Key: conf.Key, Value: conf.Value}, nil
+	matchConf := matchConfig{Key: conf.Key, Value: conf.Value, Regex: conf.Regex, Semver: conf.Semver, Selector: conf.Selector}
+	match, err := matchConf.build()
+	if err != nil {
+		return nil, err
+	}
+	if (conf.AtLeast != nil && *conf.AtLeast < 0) || (conf.AtMost != nil && *conf.AtMost < 0) {
+		return nil, errors.New("atLeast and atMost must not be negative")
+	}
+	if conf.AtLeast != nil && conf.AtMost != nil && *conf.AtLeast > *conf.AtMost {
+		return nil, fmt.Errorf("atLeast %d must not exceed atMost %d", *conf.AtLeast, *conf.AtMost)
+	}
+	return &AnyLabel{
+		Key:     conf.Key,
+		Value:   conf.Value,
+		Match:   match,
+		AtLeast: conf.AtLeast,
+		AtMost:  conf.AtMost,
+	}, nil
 }
 
 func (a *AnyLabel) ID() string {
 	return "anyLabel"
 }
 
+// Check counts the nodes matching the label and passes if the count is within the bounds.
 func (a *AnyLabel) Check(params plugin.Parameters) (plugin.CheckResult, error) {
 	var nodes v1.NodeList
 	err := params.Client.List(params.Ctx, &nodes)
 	if err != nil {
 		return plugin.Failed(nil), err
 	}
+	count := 0
 	for _, node := range nodes.Items {
-		val, ok := node.Labels[a.Key]
-		if !ok {
-			continue
-		}
-		if a.Value == "" || a.Value == val {
-			return plugin.Passed(nil), nil
+		if ok, _ := a.Match.matches(node.Labels, a.Key, a.Value); ok {
+			count++
 		}
 	}
-	return plugin.Failed(nil), nil
+	atLeast := 1
+	if a.AtLeast != nil {
+		atLeast = *a.AtLeast
+	} else if a.AtMost != nil {
+		atLeast = 0
+	}
+	info := map[string]any{"count": count, "atLeast": atLeast}
+	passed := count >= atLeast
+	if a.AtMost != nil {
+		info["atMost"] = *a.AtMost
+		passed = passed && count <= *a.AtMost
+	}
+	return plugin.CheckResult{Passed: passed, Info: info}, nil
 }
 
 func (a *AnyLabel) OnTransition(params plugin.Parameters) error {
 	return nil
 }
 
-// AlterLabel is a trigger plugin, which can add, change or remove a label.
+// AlterLabel is a trigger plugin, which can add, change or remove labels.
+// Values are rendered as templates before being applied.
 type AlterLabel struct {
 	Key    string
 	Value  string
 	Remove bool
+	// batch of labels to add or change
+	Set map[string]string
+	// batch of labels to remove
+	Delete []string
 }
 
 // New creates a new AlterLabel instance with the given config.
 func (a *AlterLabel) New(config *ucfgwrap.Config) (plugin.Trigger, error) {
-	conf := struct {
-		Key    string `config:"key" validate:"required"`
-		Value  string `config:"value"`
-		Remove bool   `config:"remove"`
-	}{}
+	var conf alterConfig
 	if err := config.Unpack(&conf); err != nil {
 		return nil, err
 	}
-	return &AlterLabel{Key: conf.Key, Remove: conf.Remove, Value: conf.Value}, nil
+	return &AlterLabel{Key: conf.Key, Remove: conf.Remove, Value: conf.Value, Set: conf.Set, Delete: conf.Delete}, nil
 }
 
 func (a *AlterLabel) ID() string {
@@ -118,14 +162,27 @@ func (a *AlterLabel) ID() string {
 
 // Trigger ensures the label with the provided key is removed if removes is set to true.
 // Otherwise it sets the label with the provided key to the provided value adding the label if required.
+// The same applies to the batches of labels to set and to delete.
+// If any value is templated, the changes are applied only once per transition of the profile.
 func (a *AlterLabel) Trigger(params plugin.Parameters) error {
-	_, ok := params.Node.Labels[a.Key]
-	if !a.Remove {
-		params.Node.Labels[a.Key] = a.Value
+	alt, applied, err := renderAlteration(&params, "label", a.Key, a.Value, a.Remove, a.Set, a.Delete)
+	if err != nil {
+		return err
+	}
+	if applied {
 		return nil
 	}
-	if ok {
-		delete(params.Node.Labels, a.Key)
+	for key, value := range alt.set {
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("rendered invalid value %s for label %s: %s", value, key, strings.Join(errs, ", "))
+		}
 	}
-	return nil
+	if params.Node.Labels == nil {
+		params.Node.Labels = make(map[string]string)
+	}
+	for _, key := range alt.delete {
+		delete(params.Node.Labels, key)
+	}
+	maps.Copy(params.Node.Labels, alt.set)
+	return alt.recordApplied(&params)
 }
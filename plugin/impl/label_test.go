@@ -4,12 +4,17 @@
 package impl
 
 import (
+	"context"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/sapcc/ucfgwrap"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/sapcc/maintenance-controller/constants"
 	"github.com/sapcc/maintenance-controller/plugin"
 )
 
@@ -69,6 +74,56 @@ var _ = Describe("The HasLabel plugin", func() {
 
 	})
 
+	Context("with label version=1.4.2", func() {
+		params := plugin.Parameters{
+			Node: &corev1.Node{
+				ObjectMeta: v1.ObjectMeta{
+					Labels: map[string]string{"version": "1.4.2", "zone": "a"},
+				},
+			},
+			Log: GinkgoLogr,
+		}
+
+		check := func(configStr string) bool {
+			config, err := ucfgwrap.FromYAML([]byte(configStr))
+			Expect(err).To(Succeed())
+			var base HasLabel
+			plugin, err := base.New(&config)
+			Expect(err).To(Succeed())
+			result, err := plugin.Check(params)
+			Expect(err).To(Succeed())
+			return result.Passed
+		}
+
+		It("matches regular expressions", func() {
+			Expect(check(`{key: version, regex: '^1\.4\.'}`)).To(BeTrue())
+			Expect(check(`{key: version, regex: '^2\.'}`)).To(BeFalse())
+		})
+
+		It("matches semver ranges", func() {
+			Expect(check(`{key: version, semver: '>=1.4.0 <2.0.0'}`)).To(BeTrue())
+			Expect(check(`{key: version, semver: '>=1.5.0'}`)).To(BeFalse())
+			Expect(check(`{key: zone, semver: '>=1.0.0'}`)).To(BeFalse())
+		})
+
+		It("matches selectors", func() {
+			Expect(check(`{selector: 'zone in (a, b),!broken'}`)).To(BeTrue())
+			Expect(check(`{selector: 'zone notin (a)'}`)).To(BeFalse())
+			Expect(check(`{key: version, selector: 'broken'}`)).To(BeFalse())
+		})
+
+		It("rejects invalid configurations", func() {
+			for _, configStr := range []string{`{value: a}`, `{key: a, value: b, regex: c}`, `{key: a, regex: '('}`,
+				`{key: a, semver: 'abc'}`, `{selector: 'a in (b'}`} {
+				config, err := ucfgwrap.FromYAML([]byte(configStr))
+				Expect(err).To(Succeed())
+				var base HasLabel
+				_, err = base.New(&config)
+				Expect(err).ToNot(Succeed(), configStr)
+			}
+		})
+	})
+
 })
 
 var _ = Describe("The AnyLabel plugin", func() {
@@ -86,6 +141,55 @@ var _ = Describe("The AnyLabel plugin", func() {
 		}))
 	})
 
+	It("rejects invalid bounds", func() {
+		config, err := ucfgwrap.FromYAML([]byte("key: key\natLeast: 3\natMost: 2"))
+		Expect(err).To(Succeed())
+		var base AnyLabel
+		_, err = base.New(&config)
+		Expect(err).ToNot(Succeed())
+	})
+
+	Context("with three nodes", func() {
+		var params plugin.Parameters
+
+		BeforeEach(func() {
+			makeNode := func(name, zone string) *corev1.Node {
+				return &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: name, Labels: map[string]string{"zone": zone}}}
+			}
+			params = plugin.Parameters{
+				Client: fake.NewClientBuilder().WithObjects(makeNode("a", "a"), makeNode("b", "a"), makeNode("c", "b")).Build(),
+				Ctx:    context.Background(),
+				Log:    GinkgoLogr,
+			}
+		})
+
+		bound := func(n int) *int {
+			return &n
+		}
+
+		It("passes if any node matches", func() {
+			result, err := (&AnyLabel{Key: "zone", Value: "b"}).Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeTrue())
+			result, err = (&AnyLabel{Key: "zone", Value: "c"}).Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeFalse())
+		})
+
+		It("counts the matching nodes", func() {
+			result, err := (&AnyLabel{Key: "zone", Value: "a", AtLeast: bound(2)}).Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeTrue())
+			Expect(result.Info).To(HaveKeyWithValue("count", 2))
+			result, err = (&AnyLabel{Key: "zone", AtMost: bound(2)}).Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeFalse())
+			result, err = (&AnyLabel{Key: "zone", Value: "c", AtMost: bound(0)}).Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeTrue())
+		})
+	})
+
 })
 
 var _ = Describe("The AlterLabel plugin", func() {
@@ -141,5 +245,44 @@ var _ = Describe("The AlterLabel plugin", func() {
 			Expect(params.Node.Labels).To(HaveLen(1))
 			Expect(params.Node.Labels["key"]).To(Equal("abc"))
 		})
+
+		It("renders values and applies batches", func() {
+			params.Node.Labels["count"] = "4"
+			params.Node.Annotations = map[string]string{"version": "1.2.3"}
+			plugin := AlterLabel{
+				Set: map[string]string{
+					"count":   `{{ index .Node.Labels "count" | atoi | add 1 }}`,
+					"version": `{{ index .Node.Annotations "version" }}`,
+					"copy":    `{{ index .Node.Labels "key" }}`,
+				},
+				Delete: []string{"key"},
+			}
+			err := plugin.Trigger(params)
+			Expect(err).To(Succeed())
+			Expect(params.Node.Labels).To(Equal(map[string]string{"count": "5", "version": "1.2.3", "copy": "value"}))
+		})
+
+		It("renders templated values once per transition", func() {
+			params.Profile = "profile"
+			params.LastTransition = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			params.Node.Labels["count"] = "4"
+			plugin := AlterLabel{Set: map[string]string{"count": `{{ index .Node.Labels "count" | atoi | add 1 }}`}}
+			Expect(plugin.Trigger(params)).To(Succeed())
+			// a later trigger of the chain returned a RetryError, so the chain is executed again
+			Expect(plugin.Trigger(params)).To(Succeed())
+			Expect(params.Node.Labels).To(HaveKeyWithValue("count", "5"))
+
+			params.LastTransition = params.LastTransition.Add(time.Hour)
+			Expect(plugin.Trigger(params)).To(Succeed())
+			Expect(params.Node.Labels).To(HaveKeyWithValue("count", "6"))
+			Expect(params.Node.Annotations).To(HaveKeyWithValue(constants.AlteredAnnotationKey,
+				MatchRegexp(`^\{"profile/[0-9a-f]{12}":"2026-01-02T04:04:05Z"\}$`)))
+		})
+
+		It("rejects invalid rendered label values", func() {
+			plugin := AlterLabel{Key: "time", Value: "{{ now }}"}
+			Expect(plugin.Trigger(params)).ToNot(Succeed())
+			Expect(params.Node.Labels).ToNot(HaveKey("time"))
+		})
 	})
 })
@@ -23,24 +23,31 @@ type Mail struct {
 	Identity string
 	User     string
 	Password string // #nosec G117 - intentional passing of secret credential
+	// routes nodes to other recipients, the first matching route is used
+	Routes []NotificationRoute
 }
 
 // New creates a new Mail instance with the given config.
 func (m *Mail) New(config *ucfgwrap.Config) (plugin.Notifier, error) {
 	conf := struct {
-		Auth     bool     `config:"auth" validate:"required"`
-		Message  string   `config:"message" validate:"required"`
-		Subject  string   `config:"subject" validate:"required"`
-		Address  string   `config:"address" validate:"required"`
-		From     string   `config:"from" validate:"required"`
-		To       []string `config:"to" validate:"required"`
-		Identity string   `config:"identity"`
-		User     string   `config:"user"`
-		Password string   `config:"password"` // #nosec G117 - intentional passing of secret credential
+		Auth     bool          `config:"auth" validate:"required"`
+		Message  string        `config:"message" validate:"required"`
+		Subject  string        `config:"subject" validate:"required"`
+		Address  string        `config:"address" validate:"required"`
+		From     string        `config:"from" validate:"required"`
+		To       []string      `config:"to" validate:"required"`
+		Identity string        `config:"identity"`
+		User     string        `config:"user"`
+		Password string        `config:"password"` // #nosec G117 - intentional passing of secret credential
+		Routes   []routeConfig `config:"routes"`
 	}{}
 	if err := config.Unpack(&conf); err != nil {
 		return nil, err
 	}
+	routes, err := buildRoutes(conf.Routes)
+	if err != nil {
+		return nil, err
+	}
 	return &Mail{
 		Auth:     conf.Auth,
 		Address:  conf.Address,
@@ -51,6 +58,7 @@ func (m *Mail) New(config *ucfgwrap.Config) (plugin.Notifier, error) {
 		Password: conf.Password,
 		To:       conf.To,
 		User:     conf.User,
+		Routes:   routes,
 	}, nil
 }
 
@@ -61,16 +69,31 @@ func (m *Mail) ID() string {
 // Notify performs connects to the provided SMTP server and transmits the configured message.
 func (m *Mail) Notify(params plugin.Parameters) error {
 	theMessage, err := plugin.RenderNotificationTemplate(m.Message, &params)
-	theMessage = m.buildMailHeader() + theMessage
 	if err != nil {
 		return err
 	}
+	recipients, err := resolveRecipients(&params, m.Routes, m.To)
+	if err != nil {
+		return err
+	}
+	routed := *m
+	routed.To = recipients
+	if err := routed.send(theMessage); err != nil {
+		return err
+	}
+	recordTarget(&params, m.ID(), strings.Join(recipients, ","))
+	return nil
+}
+
+// send transmits the given message, which has already been rendered.
+func (m *Mail) send(theMessage string) error {
+	theMessage = m.buildMailHeader() + theMessage
 	var auth smtp.Auth
 	if m.Auth {
 		server := strings.Split(m.Address, ":")[0]
 		auth = smtp.PlainAuth(m.Identity, m.User, m.Password, server)
 	}
-	err = smtp.SendMail(m.Address, auth, m.From, m.To, []byte(theMessage))
+	err := smtp.SendMail(m.Address, auth, m.From, m.To, []byte(theMessage))
 	if err != nil {
 		return err
 	}
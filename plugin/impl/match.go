@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// matchConfig is the configuration of label and annotation matching shared by several check plugins.
+type matchConfig struct {
+	Key      string `config:"key"`
+	Value    string `config:"value"`
+	Regex    string `config:"regex"`
+	Semver   string `config:"semver"`
+	Selector string `config:"selector"`
+}
+
+// build validates the configuration and compiles the regex, semver range and selector.
+func (mc *matchConfig) build() (ValueMatch, error) {
+	var match ValueMatch
+	if mc.Key == "" && mc.Selector == "" {
+		return match, errors.New("either key or selector is required")
+	}
+	alternatives := 0
+	for _, s := range []string{mc.Value, mc.Regex, mc.Semver} {
+		if s != "" {
+			alternatives++
+		}
+	}
+	if alternatives > 1 {
+		return match, errors.New("only one of value, regex and semver may be specified")
+	}
+	if alternatives > 0 && mc.Key == "" {
+		return match, errors.New("value, regex and semver require a key")
+	}
+	if mc.Regex != "" {
+		regex, err := regexp.Compile(mc.Regex)
+		if err != nil {
+			return match, fmt.Errorf("invalid regex %s: %w", mc.Regex, err)
+		}
+		match.Regex = regex
+	}
+	if mc.Semver != "" {
+		semverRange, err := semver.ParseRange(mc.Semver)
+		if err != nil {
+			return match, fmt.Errorf("invalid semver range %s: %w", mc.Semver, err)
+		}
+		match.Semver = semverRange
+	}
+	if mc.Selector != "" {
+		selector, err := labels.Parse(mc.Selector)
+		if err != nil {
+			return match, fmt.Errorf("invalid selector %s: %w", mc.Selector, err)
+		}
+		match.Selector = selector
+	}
+	return match, nil
+}
+
+// ValueMatch holds the optional matchers of label and annotation checks besides key and value.
+type ValueMatch struct {
+	// value has to match the regex
+	Regex *regexp.Regexp
+	// value has to be a semantic version within the range
+	Semver semver.Range
+	// all values have to match the selector, supports the Kubernetes label selector syntax
+	Selector labels.Selector
+}
+
+// matches checks the given labels or annotations. If they do not match, a reason is returned.
+// An empty key only evaluates the selector. An empty value only checks the presence of the key.
+func (vm *ValueMatch) matches(values map[string]string, key, value string) (bool, string) {
+	if vm.Selector != nil && !vm.Selector.Matches(labels.Set(values)) {
+		return false, fmt.Sprintf("selector %s does not match", vm.Selector)
+	}
+	if key == "" {
+		return true, ""
+	}
+	val, ok := values[key]
+	if !ok {
+		return false, fmt.Sprintf("%s not present", key)
+	}
+	if value != "" && val != value {
+		return false, fmt.Sprintf("value %s of %s does not equal %s", val, key, value)
+	}
+	if vm.Regex != nil && !vm.Regex.MatchString(val) {
+		return false, fmt.Sprintf("value %s of %s does not match regex %s", val, key, vm.Regex)
+	}
+	if vm.Semver != nil {
+		version, err := semver.ParseTolerant(val)
+		if err != nil {
+			return false, fmt.Sprintf("value %s of %s is not a semantic version", val, key)
+		}
+		if !vm.Semver(version) {
+			return false, fmt.Sprintf("version %s of %s is not within the semver range", val, key)
+		}
+	}
+	return true, ""
+}
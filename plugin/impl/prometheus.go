@@ -4,8 +4,11 @@
 package impl
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/PaesslerAG/gval"
@@ -13,29 +16,80 @@ import (
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 	"github.com/sapcc/ucfgwrap"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/sapcc/maintenance-controller/plugin"
 )
 
-// HasLabel is a check plugin that queries a prometheus for the most recent
+// PromAggregation defines how the samples of a vector result are evaluated.
+type PromAggregation string
+
+const (
+	// AggregateSingle requires the result to contain exactly one sample.
+	AggregateSingle PromAggregation = ""
+	// AggregateAll passes if every sample satisfies the expression.
+	AggregateAll PromAggregation = "all"
+	// AggregateAny passes if at least one sample satisfies the expression.
+	AggregateAny PromAggregation = "any"
+	// AggregateCount evaluates the expression against the number of samples.
+	AggregateCount PromAggregation = "count"
+	// AggregateNone passes if no sample satisfies the expression.
+	AggregateNone PromAggregation = "none"
+)
+
+// maxReportedSeries bounds the number of offending series added to the check info.
+const maxReportedSeries = 10
+
+// PrometheusInstant is a check plugin that queries a prometheus for the most recent
 // value of a query, which is checked against a given expression.
 type PrometheusInstant struct {
 	URL   string
 	Query string
 	Expr  string
+	// how the samples of a vector are evaluated
+	Aggregation PromAggregation
+	// whether an empty result passes, does not apply to the count aggregation
+	PassOnEmpty        bool
+	Auth               SecretAuth
+	InsecureSkipVerify bool
 }
 
 // New creates a new PrometheusInstant instance with the given config.
 func (pi *PrometheusInstant) New(config *ucfgwrap.Config) (plugin.Checker, error) {
 	conf := struct {
-		URL   string `config:"url" validate:"required"`
-		Query string `config:"query"`
-		Expr  string `config:"expr"`
-	}{}
+		URL                string     `config:"url" validate:"required"`
+		Query              string     `config:"query"`
+		Expr               string     `config:"expr"`
+		Aggregation        string     `config:"aggregation"`
+		EmptyResult        string     `config:"emptyResult"`
+		Auth               authConfig `config:"auth"`
+		InsecureSkipVerify bool       `config:"insecureSkipVerify"`
+	}{EmptyResult: "fail"}
 	if err := config.Unpack(&conf); err != nil {
 		return nil, err
 	}
-	return &PrometheusInstant{URL: conf.URL, Query: conf.Query, Expr: conf.Expr}, nil
+	aggregation := PromAggregation(conf.Aggregation)
+	switch aggregation {
+	case AggregateSingle, AggregateAll, AggregateAny, AggregateCount, AggregateNone:
+	default:
+		return nil, fmt.Errorf("got invalid aggregation: %s", conf.Aggregation)
+	}
+	if conf.EmptyResult != "pass" && conf.EmptyResult != "fail" {
+		return nil, fmt.Errorf("emptyResult needs to be either pass or fail, got: %s", conf.EmptyResult)
+	}
+	auth, err := conf.Auth.toSecretAuth()
+	if err != nil {
+		return nil, err
+	}
+	return &PrometheusInstant{
+		URL:                conf.URL,
+		Query:              conf.Query,
+		Expr:               conf.Expr,
+		Aggregation:        aggregation,
+		PassOnEmpty:        conf.EmptyResult == "pass",
+		Auth:               auth,
+		InsecureSkipVerify: conf.InsecureSkipVerify,
+	}, nil
 }
 
 func (pi *PrometheusInstant) ID() string {
@@ -45,37 +99,107 @@ func (pi *PrometheusInstant) ID() string {
 // Queries the prometheus and evaluate the result against the given expression.
 func (pi *PrometheusInstant) Check(params plugin.Parameters) (plugin.CheckResult, error) {
 	info := map[string]any{"url": pi.URL, "query": pi.Query, "expr": pi.Expr}
-	cfg := api.Config{
-		Address: pi.URL,
+	query, err := plugin.RenderNotificationTemplate(pi.Query, &params)
+	if err != nil {
+		return plugin.Failed(info), fmt.Errorf("failed to render prometheus query: %w", err)
 	}
-	promClient, err := api.NewClient(cfg)
+	info["query"] = query
+	promAPI, err := promClients.get(&params, pi.URL, pi.Auth, pi.InsecureSkipVerify)
 	if err != nil {
-		return plugin.Failed(info), fmt.Errorf("failed to create prometheus client for %s: %w", pi.URL, err)
+		return plugin.Failed(info), err
 	}
-	promAPI := v1.NewAPI(promClient)
-	result, warns, err := promAPI.Query(params.Ctx, pi.Query, time.Now())
+	result, warns, err := promAPI.Query(params.Ctx, query, time.Now())
 	if err != nil {
 		return plugin.Failed(info), fmt.Errorf("failed to query prometheus %s: %w", pi.URL, err)
 	}
 	if len(warns) > 0 {
 		info["warns"] = warns
 	}
-	vector, ok := result.(model.Vector)
-	if !ok {
+	var values []float64
+	var series []string
+	switch typed := result.(type) {
+	case model.Vector:
+		for _, sample := range typed {
+			values = append(values, float64(sample.Value))
+			series = append(series, sample.Metric.String())
+		}
+	case *model.Scalar:
+		values = []float64{float64(typed.Value)}
+		series = []string{"scalar"}
+	default:
 		return plugin.Failed(info), errors.New("result from prometheus is not a vector")
 	}
-	if len(vector) != 1 {
-		return plugin.Failed(info), errors.New("result does not contain exactly one element")
+	return pi.evaluate(&params, values, series, info)
+}
+
+func (pi *PrometheusInstant) evaluate(params *plugin.Parameters, values []float64, series []string,
+	info map[string]any) (plugin.CheckResult, error) {
+
+	if len(values) == 0 && pi.Aggregation != AggregateCount {
+		info["reason"] = "empty result"
+		if pi.PassOnEmpty {
+			return plugin.Passed(info), nil
+		}
+		return plugin.Failed(info), nil
 	}
-	value := float64(vector[0].Value)
-	info["value"] = value
 	evaluable, err := gval.Full().NewEvaluable(pi.Expr)
 	if err != nil {
 		return plugin.Failed(info), err
 	}
-	passed, err := evaluable.EvalBool(params.Ctx, map[string]float64{"value": value})
-	if err != nil {
-		return plugin.Failed(info), fmt.Errorf("failed to evaluate prometheus expression: %w", err)
+	satisfies := func(value float64) (bool, error) {
+		passed, err := evaluable.EvalBool(params.Ctx, map[string]float64{"value": value})
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate prometheus expression: %w", err)
+		}
+		return passed, nil
+	}
+	switch pi.Aggregation {
+	case AggregateSingle:
+		if len(values) != 1 {
+			return plugin.Failed(info), errors.New("result does not contain exactly one element")
+		}
+		info["value"] = values[0]
+		passed, err := satisfies(values[0])
+		if err != nil || !passed {
+			return plugin.Failed(info), err
+		}
+		return plugin.Passed(info), nil
+	case AggregateCount:
+		info["value"] = len(values)
+		passed, err := satisfies(float64(len(values)))
+		if err != nil || !passed {
+			return plugin.Failed(info), err
+		}
+		return plugin.Passed(info), nil
+	}
+	matching := 0
+	offending := make([]string, 0)
+	for i, value := range values {
+		passed, err := satisfies(value)
+		if err != nil {
+			return plugin.Failed(info), err
+		}
+		if passed {
+			matching++
+		}
+		// for all the unsatisfying series are offending, for none the satisfying ones
+		if passed == (pi.Aggregation == AggregateNone) && len(offending) < maxReportedSeries {
+			offending = append(offending, series[i])
+		}
+	}
+	info["samples"] = len(values)
+	info["matching"] = matching
+	var passed bool
+	switch pi.Aggregation {
+	case AggregateAll:
+		passed = matching == len(values)
+	case AggregateAny:
+		passed = matching > 0
+	case AggregateNone:
+		passed = matching == 0
+	}
+	if pi.Aggregation != AggregateAny && len(offending) > 0 {
+		info["offending"] = offending
 	}
 	if !passed {
 		return plugin.Failed(info), nil
@@ -86,3 +210,92 @@ func (pi *PrometheusInstant) Check(params plugin.Parameters) (plugin.CheckResult
 func (pi *PrometheusInstant) OnTransition(params plugin.Parameters) error {
 	return nil
 }
+
+// promClients is shared by all prometheus plugins, as plugin instances are recreated whenever the configuration is loaded.
+var promClients = promClientCache{clients: make(map[promClientKey]*promClient)}
+
+type promClientKey struct {
+	url                string
+	auth               SecretAuth
+	insecureSkipVerify bool
+}
+
+type promClient struct {
+	// resource version of the auth secret the client has been built from
+	secretVersion string
+	transport     *http.Transport
+	api           v1.API
+}
+
+// promClientCache reuses prometheus clients and their connections across checks.
+// A client is rebuilt once the referenced auth secret changes.
+type promClientCache struct {
+	mutex   sync.Mutex
+	clients map[promClientKey]*promClient
+}
+
+func (pcc *promClientCache) get(params *plugin.Parameters, url string, auth SecretAuth,
+	insecureSkipVerify bool) (v1.API, error) {
+
+	var secret *corev1.Secret
+	secretVersion := ""
+	if auth.Type != NoAuth {
+		var err error
+		secret, err = auth.fetch(params)
+		if err != nil {
+			return nil, err
+		}
+		secretVersion = secret.ResourceVersion
+	}
+	key := promClientKey{url: url, auth: auth, insecureSkipVerify: insecureSkipVerify}
+	pcc.mutex.Lock()
+	defer pcc.mutex.Unlock()
+	cached, ok := pcc.clients[key]
+	if ok && cached.secretVersion == secretVersion {
+		return cached.api, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	var roundTripper http.RoundTripper = transport
+	if secret != nil {
+		tlsConfig, err := auth.tlsConfig(secret)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+		authorization, err := auth.authorization(secret)
+		if err != nil {
+			return nil, err
+		}
+		if authorization != "" {
+			roundTripper = &authorizationRoundTripper{authorization: authorization, next: transport}
+		}
+	}
+	if insecureSkipVerify {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		transport.TLSClientConfig.InsecureSkipVerify = true //nolint:gosec
+	}
+	client, err := api.NewClient(api.Config{Address: url, RoundTripper: roundTripper})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prometheus client for %s: %w", url, err)
+	}
+	if ok {
+		// release the connections of the outdated client
+		cached.transport.CloseIdleConnections()
+	}
+	pcc.clients[key] = &promClient{secretVersion: secretVersion, transport: transport, api: v1.NewAPI(client)}
+	return pcc.clients[key].api, nil
+}
+
+// authorizationRoundTripper sets the authorization header on every request.
+type authorizationRoundTripper struct {
+	authorization string
+	next          http.RoundTripper
+}
+
+func (art *authorizationRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", art.authorization)
+	return art.next.RoundTrip(req)
+}
@@ -12,12 +12,20 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/sapcc/ucfgwrap"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/sapcc/maintenance-controller/plugin"
 )
 
 const promReply string = "{\"status\":\"success\",\"data\":{\"resultType\":\"vector\",\"result\":[{\"metric\":{\"__name__\":\"cool_metric\"},\"value\":[1680600891.782,\"1\"]}]}}" //nolint:lll
 
+const promMultiReply string = "{\"status\":\"success\",\"data\":{\"resultType\":\"vector\",\"result\":[{\"metric\":{\"zone\":\"a\"},\"value\":[1680600891.782,\"1\"]},{\"metric\":{\"zone\":\"b\"},\"value\":[1680600891.782,\"3\"]}]}}" //nolint:lll
+
+const promEmptyReply string = "{\"status\":\"success\",\"data\":{\"resultType\":\"vector\",\"result\":[]}}"
+
 var _ = Describe("The prometheusInstant plugin", func() {
 	It("can parse its configuration", func() {
 		configStr := "url: http://abc.de\nquery: q\nexpr: value > 0"
@@ -33,11 +41,47 @@ var _ = Describe("The prometheusInstant plugin", func() {
 		}))
 	})
 
+	It("can parse aggregation, empty result and auth options", func() {
+		configStr := "url: https://abc.de\nquery: q\nexpr: value > 0\naggregation: any\nemptyResult: pass\n" +
+			"insecureSkipVerify: true\nauth:\n  type: mtls\n  secret:\n    name: certs\n    namespace: default\n"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base PrometheusInstant
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(plugin).To(Equal(&PrometheusInstant{
+			URL:                "https://abc.de",
+			Query:              "q",
+			Expr:               "value > 0",
+			Aggregation:        AggregateAny,
+			PassOnEmpty:        true,
+			InsecureSkipVerify: true,
+			Auth: SecretAuth{
+				Type:   MTLSAuth,
+				Secret: client.ObjectKey{Name: "certs", Namespace: "default"},
+			},
+		}))
+	})
+
+	It("rejects invalid aggregations and empty result modes", func() {
+		for _, configStr := range []string{
+			"url: http://abc.de\naggregation: most",
+			"url: http://abc.de\nemptyResult: maybe",
+		} {
+			config, err := ucfgwrap.FromYAML([]byte(configStr))
+			Expect(err).To(Succeed())
+			var base PrometheusInstant
+			_, err = base.New(&config)
+			Expect(err).To(HaveOccurred())
+		}
+	})
+
 	Context("with a mock prometheus", Ordered, func() {
 
 		var server http.Server
 		const addr string = "127.0.0.1:29572"
 		const url = "http://" + addr
+		const token string = "right"
 
 		BeforeAll(func() {
 			mux := http.NewServeMux()
@@ -46,13 +90,23 @@ var _ = Describe("The prometheusInstant plugin", func() {
 				Expect(r.ParseForm()).To(Succeed())
 				metric := r.Form.Get("query")
 				GinkgoLogr.Info("query", "val", metric)
-				if metric == "cool_metric" {
-					_, err := w.Write([]byte(promReply))
-					Expect(err).To(Succeed())
-				} else {
-					_, err := w.Write([]byte("{}"))
-					Expect(err).To(Succeed())
+				reply := "{}"
+				switch metric {
+				case "cool_metric", `cool_metric{node="targetnode"}`:
+					reply = promReply
+				case "multi_metric":
+					reply = promMultiReply
+				case "empty_metric":
+					reply = promEmptyReply
+				case "secured_metric":
+					if r.Header.Get("Authorization") != "Bearer "+token {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					reply = promReply
 				}
+				_, err := w.Write([]byte(reply))
+				Expect(err).To(Succeed())
 			})
 			server = http.Server{
 				Addr:           addr,
@@ -124,5 +178,131 @@ var _ = Describe("The prometheusInstant plugin", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(result.Passed).To(BeFalse())
 		})
+
+		It("renders the query as template", func() {
+			prom := PrometheusInstant{
+				URL:   url,
+				Query: `cool_metric{node="{{ .Node.Name }}"}`,
+				Expr:  "value == 1",
+			}
+			result, err := prom.Check(plugin.Parameters{
+				Ctx:  context.Background(),
+				Node: &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "targetnode"}},
+			})
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeTrue())
+			Expect(result.Info).To(HaveKeyWithValue("query", `cool_metric{node="targetnode"}`))
+		})
+
+		It("fails on multiple samples without aggregation", func() {
+			prom := PrometheusInstant{URL: url, Query: "multi_metric", Expr: "value >= 1"}
+			result, err := prom.Check(plugin.Parameters{Ctx: context.Background()})
+			Expect(err).To(HaveOccurred())
+			Expect(result.Passed).To(BeFalse())
+		})
+
+		DescribeTable("aggregates multiple samples",
+			func(aggregation PromAggregation, expr string, passed bool) {
+				prom := PrometheusInstant{URL: url, Query: "multi_metric", Expr: expr, Aggregation: aggregation}
+				result, err := prom.Check(plugin.Parameters{Ctx: context.Background()})
+				Expect(err).To(Succeed())
+				Expect(result.Passed).To(Equal(passed))
+			},
+			Entry("all passes", AggregateAll, "value >= 1", true),
+			Entry("all fails", AggregateAll, "value > 1", false),
+			Entry("any passes", AggregateAny, "value > 1", true),
+			Entry("any fails", AggregateAny, "value > 3", false),
+			Entry("none passes", AggregateNone, "value > 3", true),
+			Entry("none fails", AggregateNone, "value > 1", false),
+			Entry("count passes", AggregateCount, "value == 2", true),
+			Entry("count fails", AggregateCount, "value > 2", false),
+		)
+
+		It("reports offending series", func() {
+			prom := PrometheusInstant{URL: url, Query: "multi_metric", Expr: "value > 1", Aggregation: AggregateAll}
+			result, err := prom.Check(plugin.Parameters{Ctx: context.Background()})
+			Expect(err).To(Succeed())
+			Expect(result.Info).To(HaveKeyWithValue("samples", 2))
+			Expect(result.Info).To(HaveKeyWithValue("matching", 1))
+			Expect(result.Info).To(HaveKeyWithValue("offending", []string{`{zone="a"}`}))
+		})
+
+		It("treats empty results according to the configuration", func() {
+			prom := PrometheusInstant{URL: url, Query: "empty_metric", Expr: "value == 1", Aggregation: AggregateAll}
+			result, err := prom.Check(plugin.Parameters{Ctx: context.Background()})
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeFalse())
+
+			prom.PassOnEmpty = true
+			result, err = prom.Check(plugin.Parameters{Ctx: context.Background()})
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeTrue())
+
+			prom.Aggregation = AggregateCount
+			prom.Expr = "value == 0"
+			result, err = prom.Check(plugin.Parameters{Ctx: context.Background()})
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeTrue())
+		})
+
+		It("authenticates with a token from a secret and reuses the client", func() {
+			k8sClient := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+				ObjectMeta: v1.ObjectMeta{Name: "prom-creds", Namespace: "default"},
+				Data:       map[string][]byte{"token": []byte(token)},
+			}).Build()
+			params := plugin.Parameters{Ctx: context.Background(), Client: k8sClient}
+			prom := PrometheusInstant{
+				URL:   url,
+				Query: "secured_metric",
+				Expr:  "value == 1",
+				Auth: SecretAuth{
+					Type:   BearerAuth,
+					Secret: client.ObjectKey{Name: "prom-creds", Namespace: "default"},
+				},
+			}
+			result, err := prom.Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeTrue())
+			firstAPI, err := promClients.get(&params, prom.URL, prom.Auth, false)
+			Expect(err).To(Succeed())
+			secondAPI, err := promClients.get(&params, prom.URL, prom.Auth, false)
+			Expect(err).To(Succeed())
+			Expect(secondAPI).To(BeIdenticalTo(firstAPI))
+		})
+
+		It("rebuilds the client once the secret changes", func() {
+			secret := &corev1.Secret{
+				ObjectMeta: v1.ObjectMeta{Name: "rotated-creds", Namespace: "default"},
+				Data:       map[string][]byte{"token": []byte("wrong")},
+			}
+			k8sClient := fake.NewClientBuilder().WithObjects(secret).Build()
+			params := plugin.Parameters{Ctx: context.Background(), Client: k8sClient}
+			prom := PrometheusInstant{
+				URL:   url,
+				Query: "secured_metric",
+				Expr:  "value == 1",
+				Auth: SecretAuth{
+					Type:   BearerAuth,
+					Secret: client.ObjectKey{Name: "rotated-creds", Namespace: "default"},
+				},
+			}
+			result, err := prom.Check(params)
+			Expect(err).To(HaveOccurred())
+			Expect(result.Passed).To(BeFalse())
+
+			Expect(k8sClient.Get(params.Ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+			secret.Data["token"] = []byte(token)
+			Expect(k8sClient.Update(params.Ctx, secret)).To(Succeed())
+			// the secret is cached for a while
+			_, err = prom.Check(params)
+			Expect(err).To(HaveOccurred())
+
+			secrets.mutex.Lock()
+			secrets.entries[client.ObjectKeyFromObject(secret)].fetched = time.Time{}
+			secrets.mutex.Unlock()
+			result, err = prom.Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeTrue())
+		})
 	})
 })
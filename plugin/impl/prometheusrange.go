@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/PaesslerAG/gval"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/sapcc/ucfgwrap"
+
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+// PrometheusRange is a check plugin that runs a range query against a prometheus
+// and passes if a sufficient fraction of the returned samples satisfies a given expression.
+type PrometheusRange struct {
+	URL      string
+	Query    string
+	Expr     string
+	Lookback time.Duration
+	Step     time.Duration
+	// fraction of samples, which need to satisfy the expression
+	Fraction           float64
+	PassOnEmpty        bool
+	Auth               SecretAuth
+	InsecureSkipVerify bool
+}
+
+// New creates a new PrometheusRange instance with the given config.
+func (pr *PrometheusRange) New(config *ucfgwrap.Config) (plugin.Checker, error) {
+	conf := struct {
+		URL                string        `config:"url" validate:"required"`
+		Query              string        `config:"query" validate:"required"`
+		Expr               string        `config:"expr" validate:"required"`
+		Lookback           time.Duration `config:"lookback"`
+		Step               time.Duration `config:"step"`
+		Fraction           float64       `config:"fraction"`
+		EmptyResult        string        `config:"emptyResult"`
+		Auth               authConfig    `config:"auth"`
+		InsecureSkipVerify bool          `config:"insecureSkipVerify"`
+	}{
+		Lookback:    30 * time.Minute,
+		Step:        time.Minute,
+		Fraction:    1,
+		EmptyResult: "fail",
+	}
+	if err := config.Unpack(&conf); err != nil {
+		return nil, err
+	}
+	if conf.Step <= 0 || conf.Lookback < conf.Step {
+		return nil, errors.New("step needs to be positive and lookback needs to be at least as long as step")
+	}
+	if conf.Fraction <= 0 || conf.Fraction > 1 {
+		return nil, fmt.Errorf("fraction needs to be within (0, 1], got: %v", conf.Fraction)
+	}
+	if conf.EmptyResult != "pass" && conf.EmptyResult != "fail" {
+		return nil, fmt.Errorf("emptyResult needs to be either pass or fail, got: %s", conf.EmptyResult)
+	}
+	auth, err := conf.Auth.toSecretAuth()
+	if err != nil {
+		return nil, err
+	}
+	return &PrometheusRange{
+		URL:                conf.URL,
+		Query:              conf.Query,
+		Expr:               conf.Expr,
+		Lookback:           conf.Lookback,
+		Step:               conf.Step,
+		Fraction:           conf.Fraction,
+		PassOnEmpty:        conf.EmptyResult == "pass",
+		Auth:               auth,
+		InsecureSkipVerify: conf.InsecureSkipVerify,
+	}, nil
+}
+
+func (pr *PrometheusRange) ID() string {
+	return "prometheusRange"
+}
+
+// Check queries the samples within the lookback and evaluates each of them against the expression.
+func (pr *PrometheusRange) Check(params plugin.Parameters) (plugin.CheckResult, error) {
+	info := map[string]any{"url": pr.URL, "query": pr.Query, "expr": pr.Expr}
+	query, err := plugin.RenderNotificationTemplate(pr.Query, &params)
+	if err != nil {
+		return plugin.Failed(info), fmt.Errorf("failed to render prometheus query: %w", err)
+	}
+	info["query"] = query
+	promAPI, err := promClients.get(&params, pr.URL, pr.Auth, pr.InsecureSkipVerify)
+	if err != nil {
+		return plugin.Failed(info), err
+	}
+	now := time.Now()
+	queryRange := v1.Range{Start: now.Add(-pr.Lookback), End: now, Step: pr.Step}
+	result, warns, err := promAPI.QueryRange(params.Ctx, query, queryRange)
+	if err != nil {
+		return plugin.Failed(info), fmt.Errorf("failed to query prometheus %s: %w", pr.URL, err)
+	}
+	if len(warns) > 0 {
+		info["warns"] = warns
+	}
+	matrix, ok := result.(model.Matrix)
+	if !ok {
+		return plugin.Failed(info), errors.New("result from prometheus is not a matrix")
+	}
+	values := make([]float64, 0)
+	for _, series := range matrix {
+		for _, sample := range series.Values {
+			// a ratio without any traffic yields NaN, which carries no information
+			if math.IsNaN(float64(sample.Value)) {
+				continue
+			}
+			values = append(values, float64(sample.Value))
+		}
+	}
+	return pr.evaluate(&params, values, info)
+}
+
+func (pr *PrometheusRange) evaluate(params *plugin.Parameters, values []float64,
+	info map[string]any) (plugin.CheckResult, error) {
+
+	info["samples"] = len(values)
+	if len(values) == 0 {
+		info["reason"] = "empty result"
+		if pr.PassOnEmpty {
+			return plugin.Passed(info), nil
+		}
+		return plugin.Failed(info), nil
+	}
+	evaluable, err := gval.Full().NewEvaluable(pr.Expr)
+	if err != nil {
+		return plugin.Failed(info), err
+	}
+	minValue, maxValue, sum := math.Inf(1), math.Inf(-1), 0.0
+	matching := 0
+	for _, value := range values {
+		minValue = math.Min(minValue, value)
+		maxValue = math.Max(maxValue, value)
+		sum += value
+		passed, err := evaluable.EvalBool(params.Ctx, map[string]float64{"value": value})
+		if err != nil {
+			return plugin.Failed(info), fmt.Errorf("failed to evaluate prometheus expression: %w", err)
+		}
+		if passed {
+			matching++
+		}
+	}
+	fraction := float64(matching) / float64(len(values))
+	info["min"] = minValue
+	info["max"] = maxValue
+	info["avg"] = sum / float64(len(values))
+	info["matching"] = matching
+	info["fraction"] = fraction
+	if fraction < pr.Fraction {
+		return plugin.Failed(info), nil
+	}
+	return plugin.Passed(info), nil
+}
+
+func (pr *PrometheusRange) OnTransition(params plugin.Parameters) error {
+	return nil
+}
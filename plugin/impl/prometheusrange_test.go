@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sapcc/ucfgwrap"
+
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+const promRangeReply string = "{\"status\":\"success\",\"data\":{\"resultType\":\"matrix\",\"result\":[" +
+	"{\"metric\":{\"zone\":\"a\"},\"values\":[[1680600000,\"0.001\"],[1680600060,\"0.002\"],[1680600120,\"NaN\"]]}," +
+	"{\"metric\":{\"zone\":\"b\"},\"values\":[[1680600000,\"0.003\"],[1680600060,\"0.05\"]]}]}}"
+
+const promRangeEmptyReply string = "{\"status\":\"success\",\"data\":{\"resultType\":\"matrix\",\"result\":[]}}"
+
+var _ = Describe("The prometheusRange plugin", func() {
+
+	It("can parse its configuration", func() {
+		configStr := "url: http://abc.de\nquery: q\nexpr: value < 0.01\nlookback: 1h\nstep: 30s\nfraction: 0.9"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base PrometheusRange
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(plugin).To(Equal(&PrometheusRange{
+			URL:      "http://abc.de",
+			Query:    "q",
+			Expr:     "value < 0.01",
+			Lookback: time.Hour,
+			Step:     30 * time.Second,
+			Fraction: 0.9,
+		}))
+	})
+
+	It("uses defaults for lookback, step and fraction", func() {
+		config, err := ucfgwrap.FromYAML([]byte("url: http://abc.de\nquery: q\nexpr: value < 0.01"))
+		Expect(err).To(Succeed())
+		var base PrometheusRange
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(plugin).To(HaveField("Lookback", 30*time.Minute))
+		Expect(plugin).To(HaveField("Step", time.Minute))
+		Expect(plugin).To(HaveField("Fraction", 1.0))
+	})
+
+	It("rejects invalid configurations", func() {
+		for _, configStr := range []string{
+			"url: http://abc.de\nexpr: value < 1",
+			"url: http://abc.de\nquery: q\nexpr: value < 1\nfraction: 1.5",
+			"url: http://abc.de\nquery: q\nexpr: value < 1\nfraction: 0",
+			"url: http://abc.de\nquery: q\nexpr: value < 1\nlookback: 30s\nstep: 1m",
+			"url: http://abc.de\nquery: q\nexpr: value < 1\nemptyResult: maybe",
+		} {
+			config, err := ucfgwrap.FromYAML([]byte(configStr))
+			Expect(err).To(Succeed())
+			var base PrometheusRange
+			_, err = base.New(&config)
+			Expect(err).To(HaveOccurred(), configStr)
+		}
+	})
+
+	Context("with a fake prometheus", func() {
+		var (
+			server *httptest.Server
+			ranges chan [3]string
+		)
+
+		BeforeEach(func() {
+			ranges = make(chan [3]string, 10)
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				Expect(r.URL.Path).To(Equal("/api/v1/query_range"))
+				Expect(r.ParseForm()).To(Succeed())
+				ranges <- [3]string{r.Form.Get("start"), r.Form.Get("end"), r.Form.Get("step")}
+				reply := "{}"
+				switch r.Form.Get("query") {
+				case "error_ratio":
+					reply = promRangeReply
+				case "no_traffic":
+					reply = promRangeEmptyReply
+				}
+				_, err := w.Write([]byte(reply))
+				Expect(err).To(Succeed())
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		makeRange := func(fraction float64) PrometheusRange {
+			return PrometheusRange{
+				URL:      server.URL,
+				Query:    "error_ratio",
+				Expr:     "value < 0.01",
+				Lookback: 30 * time.Minute,
+				Step:     time.Minute,
+				Fraction: fraction,
+			}
+		}
+
+		It("queries the configured range", func() {
+			prom := makeRange(1)
+			_, err := prom.Check(plugin.Parameters{Ctx: context.Background()})
+			Expect(err).To(Succeed())
+			var queried [3]string
+			Eventually(ranges).Should(Receive(&queried))
+			start, err := strconv.ParseFloat(queried[0], 64)
+			Expect(err).To(Succeed())
+			end, err := strconv.ParseFloat(queried[1], 64)
+			Expect(err).To(Succeed())
+			Expect(end - start).To(BeNumerically("~", 1800, 1))
+			Expect(queried[2]).To(Equal("60"))
+		})
+
+		It("passes if the required fraction of samples satisfies the expression", func() {
+			prom := makeRange(0.75)
+			result, err := prom.Check(plugin.Parameters{Ctx: context.Background()})
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeTrue())
+			Expect(result.Info).To(HaveKeyWithValue("samples", 4))
+			Expect(result.Info).To(HaveKeyWithValue("matching", 3))
+			Expect(result.Info).To(HaveKeyWithValue("min", 0.001))
+			Expect(result.Info).To(HaveKeyWithValue("max", 0.05))
+			Expect(result.Info).To(HaveKeyWithValue("avg", BeNumerically("~", 0.014, 1e-9)))
+		})
+
+		It("fails if too few samples satisfy the expression", func() {
+			prom := makeRange(1)
+			result, err := prom.Check(plugin.Parameters{Ctx: context.Background()})
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.Info).To(HaveKeyWithValue("fraction", 0.75))
+		})
+
+		It("treats empty results according to the configuration", func() {
+			prom := makeRange(1)
+			prom.Query = "no_traffic"
+			result, err := prom.Check(plugin.Parameters{Ctx: context.Background()})
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeFalse())
+
+			prom.PassOnEmpty = true
+			result, err = prom.Check(plugin.Parameters{Ctx: context.Background()})
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeTrue())
+		})
+
+		It("fails if the result is not a matrix", func() {
+			prom := makeRange(1)
+			prom.Query = "unknown"
+			result, err := prom.Check(plugin.Parameters{Ctx: context.Background()})
+			Expect(err).To(HaveOccurred())
+			Expect(result.Passed).To(BeFalse())
+		})
+	})
+})
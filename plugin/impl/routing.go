@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+// routeConfig is the configuration of a notification route shared by the slack and mail plugins.
+type routeConfig struct {
+	Selector string   `config:"selector" validate:"required"`
+	Channel  string   `config:"channel"`
+	To       []string `config:"to"`
+}
+
+// buildRoutes validates the given route configurations and parses their selectors.
+// No routes yield nil, so instances without routes compare equal to the ones without the option.
+func buildRoutes(confs []routeConfig) ([]NotificationRoute, error) {
+	if len(confs) == 0 {
+		return nil, nil
+	}
+	routes := make([]NotificationRoute, 0, len(confs))
+	for _, conf := range confs {
+		selector, err := labels.Parse(conf.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid route selector %s: %w", conf.Selector, err)
+		}
+		routes = append(routes, NotificationRoute{Selector: selector, Channel: conf.Channel, To: conf.To})
+	}
+	return routes, nil
+}
+
+// NotificationRoute sends the notifications of nodes matching the label selector to another
+// channel or other recipients than configured for the notification instance.
+type NotificationRoute struct {
+	Selector labels.Selector
+	Channel  string
+	To       []string
+}
+
+// matchRoute returns the first route with a selector matching the labels of the node.
+func matchRoute(routes []NotificationRoute, node *corev1.Node) (NotificationRoute, bool) {
+	if node == nil {
+		return NotificationRoute{}, false
+	}
+	for _, route := range routes {
+		if route.Selector.Matches(labels.Set(node.Labels)) {
+			return route, true
+		}
+	}
+	return NotificationRoute{}, false
+}
+
+// resolveChannel renders the channel of the first matching route with a channel or the fallback otherwise.
+func resolveChannel(params *plugin.Parameters, routes []NotificationRoute, fallback string) (string, error) {
+	channel := fallback
+	if route, ok := matchRoute(routes, params.Node); ok && route.Channel != "" {
+		channel = route.Channel
+	}
+	rendered, err := plugin.RenderNotificationTemplate(channel, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to render channel: %w", err)
+	}
+	return rendered, nil
+}
+
+// resolveRecipients renders the recipients of the first matching route with recipients or the fallback otherwise.
+// Recipients rendering to an empty string are dropped.
+func resolveRecipients(params *plugin.Parameters, routes []NotificationRoute, fallback []string) ([]string, error) {
+	recipients := fallback
+	if route, ok := matchRoute(routes, params.Node); ok && len(route.To) > 0 {
+		recipients = route.To
+	}
+	rendered := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		value, err := plugin.RenderNotificationTemplate(recipient, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render recipient: %w", err)
+		}
+		if value != "" {
+			rendered = append(rendered, value)
+		}
+	}
+	if len(rendered) == 0 {
+		return nil, errors.New("no recipients left after rendering")
+	}
+	return rendered, nil
+}
+
+// recordTarget logs the target a notification has been sent to and records it as an event on the node.
+func recordTarget(params *plugin.Parameters, pluginID, target string) {
+	if params.Node == nil {
+		return
+	}
+	params.Log.Info("Sent notification", "node", params.Node.Name, "plugin", pluginID, "target", target)
+	if params.Recorder != nil {
+		params.Recorder.Eventf(params.Node, nil, corev1.EventTypeNormal, "NotificationSent", "Notify",
+			"Sent %s notification of profile %s to %s", pluginID, params.Profile, target)
+	}
+}
+
+// channelSuffix derives a suffix for names of Kubernetes objects from a channel name,
+// which may contain characters not allowed in object names.
+func channelSuffix(channel string) string {
+	hash := sha256.Sum256([]byte(channel))
+	return hex.EncodeToString(hash[:])[:8]
+}
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sapcc/ucfgwrap"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+var _ = Describe("Notification routing", func() {
+	var routes []NotificationRoute
+
+	BeforeEach(func() {
+		var err error
+		routes, err = buildRoutes([]routeConfig{
+			{Selector: "pool=gpu", Channel: "#gpu"},
+			{Selector: "team in (storage)", To: []string{"storage@example.com", "{{ .Node.Labels.oncall }}"}},
+		})
+		Expect(err).To(Succeed())
+	})
+
+	makeParams := func(nodeLabels map[string]string) plugin.Parameters {
+		return plugin.Parameters{
+			Ctx:  context.Background(),
+			Node: &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "targetnode", Labels: nodeLabels}},
+		}
+	}
+
+	It("rejects invalid selectors", func() {
+		_, err := buildRoutes([]routeConfig{{Selector: "pool in gpu"}})
+		Expect(err).ToNot(Succeed())
+		routes, err := buildRoutes(nil)
+		Expect(err).To(Succeed())
+		Expect(routes).To(BeNil())
+	})
+
+	It("resolves channels", func() {
+		params := makeParams(map[string]string{"pool": "gpu", "team": "storage"})
+		channel, err := resolveChannel(&params, routes, "#default")
+		Expect(err).To(Succeed())
+		Expect(channel).To(Equal("#gpu"))
+
+		params = makeParams(map[string]string{"team": "storage", "zone": "a"})
+		channel, err = resolveChannel(&params, routes, "#maintenance-{{ .Node.Labels.zone }}")
+		Expect(err).To(Succeed())
+		Expect(channel).To(Equal("#maintenance-a"))
+	})
+
+	It("resolves recipients", func() {
+		params := makeParams(map[string]string{"team": "storage", "oncall": "alice@example.com"})
+		recipients, err := resolveRecipients(&params, routes, []string{"ops@example.com"})
+		Expect(err).To(Succeed())
+		Expect(recipients).To(Equal([]string{"storage@example.com", "alice@example.com"}))
+
+		params = makeParams(map[string]string{"pool": "gpu"})
+		recipients, err = resolveRecipients(&params, routes, []string{"ops@example.com"})
+		Expect(err).To(Succeed())
+		Expect(recipients).To(Equal([]string{"ops@example.com"}))
+
+		_, err = resolveRecipients(&params, nil, []string{"{{ .Node.Labels.oncall }}"})
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("records the target as an event", func() {
+		recorder := events.NewFakeRecorder(1)
+		params := makeParams(nil)
+		params.Profile = "profile"
+		params.Recorder = recorder
+		recordTarget(&params, "slack", "#gpu")
+		Expect(recorder.Events).To(Receive(ContainSubstring("#gpu")))
+	})
+
+	It("is parsed by the mail plugin", func() {
+		configStr := "auth: false\nmessage: msg\nsubject: sub\naddress: localhost:25\n" +
+			"from: a@example.com\nto: [b@example.com]\nroutes:\n- selector: pool=gpu\n  to: [c@example.com]\n"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base Mail
+		notifier, err := base.New(&config)
+		Expect(err).To(Succeed())
+		mail, ok := notifier.(*Mail)
+		Expect(ok).To(BeTrue())
+		Expect(mail.Routes).To(HaveLen(1))
+		Expect(mail.Routes[0].Selector.String()).To(Equal("pool=gpu"))
+		Expect(mail.Routes[0].To).To(Equal([]string{"c@example.com"}))
+	})
+})
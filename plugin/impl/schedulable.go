@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"github.com/sapcc/ucfgwrap"
+
+	"github.com/sapcc/maintenance-controller/common"
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+// Schedulable is a check plugin that checks whether a node is schedulable.
+// The info reports who cordoned the node and whether the controller would skip uncordoning it.
+type Schedulable struct{}
+
+// New creates a new Schedulable instance with the given config.
+func (s *Schedulable) New(config *ucfgwrap.Config) (plugin.Checker, error) {
+	return &Schedulable{}, nil
+}
+
+func (s *Schedulable) ID() string {
+	return "schedulable"
+}
+
+func (s *Schedulable) Check(params plugin.Parameters) (plugin.CheckResult, error) {
+	node := params.Node
+	if !node.Spec.Unschedulable {
+		return plugin.Passed(nil), nil
+	}
+	info := map[string]any{}
+	if record, ok := common.GetCordonRecord(node); ok {
+		info["cordonedBy"] = record.By
+		info["cordonReason"] = record.Reason
+		info["cordonTime"] = record.Time
+	}
+	// Uncordon works on a copy, so the node is not altered by checking
+	if uncordoned, reason := common.Uncordon(node.DeepCopy()); !uncordoned {
+		info["uncordonSkipped"] = reason
+	}
+	return plugin.Failed(info), nil
+}
+
+func (s *Schedulable) OnTransition(params plugin.Parameters) error {
+	return nil
+}
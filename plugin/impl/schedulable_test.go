@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/sapcc/maintenance-controller/common"
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+var _ = Describe("The Schedulable plugin", func() {
+
+	It("passes for schedulable nodes", func() {
+		result, err := (&Schedulable{}).Check(plugin.Parameters{Node: &corev1.Node{}})
+		Expect(err).To(Succeed())
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("reports the cordon owned by the controller", func() {
+		node := &corev1.Node{}
+		Expect(common.Cordon(node, "testing")).To(Succeed())
+		result, err := (&Schedulable{}).Check(plugin.Parameters{Node: node})
+		Expect(err).To(Succeed())
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Info).To(HaveKeyWithValue("cordonReason", "testing"))
+		Expect(result.Info).ToNot(HaveKey("uncordonSkipped"))
+		Expect(node.Spec.Unschedulable).To(BeTrue())
+	})
+
+	It("reports skipped uncordons", func() {
+		node := &corev1.Node{Spec: corev1.NodeSpec{Unschedulable: true}}
+		result, err := (&Schedulable{}).Check(plugin.Parameters{Node: node})
+		Expect(err).To(Succeed())
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Info).To(HaveKey("uncordonSkipped"))
+	})
+
+})
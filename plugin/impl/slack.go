@@ -9,7 +9,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/sapcc/ucfgwrap"
@@ -20,6 +23,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/sapcc/maintenance-controller/common"
+	"github.com/sapcc/maintenance-controller/constants"
 	"github.com/sapcc/maintenance-controller/plugin"
 )
 
@@ -29,19 +34,26 @@ type SlackWebhook struct {
 	Hook    string
 	Channel string
 	Message string
+	// routes nodes to other channels, the first matching route is used
+	Routes []NotificationRoute
 }
 
 // New creates a new Slack instance with the given config.
 func (sw *SlackWebhook) New(config *ucfgwrap.Config) (plugin.Notifier, error) {
 	conf := struct {
-		Hook    string `config:"hook" validate:"required"`
-		Channel string `config:"channel" validate:"required"`
-		Message string `config:"message" validate:"required"`
+		Hook    string        `config:"hook" validate:"required"`
+		Channel string        `config:"channel" validate:"required"`
+		Message string        `config:"message" validate:"required"`
+		Routes  []routeConfig `config:"routes"`
 	}{}
 	if err := config.Unpack(&conf); err != nil {
 		return nil, err
 	}
-	return &SlackWebhook{Hook: conf.Hook, Channel: conf.Channel, Message: conf.Message}, nil
+	routes, err := buildRoutes(conf.Routes)
+	if err != nil {
+		return nil, err
+	}
+	return &SlackWebhook{Hook: conf.Hook, Channel: conf.Channel, Message: conf.Message, Routes: routes}, nil
 }
 
 func (sw *SlackWebhook) ID() string {
@@ -54,10 +66,14 @@ func (sw *SlackWebhook) Notify(params plugin.Parameters) error {
 	if err != nil {
 		return err
 	}
+	channel, err := resolveChannel(&params, sw.Routes, sw.Channel)
+	if err != nil {
+		return err
+	}
 	msg := struct {
 		Text    string `json:"text"`
 		Channel string `json:"channel"`
-	}{Text: theMessage, Channel: sw.Channel}
+	}{Text: theMessage, Channel: channel}
 	marshaled, err := json.Marshal(msg)
 	if err != nil {
 		return err
@@ -79,6 +95,7 @@ func (sw *SlackWebhook) Notify(params plugin.Parameters) error {
 	if string(bodyBytes) != "ok" {
 		return errors.New("slack webhook response is not ok")
 	}
+	recordTarget(&params, sw.ID(), channel)
 	return nil
 }
 
@@ -92,7 +109,23 @@ type SlackThread struct {
 	Message   string
 	LeaseName types.NamespacedName
 	Period    time.Duration
-	testURL   string
+	// adds approval buttons to the replies, if set
+	Approval *SlackApproval
+	// posts one message per node and profile, which is edited in place, instead of replying to a thread
+	Live bool
+	// routes nodes to other channels, the first matching route is used
+	Routes  []NotificationRoute
+	testURL string
+}
+
+// SlackApproval configures the label or annotation set by the buttons of interactive slack messages.
+type SlackApproval struct {
+	Label      string
+	Annotation string
+	Approve    string
+	Deny       string
+	// postpone button is omitted, if zero
+	Postpone time.Duration
 }
 
 // New creates a new Slack instance with the given config.
@@ -102,13 +135,54 @@ func (st *SlackThread) New(config *ucfgwrap.Config) (plugin.Notifier, error) {
 		Channel        string        `config:"channel" validate:"required"`
 		Title          string        `config:"title" validate:"required"`
 		Message        string        `config:"message" validate:"required"`
-		LeaseName      string        `config:"leaseName" validate:"required"`
-		LeaseNamespace string        `config:"leaseNamespace" validate:"required"`
-		Period         time.Duration `config:"period" validate:"required"`
-	}{}
+		Mode           string        `config:"mode"`
+		LeaseName      string        `config:"leaseName"`
+		LeaseNamespace string        `config:"leaseNamespace"`
+		Period         time.Duration `config:"period"`
+		Routes         []routeConfig `config:"routes"`
+		Approval       struct {
+			Label      string        `config:"label"`
+			Annotation string        `config:"annotation"`
+			Approve    string        `config:"approve"`
+			Deny       string        `config:"deny"`
+			Postpone   time.Duration `config:"postpone"`
+		} `config:"approval"`
+	}{Mode: "thread"}
+	conf.Approval.Approve = constants.TrueStr
+	conf.Approval.Deny = "false"
+	conf.Approval.Postpone = 24 * time.Hour
 	if err := config.Unpack(&conf); err != nil {
 		return nil, err
 	}
+	switch conf.Mode {
+	case "thread":
+		if conf.LeaseName == "" || conf.LeaseNamespace == "" || conf.Period <= 0 {
+			return nil, errors.New("slack threads need a leaseName, leaseNamespace and period")
+		}
+	case "live":
+	default:
+		return nil, fmt.Errorf("slack mode needs to be either thread or live, got: %s", conf.Mode)
+	}
+	routes, err := buildRoutes(conf.Routes)
+	if err != nil {
+		return nil, err
+	}
+	var approval *SlackApproval
+	if conf.Approval.Label != "" || conf.Approval.Annotation != "" {
+		if conf.Approval.Label != "" && conf.Approval.Annotation != "" {
+			return nil, errors.New("slack approvals need either a label or an annotation, not both")
+		}
+		if conf.Approval.Postpone < 0 {
+			return nil, errors.New("slack approval postpone duration must not be negative")
+		}
+		approval = &SlackApproval{
+			Label:      conf.Approval.Label,
+			Annotation: conf.Approval.Annotation,
+			Approve:    conf.Approval.Approve,
+			Deny:       conf.Approval.Deny,
+			Postpone:   conf.Approval.Postpone,
+		}
+	}
 	return &SlackThread{
 		testURL: "",
 		Token:   conf.Token,
@@ -120,6 +194,9 @@ func (st *SlackThread) New(config *ucfgwrap.Config) (plugin.Notifier, error) {
 			Namespace: conf.LeaseNamespace,
 			Name:      conf.LeaseName,
 		},
+		Approval: approval,
+		Live:     conf.Mode == "live",
+		Routes:   routes,
 	}, nil
 }
 
@@ -139,15 +216,62 @@ func (st *SlackThread) makeSlack() *slack.Client {
 }
 
 func (st *SlackThread) Notify(params plugin.Parameters) error {
+	if st.Approval != nil && params.Node != nil && common.IsPostponed(params.Node, params.Profile, time.Now()) {
+		params.Log.Info("Skipping slack notification, as the approval has been postponed", "node", params.Node.Name)
+		return nil
+	}
+	routed, err := st.route(&params)
+	if err != nil {
+		return err
+	}
+	if err := routed.notify(&params); err != nil {
+		return err
+	}
+	recordTarget(&params, st.ID(), routed.Channel)
+	return nil
+}
+
+// NotifyStateChange finishes the live message of the node and profile once the profile is operational again,
+// regardless of the schedule, so the next maintenance starts a new message.
+func (st *SlackThread) NotifyStateChange(params plugin.Parameters, previous string) error {
+	if !st.Live || params.State != "operational" || previous == "operational" {
+		return nil
+	}
+	routed, err := st.route(&params)
+	if err != nil {
+		return err
+	}
+	return routed.finishLive(&params, routed.makeSlack())
+}
+
+// route returns a copy of the instance, which uses the channel resolved for the node throughout,
+// so each channel gets its own thread.
+func (st *SlackThread) route(params *plugin.Parameters) (*SlackThread, error) {
+	channel, err := resolveChannel(params, st.Routes, st.Channel)
+	if err != nil {
+		return nil, err
+	}
+	routed := *st
+	routed.Channel = channel
+	if channel != st.Channel {
+		routed.LeaseName.Name = st.LeaseName.Name + "-" + channelSuffix(channel)
+	}
+	return &routed, nil
+}
+
+func (st *SlackThread) notify(params *plugin.Parameters) error {
 	api := st.makeSlack()
+	if st.Live {
+		return st.updateLive(params, api)
+	}
 	var lease coordinationv1.Lease
 	err := params.Client.Get(params.Ctx, st.LeaseName, &lease)
 	if k8serrors.IsNotFound(err) {
-		parentTS, err := st.startThread(&params, api)
+		parentTS, err := st.startThread(params, api)
 		if err != nil {
 			return fmt.Errorf("failed to create slack thread: %w", err)
 		}
-		err = st.createLease(&params, parentTS)
+		err = st.createLease(params, parentTS)
 		if err != nil {
 			return fmt.Errorf("failed to create slack thread lease %s: %w", st.LeaseName, err)
 		}
@@ -161,18 +285,18 @@ func (st *SlackThread) Notify(params plugin.Parameters) error {
 		if lease.Spec.HolderIdentity == nil {
 			return errors.New("slack thread leases has no holder")
 		}
-		err := st.replyMessage(&params, api, *lease.Spec.HolderIdentity)
+		err := st.replyMessage(params, api, *lease.Spec.HolderIdentity)
 		if err != nil {
 			return fmt.Errorf("failed to reply to slack thread: %w", err)
 		}
 		return nil
 	}
-	parentTS, err := st.startThread(&params, api)
+	parentTS, err := st.startThread(params, api)
 	if err != nil {
 		return fmt.Errorf("failed to create slack thread: %w", err)
 	}
 	// update Lease
-	err = st.updateLease(&params, parentTS, &lease)
+	err = st.updateLease(params, parentTS, &lease)
 	if err != nil {
 		return fmt.Errorf("failed to update slack thread lease %s: %w", st.LeaseName, err)
 	}
@@ -208,14 +332,209 @@ func (st *SlackThread) replyMessage(params *plugin.Parameters, api *slack.Client
 	if err != nil {
 		return err
 	}
-	_, _, err = api.PostMessageContext(params.Ctx, st.Channel,
-		slack.MsgOptionText(theMessage, true), slack.MsgOptionTS(parentTS))
+	options := []slack.MsgOption{slack.MsgOptionText(theMessage, true), slack.MsgOptionTS(parentTS)}
+	if st.awaitsApproval(params) {
+		blocks, err := st.approvalBlocks(params, theMessage)
+		if err != nil {
+			return err
+		}
+		options = append(options, slack.MsgOptionBlocks(blocks...))
+	}
+	_, _, err = api.PostMessageContext(params.Ctx, st.Channel, options...)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// awaitsApproval returns whether approval buttons are attached to messages.
+// Buttons are only rendered in the maintenance-required state, as approving in any other state
+// would silently approve the next maintenance.
+func (st *SlackThread) awaitsApproval(params *plugin.Parameters) bool {
+	return st.Approval != nil && params.State == "maintenance-required"
+}
+
+// approvalBlocks renders the message followed by buttons, which carry the change to apply to the node.
+// Clicks are handled by the slack interaction endpoint of the api server, which rejects them
+// unless the profile is still in the maintenance-required state entered at the transition time embedded in the buttons.
+func (st *SlackThread) approvalBlocks(params *plugin.Parameters, message string) ([]slack.Block, error) {
+	if params.Node == nil {
+		return nil, errors.New("slack approvals require a node")
+	}
+	buttons := make([]slack.BlockElement, 0)
+	for _, action := range []struct {
+		action common.ApprovalAction
+		text   string
+		value  string
+		style  slack.Style
+	}{
+		{action: common.ApprovalApprove, text: "Approve", value: st.Approval.Approve, style: slack.StylePrimary},
+		{action: common.ApprovalDeny, text: "Deny", value: st.Approval.Deny, style: slack.StyleDanger},
+		{action: common.ApprovalPostpone, text: "Postpone " + st.Approval.Postpone.String()},
+	} {
+		if action.action == common.ApprovalPostpone && st.Approval.Postpone == 0 {
+			continue
+		}
+		request := common.ApprovalRequest{
+			Node:       params.Node.Name,
+			Profile:    params.Profile,
+			Action:     action.action,
+			Label:      st.Approval.Label,
+			Annotation: st.Approval.Annotation,
+			Value:      action.value,
+			Transition: params.LastTransition,
+		}
+		if action.action == common.ApprovalPostpone {
+			request.Value = ""
+			request.Postpone = st.Approval.Postpone
+		}
+		value, err := json.Marshal(request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal approval request: %w", err)
+		}
+		button := slack.NewButtonBlockElement(common.ApprovalBlockID+"-"+string(action.action), string(value),
+			slack.NewTextBlockObject(slack.PlainTextType, action.text, false, false))
+		if action.style != "" {
+			button = button.WithStyle(action.style)
+		}
+		buttons = append(buttons, button)
+	}
+	return []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, message, false, false), nil, nil),
+		slack.NewActionBlock(common.ApprovalBlockID, buttons...),
+	}, nil
+}
+
+// updateLive edits the message of the node and profile or posts it, if there is none.
+// The reference to the message is kept in the node data per instance, channel and profile.
+// Once the profile is operational again, the message is finished by NotifyStateChange.
+func (st *SlackThread) updateLive(params *plugin.Parameters, api *slack.Client) error {
+	if params.Messages == nil || params.Node == nil {
+		return errors.New("live slack messages require a node and its data to keep track of messages")
+	}
+	if params.State == "operational" {
+		// finishing has failed or has not been invoked on the state change
+		return st.finishLive(params, api)
+	}
+	text, err := st.liveText(params, time.Now())
+	if err != nil {
+		return err
+	}
+	blocks := make([]slack.Block, 0)
+	if st.awaitsApproval(params) {
+		blocks, err = st.approvalBlocks(params, text)
+		if err != nil {
+			return err
+		}
+	}
+	updated, err := st.updateLiveMessage(params, api, text, blocks)
+	if err != nil || updated {
+		return err
+	}
+	options := []slack.MsgOption{slack.MsgOptionText(text, false)}
+	if len(blocks) > 0 {
+		options = append(options, slack.MsgOptionBlocks(blocks...))
+	}
+	channelID, ts, err := api.PostMessageContext(params.Ctx, st.Channel, options...)
+	if err != nil {
+		return fmt.Errorf("failed to post message to slack: %w", err)
+	}
+	params.Messages[st.liveMessageKey(params)] = channelID + "/" + ts
+	return nil
+}
+
+// finishLive updates the message of the node and profile a last time and forgets it,
+// so the next maintenance gets a new message.
+func (st *SlackThread) finishLive(params *plugin.Parameters, api *slack.Client) error {
+	if _, ok := params.Messages[st.liveMessageKey(params)]; !ok {
+		return nil
+	}
+	text, err := st.liveText(params, time.Now())
+	if err != nil {
+		return err
+	}
+	if _, err := st.updateLiveMessage(params, api, text, nil); err != nil {
+		return err
+	}
+	delete(params.Messages, st.liveMessageKey(params))
+	return nil
+}
+
+// updateLiveMessage edits the message referenced in the node data.
+// It returns false, if there is no message or it has been deleted in the meantime, in which case it is forgotten.
+func (st *SlackThread) updateLiveMessage(params *plugin.Parameters, api *slack.Client,
+	text string, blocks []slack.Block) (bool, error) {
+
+	key := st.liveMessageKey(params)
+	ref, ok := params.Messages[key]
+	if !ok {
+		return false, nil
+	}
+	channelID, ts, _ := strings.Cut(ref, "/")
+	// passing no blocks removes the buttons of previous updates
+	_, _, _, err := api.UpdateMessageContext(params.Ctx, channelID, ts,
+		slack.MsgOptionText(text, false), slack.MsgOptionBlocks(blocks...))
+	switch {
+	case err == nil:
+		return true, nil
+	case err.Error() == "message_not_found":
+		delete(params.Messages, key)
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to update slack message: %w", err)
+	}
+}
+
+// liveMessageKey identifies the live message of the node and profile in the node data.
+func (st *SlackThread) liveMessageKey(params *plugin.Parameters) string {
+	return fmt.Sprintf("slack/%s/%s/%s", params.Instance, st.Channel, params.Profile)
+}
+
+// liveText renders the title and message followed by the state and the checks,
+// which failed during the last reconciliation.
+func (st *SlackThread) liveText(params *plugin.Parameters, now time.Time) (string, error) {
+	title, err := plugin.RenderNotificationTemplate(st.Title, params)
+	if err != nil {
+		return "", err
+	}
+	message, err := plugin.RenderNotificationTemplate(st.Message, params)
+	if err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "*%s*\n%s\n*State:* %s", title, message, params.State)
+	if !params.LastTransition.IsZero() {
+		fmt.Fprintf(&builder, " since %s", params.LastTransition.UTC().Format(time.RFC3339))
+	}
+	failing := make(map[string]string)
+	for _, chain := range params.LastCheckResults {
+		if chain.Passed {
+			continue
+		}
+		for name, result := range chain.Info {
+			if result.Passed {
+				continue
+			}
+			reason := ""
+			if value, ok := result.Info["reason"]; ok {
+				reason = fmt.Sprint(value)
+			}
+			failing[name] = reason
+		}
+	}
+	if len(failing) > 0 && params.State != "operational" {
+		builder.WriteString("\n*Failing checks:*")
+		for _, name := range slices.Sorted(maps.Keys(failing)) {
+			fmt.Fprintf(&builder, "\n• %s", name)
+			if failing[name] != "" {
+				fmt.Fprintf(&builder, ": %s", failing[name])
+			}
+		}
+	}
+	fmt.Fprintf(&builder, "\n_Updated %s_", now.UTC().Format(time.RFC3339))
+	return builder.String(), nil
+}
+
 func (st *SlackThread) createLease(params *plugin.Parameters, parentTS string) error {
 	var lease coordinationv1.Lease
 	lease.Name = st.LeaseName.Name
@@ -8,15 +8,18 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/sapcc/ucfgwrap"
+	"github.com/slack-go/slack"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/sapcc/maintenance-controller/common"
 	"github.com/sapcc/maintenance-controller/plugin"
 	"github.com/sapcc/maintenance-controller/state"
 )
@@ -113,4 +116,170 @@ var _ = Describe("The slack thread plugin", func() {
 			Period:    time.Minute,
 		}))
 	})
+
+	It("should parse its approval config", func() {
+		configStr := "token: token\n" +
+			"channel: thechannel\n" +
+			"title: title\n" +
+			"message: msg\n" +
+			"leaseName: lease\n" +
+			"leaseNamespace: default\n" +
+			"period: 1m\n" +
+			"approval:\n" +
+			"  label: approved\n" +
+			"  postpone: 2h\n"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base SlackThread
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(plugin.(*SlackThread).Approval).To(Equal(&SlackApproval{
+			Label:    "approved",
+			Approve:  "true",
+			Deny:     "false",
+			Postpone: 2 * time.Hour,
+		}))
+
+		config, err = ucfgwrap.FromYAML([]byte(configStr + "  annotation: approved\n"))
+		Expect(err).To(Succeed())
+		_, err = base.New(&config)
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("should render approval buttons", func() {
+		thread := SlackThread{Approval: &SlackApproval{Annotation: "approved", Approve: "yes", Deny: "no"}}
+		transition := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		params := plugin.Parameters{
+			Node:           &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "targetnode"}},
+			Profile:        "profile",
+			State:          "maintenance-required",
+			LastTransition: transition,
+		}
+		Expect(thread.awaitsApproval(&params)).To(BeTrue())
+		blocks, err := thread.approvalBlocks(&params, "msg")
+		Expect(err).To(Succeed())
+		Expect(blocks).To(HaveLen(2))
+		actions, ok := blocks[1].(*slack.ActionBlock)
+		Expect(ok).To(BeTrue())
+		Expect(actions.BlockID).To(Equal(common.ApprovalBlockID))
+		// the postpone button is omitted without a duration
+		Expect(actions.Elements.ElementSet).To(HaveLen(2))
+		approve, ok := actions.Elements.ElementSet[0].(*slack.ButtonBlockElement)
+		Expect(ok).To(BeTrue())
+		var request common.ApprovalRequest
+		Expect(json.Unmarshal([]byte(approve.Value), &request)).To(Succeed())
+		Expect(request).To(Equal(common.ApprovalRequest{
+			Node:       "targetnode",
+			Profile:    "profile",
+			Action:     common.ApprovalApprove,
+			Annotation: "approved",
+			Value:      "yes",
+			Transition: transition,
+		}))
+		Expect(request.Validate()).To(Succeed())
+
+		for _, state := range []string{"operational", "in-maintenance"} {
+			params.State = state
+			Expect(thread.awaitsApproval(&params)).To(BeFalse())
+		}
+	})
+
+	It("should require a lease in thread mode only", func() {
+		configStr := "token: token\n" +
+			"channel: thechannel\n" +
+			"title: title\n" +
+			"message: msg\n"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base SlackThread
+		_, err = base.New(&config)
+		Expect(err).ToNot(Succeed())
+
+		config, err = ucfgwrap.FromYAML([]byte(configStr + "mode: live\n"))
+		Expect(err).To(Succeed())
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(plugin.(*SlackThread).Live).To(BeTrue())
+	})
+
+	Context("in live mode", func() {
+		var server *httptest.Server
+		var calls []string
+		var thread SlackThread
+		var params plugin.Parameters
+
+		BeforeEach(func() {
+			calls = make([]string, 0)
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				Expect(r.ParseForm()).To(Succeed())
+				calls = append(calls, r.URL.Path+" "+r.Form.Get("text"))
+				w.Header().Set("Content-Type", "application/json")
+				_, err := w.Write([]byte(`{"ok":true,"channel":"C123","ts":"1700000000.000100"}`))
+				Expect(err).To(Succeed())
+			}))
+			thread = SlackThread{Channel: "#thechannel", Title: "{{ .Node.Name }}", Message: "msg", Live: true}
+			thread.SetTestURL(server.URL + "/")
+			params = plugin.Parameters{
+				Ctx:      context.Background(),
+				Node:     &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "targetnode"}},
+				Profile:  "profile",
+				Instance: "live",
+				State:    string(state.Required),
+				LastCheckResults: []plugin.CheckChainResult{{
+					Passed: false,
+					Info: map[string]plugin.CheckResult{
+						"check_approval": plugin.FailedWithReason("label missing"),
+						"check_window":   plugin.Passed(nil),
+					},
+				}},
+				Messages: make(map[string]string),
+			}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("posts a message once and updates it afterwards", func() {
+			Expect(thread.Notify(params)).To(Succeed())
+			Expect(params.Messages).To(HaveKeyWithValue("slack/live/#thechannel/profile", "C123/1700000000.000100"))
+			Expect(calls).To(HaveLen(1))
+			Expect(calls[0]).To(HavePrefix("/chat.postMessage"))
+			Expect(calls[0]).To(ContainSubstring("targetnode"))
+			Expect(calls[0]).To(ContainSubstring("check_approval: label missing"))
+			Expect(calls[0]).ToNot(ContainSubstring("check_window"))
+
+			params.State = string(state.InMaintenance)
+			Expect(thread.Notify(params)).To(Succeed())
+			Expect(calls).To(HaveLen(2))
+			Expect(calls[1]).To(HavePrefix("/chat.update"))
+			Expect(calls[1]).To(ContainSubstring("in-maintenance"))
+			Expect(params.Messages).To(HaveLen(1))
+		})
+
+		It("posts a message per instance", func() {
+			Expect(thread.Notify(params)).To(Succeed())
+			params.Instance = "other"
+			Expect(thread.Notify(params)).To(Succeed())
+			Expect(calls).To(HaveLen(2))
+			Expect(calls[1]).To(HavePrefix("/chat.postMessage"))
+			Expect(params.Messages).To(HaveLen(2))
+		})
+
+		It("finishes and forgets the message once operational", func() {
+			Expect(thread.Notify(params)).To(Succeed())
+
+			params.State = string(state.Operational)
+			Expect(thread.NotifyStateChange(params, string(state.InMaintenance))).To(Succeed())
+			Expect(calls).To(HaveLen(2))
+			Expect(calls[1]).To(HavePrefix("/chat.update"))
+			Expect(params.Messages).To(BeEmpty())
+
+			// nothing is left to finish
+			Expect(thread.Notify(params)).To(Succeed())
+			Expect(thread.NotifyStateChange(params, string(state.InMaintenance))).To(Succeed())
+			Expect(calls).To(HaveLen(2))
+		})
+	})
 })
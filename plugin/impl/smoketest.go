@@ -0,0 +1,201 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sapcc/ucfgwrap"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sapcc/maintenance-controller/constants"
+	"github.com/sapcc/maintenance-controller/plugin"
+	"github.com/sapcc/maintenance-controller/state"
+)
+
+// pod names are limited to 63 characters by the hostname the kubelet derives from them.
+const maxSmokeTestPrefixLength int = 52
+
+// SmokeTest is a check plugin, which schedules a canary pod onto the cordoned node
+// before it is released into the operational state.
+// It passes once the pod became ready or finished successfully within the timeout.
+type SmokeTest struct {
+	// name prefix of the pod, the full name is made unique per node, profile and transition
+	Name      string
+	Namespace string
+	// pod manifest, which is rendered as template before parsing
+	Template string
+	Timeout  time.Duration
+}
+
+// New creates a new SmokeTest instance with the given config.
+func (st *SmokeTest) New(config *ucfgwrap.Config) (plugin.Checker, error) {
+	conf := struct {
+		Name      string        `config:"name" validate:"required"`
+		Namespace string        `config:"namespace" validate:"required"`
+		Template  string        `config:"template" validate:"required"`
+		Timeout   time.Duration `config:"timeout"`
+	}{Timeout: 5 * time.Minute}
+	if err := config.Unpack(&conf); err != nil {
+		return nil, err
+	}
+	if len(conf.Name) > maxSmokeTestPrefixLength {
+		return nil, fmt.Errorf("smoke test name %s exceeds %d characters", conf.Name, maxSmokeTestPrefixLength)
+	}
+	return &SmokeTest{
+		Name:      conf.Name,
+		Namespace: conf.Namespace,
+		Template:  conf.Template,
+		Timeout:   conf.Timeout,
+	}, nil
+}
+
+func (st *SmokeTest) ID() string {
+	return "smokeTest"
+}
+
+// Key returns the namespaced name of the smoke test pod for the given parameters.
+func (st *SmokeTest) Key(params *plugin.Parameters) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: st.Namespace,
+		Name:      fmt.Sprintf("%s-%s", st.Name, IdempotencyKey(params)[:jobHashLength]),
+	}
+}
+
+// Check creates the smoke test pod if required and evaluates its status.
+// A pod, which failed or did not become ready within the timeout, is deleted,
+// so the next invocation starts another attempt.
+func (st *SmokeTest) Check(params plugin.Parameters) (plugin.CheckResult, error) {
+	if params.State != string(state.InMaintenance) {
+		err := fmt.Errorf("smoke test check plugin failed, node %v is not in in-maintenance but %v state",
+			params.Node.Name, params.State)
+		return plugin.Failed(nil), err
+	}
+	key := st.Key(&params)
+	info := map[string]any{"pod": key.String()}
+	if err := st.collectGarbage(&params); err != nil {
+		params.Log.Error(err, "failed to delete stale smoke test pods", "smokeTest", st.Name)
+	}
+	var pod corev1.Pod
+	err := params.Client.Get(params.Ctx, key, &pod)
+	if k8serrors.IsNotFound(err) {
+		if err := st.createPod(&params); err != nil {
+			return plugin.Failed(info), err
+		}
+		info["reason"] = "smoke test pod has been created"
+		return plugin.Failed(info), nil
+	}
+	if err != nil {
+		return plugin.Failed(info), err
+	}
+	info["phase"] = pod.Status.Phase
+	if pod.DeletionTimestamp != nil {
+		info["reason"] = "previous smoke test pod is terminating"
+		return plugin.Failed(info), nil
+	}
+	if pod.Status.Phase == corev1.PodSucceeded || isPodReady(&pod) {
+		info["reason"] = "smoke test pod is ready or succeeded"
+		return plugin.Passed(info), nil
+	}
+	failed := pod.Status.Phase == corev1.PodFailed
+	timedOut := !pod.CreationTimestamp.IsZero() && time.Since(pod.CreationTimestamp.Time) > st.Timeout
+	if !failed && !timedOut {
+		info["reason"] = "smoke test pod is not ready yet"
+		return plugin.Failed(info), nil
+	}
+	if failed {
+		info["reason"] = "smoke test pod failed"
+	} else {
+		info["reason"] = fmt.Sprintf("smoke test pod did not become ready within %v", st.Timeout)
+	}
+	params.Log.Info("Smoke test failed, deleting pod", "pod", key, "reason", info["reason"])
+	if err := deleteIgnoreNotFound(&params, &pod); err != nil {
+		return plugin.Failed(info), err
+	}
+	return plugin.Failed(info), nil
+}
+
+func (st *SmokeTest) createPod(params *plugin.Parameters) error {
+	var pod corev1.Pod
+	if err := renderManifest(st.Template, params, &pod); err != nil {
+		return fmt.Errorf("failed to render smoke test pod template: %w", err)
+	}
+	key := st.Key(params)
+	pod.Name = key.Name
+	pod.Namespace = key.Namespace
+	if pod.Labels == nil {
+		pod.Labels = make(map[string]string)
+	}
+	pod.Labels[constants.SmokeTestLabelKey] = st.Name
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[constants.SmokeTestNodeAnnotationKey] = params.Node.Name
+	injectNodeScheduling(&pod.Spec, params.Node.Name)
+	if pod.Spec.RestartPolicy == "" {
+		pod.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+	if err := params.Client.Create(params.Ctx, &pod); err != nil {
+		return fmt.Errorf("failed to create smoke test pod %s: %w", key, err)
+	}
+	params.Log.Info("Created smoke test pod", "pod", key)
+	return nil
+}
+
+// collectGarbage deletes smoke test pods, which have been created for the same node during previous transitions.
+func (st *SmokeTest) collectGarbage(params *plugin.Parameters) error {
+	var pods corev1.PodList
+	err := params.Client.List(params.Ctx, &pods,
+		client.InNamespace(st.Namespace), client.MatchingLabels{constants.SmokeTestLabelKey: st.Name})
+	if err != nil {
+		return err
+	}
+	current := st.Key(params).Name
+	var errs []error
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Name == current || pod.Annotations[constants.SmokeTestNodeAnnotationKey] != params.Node.Name {
+			continue
+		}
+		if err := deleteIgnoreNotFound(params, pod); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// OnTransition removes the smoke test pod once the node leaves the in-maintenance state.
+func (st *SmokeTest) OnTransition(params plugin.Parameters) error {
+	var pod corev1.Pod
+	err := params.Client.Get(params.Ctx, st.Key(&params), &pod)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return deleteIgnoreNotFound(&params, &pod)
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func deleteIgnoreNotFound(params *plugin.Parameters, obj client.Object) error {
+	err := params.Client.Delete(params.Ctx, obj)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sapcc/ucfgwrap"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sapcc/maintenance-controller/constants"
+	"github.com/sapcc/maintenance-controller/plugin"
+	"github.com/sapcc/maintenance-controller/state"
+)
+
+const smokeTestTemplate string = `
+metadata:
+  labels:
+    node: "{{ .Node.Name }}"
+spec:
+  containers:
+  - name: canary
+    image: busybox
+    command: ["sleep", "60"]
+`
+
+var _ = Describe("The smokeTest plugin", func() {
+
+	It("can parse its configuration", func() {
+		config, err := ucfgwrap.FromYAML([]byte("name: canary\nnamespace: default\ntemplate: abc\ntimeout: 1m"))
+		Expect(err).To(Succeed())
+		var base SmokeTest
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(plugin).To(Equal(&SmokeTest{
+			Name:      "canary",
+			Namespace: "default",
+			Template:  "abc",
+			Timeout:   time.Minute,
+		}))
+	})
+
+	It("rejects too long name prefixes", func() {
+		configStr := "name: " + strings.Repeat("a", 53) + "\nnamespace: default\ntemplate: abc"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base SmokeTest
+		_, err = base.New(&config)
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("with a fake client", func() {
+		var (
+			k8sClient client.Client
+			params    plugin.Parameters
+			smokeTest SmokeTest
+		)
+
+		BeforeEach(func() {
+			k8sClient = fake.NewClientBuilder().WithStatusSubresource(&corev1.Pod{}).Build()
+			params = plugin.Parameters{
+				Client:         k8sClient,
+				Ctx:            context.Background(),
+				Log:            GinkgoLogr,
+				Node:           &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "targetnode"}},
+				Profile:        "someprofile",
+				State:          string(state.InMaintenance),
+				LastTransition: time.Date(2026, time.March, 3, 10, 0, 0, 0, time.UTC),
+			}
+			smokeTest = SmokeTest{
+				Name:      "canary",
+				Namespace: "default",
+				Template:  smokeTestTemplate,
+				Timeout:   time.Minute,
+			}
+		})
+
+		setStatus := func(ctx SpecContext, status corev1.PodStatus) {
+			var pod corev1.Pod
+			Expect(k8sClient.Get(ctx, smokeTest.Key(&params), &pod)).To(Succeed())
+			pod.Status = status
+			Expect(k8sClient.Status().Update(ctx, &pod)).To(Succeed())
+		}
+
+		It("fails outside of the in-maintenance state", func() {
+			params.State = string(state.Operational)
+			_, err := smokeTest.Check(params)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("creates a pod pinned to the node", func(ctx SpecContext) {
+			result, err := smokeTest.Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeFalse())
+
+			var pod corev1.Pod
+			Expect(k8sClient.Get(ctx, smokeTest.Key(&params), &pod)).To(Succeed())
+			Expect(pod.Name).To(HavePrefix("canary-"))
+			Expect(pod.Labels).To(HaveKeyWithValue("node", "targetnode"))
+			Expect(pod.Labels).To(HaveKeyWithValue(constants.SmokeTestLabelKey, "canary"))
+			Expect(pod.Annotations).To(HaveKeyWithValue(constants.SmokeTestNodeAnnotationKey, "targetnode"))
+			Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+			terms := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+			Expect(terms[0].MatchFields[0].Values).To(HaveExactElements("targetnode"))
+			Expect(pod.Spec.Tolerations).To(ContainElement(HaveField("Key", corev1.TaintNodeUnschedulable)))
+		})
+
+		It("passes once the pod is ready", func(ctx SpecContext) {
+			_, err := smokeTest.Check(params)
+			Expect(err).To(Succeed())
+			result, err := smokeTest.Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeFalse())
+
+			setStatus(ctx, corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+				},
+			})
+			result, err = smokeTest.Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeTrue())
+		})
+
+		It("passes once the pod succeeded", func(ctx SpecContext) {
+			_, err := smokeTest.Check(params)
+			Expect(err).To(Succeed())
+			setStatus(ctx, corev1.PodStatus{Phase: corev1.PodSucceeded})
+			result, err := smokeTest.Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeTrue())
+		})
+
+		It("deletes a failed pod", func(ctx SpecContext) {
+			_, err := smokeTest.Check(params)
+			Expect(err).To(Succeed())
+			setStatus(ctx, corev1.PodStatus{Phase: corev1.PodFailed})
+			result, err := smokeTest.Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeFalse())
+			err = k8sClient.Get(ctx, smokeTest.Key(&params), &corev1.Pod{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("deletes pods of previous transitions", func(ctx SpecContext) {
+			_, err := smokeTest.Check(params)
+			Expect(err).To(Succeed())
+			staleKey := smokeTest.Key(&params)
+
+			params.LastTransition = params.LastTransition.Add(time.Hour)
+			_, err = smokeTest.Check(params)
+			Expect(err).To(Succeed())
+
+			err = k8sClient.Get(ctx, staleKey, &corev1.Pod{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			Expect(k8sClient.Get(ctx, smokeTest.Key(&params), &corev1.Pod{})).To(Succeed())
+		})
+
+		It("removes the pod on transition", func(ctx SpecContext) {
+			_, err := smokeTest.Check(params)
+			Expect(err).To(Succeed())
+			Expect(smokeTest.OnTransition(params)).To(Succeed())
+			err = k8sClient.Get(ctx, smokeTest.Key(&params), &corev1.Pod{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+})
@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sapcc/ucfgwrap"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sapcc/maintenance-controller/constants"
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+var validTaintEffects = []v1.TaintEffect{v1.TaintEffectNoSchedule, v1.TaintEffectPreferNoSchedule, v1.TaintEffectNoExecute}
+
+func parseTaintEffect(effect string, required bool) (v1.TaintEffect, error) {
+	if effect == "" && !required {
+		return "", nil
+	}
+	if !slices.Contains(validTaintEffects, v1.TaintEffect(effect)) {
+		return "", fmt.Errorf("invalid taint effect: %s", effect)
+	}
+	return v1.TaintEffect(effect), nil
+}
+
+// HasTaint is a check plugin that checks whether a node has a taint with a certain key,
+// optionally with a certain value and effect.
+type HasTaint struct {
+	Key    string
+	Value  string
+	Effect v1.TaintEffect
+}
+
+// New creates a new HasTaint instance with the given config.
+func (h *HasTaint) New(config *ucfgwrap.Config) (plugin.Checker, error) {
+	conf := struct {
+		Key    string `config:"key" validate:"required"`
+		Value  string `config:"value"`
+		Effect string `config:"effect"`
+	}{}
+	if err := config.Unpack(&conf); err != nil {
+		return nil, err
+	}
+	effect, err := parseTaintEffect(conf.Effect, false)
+	if err != nil {
+		return nil, err
+	}
+	return &HasTaint{Key: conf.Key, Value: conf.Value, Effect: effect}, nil
+}
+
+func (h *HasTaint) ID() string {
+	return "hasTaint"
+}
+
+// Check passes if any taint of the node matches the key and, if specified, the value and effect.
+func (h *HasTaint) Check(params plugin.Parameters) (plugin.CheckResult, error) {
+	for _, taint := range params.Node.Spec.Taints {
+		if taint.Key != h.Key {
+			continue
+		}
+		if h.Effect != "" && taint.Effect != h.Effect {
+			continue
+		}
+		if h.Value != "" && taint.Value != h.Value {
+			continue
+		}
+		return plugin.Passed(map[string]any{"taint": taint.ToString()}), nil
+	}
+	return plugin.FailedWithReason(fmt.Sprintf("no matching taint with key %s present", h.Key)), nil
+}
+
+func (h *HasTaint) OnTransition(params plugin.Parameters) error {
+	return nil
+}
+
+// AlterTaint is a trigger plugin, which can add, change or remove a taint.
+// Taints added by it are recorded in the constants.TaintsAnnotationKey annotation,
+// so only these are changed or removed later on.
+type AlterTaint struct {
+	Key string
+	// rendered as template before being applied
+	Value  string
+	Effect v1.TaintEffect
+	Remove bool
+}
+
+// New creates a new AlterTaint instance with the given config.
+func (a *AlterTaint) New(config *ucfgwrap.Config) (plugin.Trigger, error) {
+	conf := struct {
+		Key    string `config:"key" validate:"required"`
+		Value  string `config:"value"`
+		Effect string `config:"effect" validate:"required"`
+		Remove bool   `config:"remove"`
+	}{}
+	if err := config.Unpack(&conf); err != nil {
+		return nil, err
+	}
+	effect, err := parseTaintEffect(conf.Effect, true)
+	if err != nil {
+		return nil, err
+	}
+	return &AlterTaint{Key: conf.Key, Value: conf.Value, Effect: effect, Remove: conf.Remove}, nil
+}
+
+func (a *AlterTaint) ID() string {
+	return "alterTaint"
+}
+
+// Trigger adds or updates the taint with the configured key and effect or removes it, if remove is set.
+// Taints with the same key and effect, which have not been added by the controller, are left untouched.
+func (a *AlterTaint) Trigger(params plugin.Parameters) error {
+	node := params.Node
+	owned := ownedTaints(node)
+	ref := a.ref()
+	idx := slices.IndexFunc(node.Spec.Taints, func(taint v1.Taint) bool {
+		return taint.Key == a.Key && taint.Effect == a.Effect
+	})
+	isOwned := slices.Contains(owned, ref)
+	if a.Remove {
+		if idx == -1 {
+			setOwnedTaints(node, slices.DeleteFunc(owned, func(s string) bool { return s == ref }))
+			return nil
+		}
+		if !isOwned {
+			params.Log.Info("Not removing taint, which has not been added by the maintenance controller",
+				"node", node.Name, "taint", node.Spec.Taints[idx].ToString())
+			return nil
+		}
+		node.Spec.Taints = slices.Delete(node.Spec.Taints, idx, idx+1)
+		setOwnedTaints(node, slices.DeleteFunc(owned, func(s string) bool { return s == ref }))
+		return nil
+	}
+	value, err := plugin.RenderNotificationTemplate(a.Value, &params)
+	if err != nil {
+		return fmt.Errorf("failed to render taint value: %w", err)
+	}
+	taint := v1.Taint{Key: a.Key, Value: value, Effect: a.Effect}
+	if a.Effect == v1.TaintEffectNoExecute {
+		now := metav1.Now()
+		taint.TimeAdded = &now
+	}
+	if idx == -1 {
+		node.Spec.Taints = append(node.Spec.Taints, taint)
+		setOwnedTaints(node, append(owned, ref))
+		return nil
+	}
+	if !isOwned {
+		params.Log.Info("Not changing taint, which has not been added by the maintenance controller",
+			"node", node.Name, "taint", node.Spec.Taints[idx].ToString())
+		return nil
+	}
+	if node.Spec.Taints[idx].Value != value {
+		node.Spec.Taints[idx] = taint
+	}
+	return nil
+}
+
+// ref identifies the taint within the constants.TaintsAnnotationKey annotation.
+func (a *AlterTaint) ref() string {
+	return fmt.Sprintf("%s:%s", a.Key, a.Effect)
+}
+
+func ownedTaints(node *v1.Node) []string {
+	raw := node.Annotations[constants.TaintsAnnotationKey]
+	if raw == "" {
+		return []string{}
+	}
+	return strings.Split(raw, ",")
+}
+
+func setOwnedTaints(node *v1.Node, refs []string) {
+	if len(refs) == 0 {
+		delete(node.Annotations, constants.TaintsAnnotationKey)
+		return
+	}
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+	slices.Sort(refs)
+	node.Annotations[constants.TaintsAnnotationKey] = strings.Join(slices.Compact(refs), ",")
+}
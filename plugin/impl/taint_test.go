@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sapcc/ucfgwrap"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sapcc/maintenance-controller/constants"
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+var _ = Describe("The HasTaint plugin", func() {
+	It("can parse its config", func() {
+		configStr := "key: key\nvalue: value\neffect: NoSchedule"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+
+		var base HasTaint
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(plugin).To(Equal(&HasTaint{
+			Key:    "key",
+			Value:  "value",
+			Effect: corev1.TaintEffectNoSchedule,
+		}))
+	})
+
+	It("rejects invalid effects", func() {
+		config, err := ucfgwrap.FromYAML([]byte("key: key\neffect: NoWhatever"))
+		Expect(err).To(Succeed())
+		var base HasTaint
+		_, err = base.New(&config)
+		Expect(err).ToNot(Succeed())
+	})
+
+	Context("with taint key=value:NoSchedule", func() {
+		params := plugin.Parameters{
+			Node: &corev1.Node{
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{{Key: "key", Value: "value", Effect: corev1.TaintEffectNoSchedule}},
+				},
+			},
+			Log: GinkgoLogr,
+		}
+
+		It("matches the taint by key", func() {
+			result, err := (&HasTaint{Key: "key"}).Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeTrue())
+		})
+
+		It("matches the taint with correct value and effect", func() {
+			result, err := (&HasTaint{Key: "key", Value: "value", Effect: corev1.TaintEffectNoSchedule}).Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeTrue())
+		})
+
+		It("does not match the taint with wrong value", func() {
+			result, err := (&HasTaint{Key: "key", Value: "other"}).Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeFalse())
+		})
+
+		It("does not match the taint with wrong effect", func() {
+			result, err := (&HasTaint{Key: "key", Effect: corev1.TaintEffectNoExecute}).Check(params)
+			Expect(err).To(Succeed())
+			Expect(result.Passed).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("The AlterTaint plugin", func() {
+	It("can parse its config", func() {
+		configStr := "key: key\nvalue: value\neffect: NoExecute\nremove: true"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+
+		var base AlterTaint
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(plugin).To(Equal(&AlterTaint{
+			Key:    "key",
+			Value:  "value",
+			Effect: corev1.TaintEffectNoExecute,
+			Remove: true,
+		}))
+	})
+
+	It("requires an effect", func() {
+		config, err := ucfgwrap.FromYAML([]byte("key: key"))
+		Expect(err).To(Succeed())
+		var base AlterTaint
+		_, err = base.New(&config)
+		Expect(err).ToNot(Succeed())
+	})
+
+	var params plugin.Parameters
+
+	BeforeEach(func() {
+		params = plugin.Parameters{
+			Node: &corev1.Node{
+				ObjectMeta: v1.ObjectMeta{Name: "targetnode"},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{{Key: "foreign", Value: "value", Effect: corev1.TaintEffectNoSchedule}},
+				},
+			},
+			State: "in-maintenance",
+			Log:   GinkgoLogr,
+		}
+	})
+
+	It("adds a taint with a templated value and records it", func() {
+		alter := AlterTaint{Key: "maintenance", Value: "{{ .Node.Name }}-{{ .State }}", Effect: corev1.TaintEffectNoExecute}
+		Expect(alter.Trigger(params)).To(Succeed())
+		Expect(params.Node.Spec.Taints).To(HaveLen(2))
+		taint := params.Node.Spec.Taints[1]
+		Expect(taint.Key).To(Equal("maintenance"))
+		Expect(taint.Value).To(Equal("targetnode-in-maintenance"))
+		Expect(taint.TimeAdded).ToNot(BeNil())
+		Expect(params.Node.Annotations).To(HaveKeyWithValue(constants.TaintsAnnotationKey, "maintenance:NoExecute"))
+	})
+
+	It("changes the value of a taint it added", func() {
+		Expect((&AlterTaint{Key: "maintenance", Value: "a", Effect: corev1.TaintEffectNoSchedule}).Trigger(params)).To(Succeed())
+		Expect((&AlterTaint{Key: "maintenance", Value: "b", Effect: corev1.TaintEffectNoSchedule}).Trigger(params)).To(Succeed())
+		Expect(params.Node.Spec.Taints).To(HaveLen(2))
+		Expect(params.Node.Spec.Taints[1].Value).To(Equal("b"))
+	})
+
+	It("removes a taint it added", func() {
+		Expect((&AlterTaint{Key: "maintenance", Effect: corev1.TaintEffectNoSchedule}).Trigger(params)).To(Succeed())
+		Expect((&AlterTaint{Key: "maintenance", Effect: corev1.TaintEffectNoSchedule, Remove: true}).Trigger(params)).To(Succeed())
+		Expect(params.Node.Spec.Taints).To(HaveLen(1))
+		Expect(params.Node.Annotations).ToNot(HaveKey(constants.TaintsAnnotationKey))
+	})
+
+	It("does not change or remove taints it did not add", func() {
+		Expect((&AlterTaint{Key: "foreign", Value: "mine", Effect: corev1.TaintEffectNoSchedule}).Trigger(params)).To(Succeed())
+		Expect(params.Node.Spec.Taints[0].Value).To(Equal("value"))
+		Expect((&AlterTaint{Key: "foreign", Effect: corev1.TaintEffectNoSchedule, Remove: true}).Trigger(params)).To(Succeed())
+		Expect(params.Node.Spec.Taints).To(HaveLen(1))
+		Expect(params.Node.Annotations).ToNot(HaveKey(constants.TaintsAnnotationKey))
+	})
+})
@@ -19,20 +19,27 @@ const timeFormat = "15:04"
 const dayMonthFormat = "Jan 2"
 
 // TimeWindow is a check plugin that checks whether it is invoked on a certain weekday with a specified timewindow.
+// Weekdays, excluded days and the time window are evaluated in the configured time zone.
 type TimeWindow struct {
 	Start    time.Time
 	End      time.Time
 	Weekdays []time.Weekday
 	Exclude  []time.Time
+	// names of calendars, during which the check fails
+	Calendars []string
+	// time zone of the time window, nil refers to UTC
+	Location *time.Location
 }
 
 // New creates a new TimeWindow instance with the given config.
 func (tw *TimeWindow) New(config *ucfgwrap.Config) (plugin.Checker, error) {
 	conf := struct {
-		Start    string
-		End      string
-		Weekdays []string
-		Exclude  []string
+		Start     string
+		End       string
+		Weekdays  []string
+		Exclude   []string
+		Calendars []string
+		Timezone  string
 	}{}
 	err := config.Unpack(&conf)
 	if err != nil {
@@ -54,7 +61,11 @@ func (tw *TimeWindow) New(config *ucfgwrap.Config) (plugin.Checker, error) {
 	if start.After(end) {
 		return nil, fmt.Errorf("the end time '%v' should be after the start time '%v'", end, start)
 	}
-	timewindow := &TimeWindow{Start: start, End: end}
+	location, err := common.LoadTimezone(conf.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	timewindow := &TimeWindow{Start: start, End: end, Calendars: conf.Calendars, Location: location}
 	for _, weekdayStr := range conf.Weekdays {
 		weekday, err := common.WeekdayFromString(weekdayStr)
 		if err != nil {
@@ -78,10 +89,18 @@ func (tw *TimeWindow) ID() string {
 
 // Check checks whether the current time is within specified time window on allowed weekdays.
 func (tw *TimeWindow) Check(params plugin.Parameters) (plugin.CheckResult, error) {
-	return tw.checkInternal(time.Now().UTC()), nil
+	now := common.InLocation(time.Now(), tw.Location)
+	blackout, ok, err := params.Calendars.ActiveAt(params.Ctx, params.Client, tw.Calendars, now)
+	if err != nil {
+		return plugin.Failed(nil), err
+	}
+	if ok {
+		return blackoutResult(blackout), nil
+	}
+	return tw.checkInternal(now), nil
 }
 
-// checkInternal expects a time in UTC.
+// checkInternal expects a time in the location of the time window.
 func (tw *TimeWindow) checkInternal(current time.Time) plugin.CheckResult {
 	if !slices.Contains(tw.Weekdays, current.Weekday()) {
 		return plugin.FailedWithReason("current day of week forbidden")
@@ -102,6 +121,10 @@ func (tw *TimeWindow) checkInternal(current time.Time) plugin.CheckResult {
 	return plugin.CheckResult{Passed: compare.After(tw.Start) && compare.Before(tw.End)}
 }
 
+func (tw *TimeWindow) CalendarRefs() []string {
+	return tw.Calendars
+}
+
 func (tw *TimeWindow) OnTransition(params plugin.Parameters) error {
 	return nil
 }
@@ -4,11 +4,15 @@
 package impl
 
 import (
+	"context"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/sapcc/ucfgwrap"
+
+	"github.com/sapcc/maintenance-controller/common"
+	"github.com/sapcc/maintenance-controller/plugin"
 )
 
 var _ = Describe("The Timewindow plugin", func() {
@@ -37,6 +41,50 @@ var _ = Describe("The Timewindow plugin", func() {
 		Expect(exclude[0]).To(Equal(dayMonth))
 	})
 
+	It("fails during a period of a shared calendar", func() {
+		today := time.Now().UTC()
+		calendars, err := common.NewCalendars([]common.CalendarDefinition{{
+			Name: "freeze",
+			Ranges: []common.CalendarRange{{
+				Summary: "Freeze",
+				Start:   today.AddDate(0, 0, -1).Format("2006-01-02"),
+				End:     today.AddDate(0, 0, 1).Format("2006-01-02"),
+			}},
+		}})
+		Expect(err).To(Succeed())
+		start, err := time.Parse(timeFormat, "00:00")
+		Expect(err).To(Succeed())
+		end, err := time.Parse(timeFormat, "23:59")
+		Expect(err).To(Succeed())
+		timeWindow := TimeWindow{
+			Start:     start,
+			End:       end,
+			Weekdays:  []time.Weekday{today.Weekday()},
+			Calendars: []string{"freeze"},
+		}
+		result, err := timeWindow.Check(plugin.Parameters{Ctx: context.Background(), Calendars: calendars})
+		Expect(err).To(Succeed())
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Info).To(HaveKeyWithValue("calendar", "freeze"))
+		Expect(result.Info).To(HaveKeyWithValue("blackout", "Freeze"))
+	})
+
+	It("evaluates the window in its time zone", func() {
+		configStr := "weekdays: [mon]\nstart: \"10:30\"\nend: \"15:20\"\ntimezone: Europe/Berlin"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base TimeWindow
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		timeWindow, ok := plugin.(*TimeWindow)
+		Expect(ok).To(BeTrue())
+		Expect(timeWindow.Location.String()).To(Equal("Europe/Berlin"))
+		// 11:00 in Berlin
+		current := time.Date(2020, time.June, 29, 9, 0, 0, 0, time.UTC)
+		Expect(timeWindow.checkInternal(current.In(timeWindow.Location)).Passed).To(BeTrue())
+		Expect(timeWindow.checkInternal(current).Passed).To(BeFalse())
+	})
+
 	It("should fail creation if no weekdays are provided", func() {
 		configStr := "start: \"11:00\"\nend: \"19:30\""
 		config, err := ucfgwrap.FromYAML([]byte(configStr))
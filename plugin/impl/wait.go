@@ -13,15 +13,16 @@ import (
 	"github.com/sapcc/maintenance-controller/plugin"
 )
 
-const day time.Duration = 24 * time.Hour
-
 type Wait struct {
 	Duration time.Duration
+	// names of calendars, during which the time does not progress
+	Calendars []string
 }
 
 func (w *Wait) New(config *ucfgwrap.Config) (plugin.Checker, error) {
 	conf := struct {
-		Duration string `config:"duration" validate:"required"`
+		Duration  string   `config:"duration" validate:"required"`
+		Calendars []string `config:"calendars"`
 	}{}
 	if err := config.Unpack(&conf); err != nil {
 		return nil, err
@@ -30,7 +31,7 @@ func (w *Wait) New(config *ucfgwrap.Config) (plugin.Checker, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Wait{Duration: duration}, nil
+	return &Wait{Duration: duration, Calendars: conf.Calendars}, nil
 }
 
 func (w *Wait) ID() string {
@@ -38,11 +39,25 @@ func (w *Wait) ID() string {
 }
 
 func (w *Wait) Check(params plugin.Parameters) (plugin.CheckResult, error) {
-	if time.Since(params.LastTransition) > w.Duration {
-		return plugin.Passed(nil), nil
+	now := time.Now().UTC()
+	blackouts, err := params.Calendars.Periods(params.Ctx, params.Client, w.Calendars, params.LastTransition, now)
+	if err != nil {
+		return plugin.Failed(nil), err
 	}
-	remaining := w.Duration - time.Since(params.LastTransition)
-	return plugin.Failed(map[string]any{"remaining_seconds": remaining.Seconds()}), nil
+	return w.checkInternal(params.LastTransition, now, blackouts), nil
+}
+
+func (w *Wait) checkInternal(lastTransition, now time.Time, blackouts []common.CalendarPeriod) plugin.CheckResult {
+	since := now.Sub(lastTransition) - common.OverlapDuration(blackouts, lastTransition, now)
+	if since > w.Duration {
+		return plugin.Passed(nil)
+	}
+	remaining := w.Duration - since
+	return plugin.Failed(map[string]any{"remaining_seconds": remaining.Seconds()})
+}
+
+func (w *Wait) CalendarRefs() []string {
+	return w.Calendars
 }
 
 func (w *Wait) OnTransition(params plugin.Parameters) error {
@@ -52,12 +67,18 @@ func (w *Wait) OnTransition(params plugin.Parameters) error {
 type WaitExclude struct {
 	Duration time.Duration
 	Exclude  []time.Weekday
+	// names of calendars, during which the time does not progress
+	Calendars []string
+	// time zone, which determines weekdays and day boundaries, nil refers to UTC
+	Location *time.Location
 }
 
 func (we *WaitExclude) New(config *ucfgwrap.Config) (plugin.Checker, error) {
 	conf := struct {
-		Duration string   `config:"duration" validate:"required"`
-		Exclude  []string `config:"exclude" validate:"required"`
+		Duration  string   `config:"duration" validate:"required"`
+		Exclude   []string `config:"exclude" validate:"required"`
+		Calendars []string `config:"calendars"`
+		Timezone  string   `config:"timezone"`
 	}{}
 	if err := config.Unpack(&conf); err != nil {
 		return nil, err
@@ -66,6 +87,10 @@ func (we *WaitExclude) New(config *ucfgwrap.Config) (plugin.Checker, error) {
 	if err != nil {
 		return nil, err
 	}
+	location, err := common.LoadTimezone(conf.Timezone)
+	if err != nil {
+		return nil, err
+	}
 	weekdays := make([]time.Weekday, 0)
 	for _, weekdayStr := range conf.Exclude {
 		weekday, err := common.WeekdayFromString(weekdayStr)
@@ -74,7 +99,7 @@ func (we *WaitExclude) New(config *ucfgwrap.Config) (plugin.Checker, error) {
 		}
 		weekdays = append(weekdays, weekday)
 	}
-	return &WaitExclude{Duration: duration, Exclude: weekdays}, nil
+	return &WaitExclude{Duration: duration, Exclude: weekdays, Calendars: conf.Calendars, Location: location}, nil
 }
 
 func (we *WaitExclude) ID() string {
@@ -82,45 +107,54 @@ func (we *WaitExclude) ID() string {
 }
 
 func (we *WaitExclude) Check(params plugin.Parameters) (plugin.CheckResult, error) {
-	return we.checkInternal(&params, time.Now().UTC()), nil
+	now := time.Now().UTC()
+	blackouts, err := params.Calendars.Periods(params.Ctx, params.Client, we.Calendars, params.LastTransition, now)
+	if err != nil {
+		return plugin.Failed(nil), err
+	}
+	return we.checkInternal(&params, now, blackouts), nil
 }
 
-func (we *WaitExclude) checkInternal(params *plugin.Parameters, now time.Time) plugin.CheckResult {
-	timestamp := params.LastTransition
-	since := now.Sub(params.LastTransition)
+func (we *WaitExclude) checkInternal(params *plugin.Parameters, now time.Time,
+	blackouts []common.CalendarPeriod) plugin.CheckResult {
+
+	// weekdays and day boundaries are evaluated in the configured time zone
+	now = common.InLocation(now, we.Location)
+	lastTransition := common.InLocation(params.LastTransition, we.Location)
+	timestamp := lastTransition
+	since := now.Sub(lastTransition)
 	// "since" currently includes excluded days.
 	// So, we loop through each day between timestamp (included) and today (included)
-	// and subtract 24 hours if that weekday was excluded
+	// and subtract the length of that day if that weekday was excluded
 	for !timestamp.After(now) {
 		if !we.isExcluded(timestamp.Weekday()) {
 			// not excluded => check the next day
-			timestamp = timestamp.Add(day)
+			timestamp = timestamp.AddDate(0, 0, 1)
 			continue
 		}
-		sub := day
-		// We can only remove the full 24 hours, if the full day can be considered
-		// as excluded. That does not hold for "params.LastTransition" and today.
+		// due to daylight saving time a day does not necessarily last 24 hours
+		sub := common.EndOfDay(timestamp).Sub(common.StartOfDay(timestamp))
+		// We can only remove the full day, if the full day can be considered
+		// as excluded. That does not hold for "lastTransition" and today.
 		// To make matters worse, both can be the same day.
-		if isSameDay(timestamp, params.LastTransition) {
-			// Day is the same as params.LastTransition so only the time from
-			// params.LastTransition to 00:00:00 can be subtracted.
-			// So if params.LastTransition and now are on the same day
+		if isSameDay(timestamp, lastTransition) {
+			// Day is the same as lastTransition so only the time from
+			// lastTransition to 00:00:00 can be subtracted.
+			// So if lastTransition and now are on the same day
 			// sub will be greater then since => sub becomes negative.
 			// In the end we compare against a positive duration, so this is fine.
-			hour, minute, sec := params.LastTransition.Clock()
-			sub = day - time.Duration(hour)*time.Hour - time.Duration(minute)*time.Minute - time.Duration(sec)*time.Second
+			sub = common.EndOfDay(lastTransition).Sub(lastTransition)
 		}
 		// subtract since and move to the next day
 		since -= sub
-		timestamp = timestamp.Add(day)
+		timestamp = timestamp.AddDate(0, 0, 1)
 	}
 	// if now is an excluded day and we have not accounted for it already,
 	// the time from 00:00:00 to now has to be subtracted.
-	if !isSameDay(params.LastTransition, now) && we.isExcluded(now.Weekday()) {
-		hour, minute, sec := now.Clock()
-		sub := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(sec)*time.Second
-		since -= sub
+	if !isSameDay(lastTransition, now) && we.isExcluded(now.Weekday()) {
+		since -= now.Sub(common.StartOfDay(now))
 	}
+	since -= common.OverlapDuration(we.withoutExcludedDays(blackouts), lastTransition, now)
 	if since > we.Duration {
 		return plugin.Passed(nil)
 	}
@@ -128,6 +162,30 @@ func (we *WaitExclude) checkInternal(params *plugin.Parameters, now time.Time) p
 	return plugin.Failed(map[string]any{"remaining_seconds": remaining.Seconds()})
 }
 
+// withoutExcludedDays splits the periods at midnight and drops the parts on excluded weekdays,
+// as the time on these days is already not accounted.
+func (we *WaitExclude) withoutExcludedDays(periods []common.CalendarPeriod) []common.CalendarPeriod {
+	result := make([]common.CalendarPeriod, 0)
+	for _, period := range periods {
+		start := common.InLocation(period.Start, we.Location)
+		for start.Before(period.End) {
+			end := common.EndOfDay(start)
+			if end.After(period.End) {
+				end = period.End
+			}
+			if !we.isExcluded(start.Weekday()) {
+				result = append(result, common.CalendarPeriod{Start: start, End: end})
+			}
+			start = end
+		}
+	}
+	return result
+}
+
+func (we *WaitExclude) CalendarRefs() []string {
+	return we.Calendars
+}
+
 func (we *WaitExclude) isExcluded(weekday time.Weekday) bool {
 	return slices.Contains(we.Exclude, weekday)
 }
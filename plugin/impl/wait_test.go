@@ -10,6 +10,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/sapcc/ucfgwrap"
 
+	"github.com/sapcc/maintenance-controller/common"
 	"github.com/sapcc/maintenance-controller/plugin"
 )
 
@@ -41,6 +42,30 @@ var _ = Describe("The wait plugin", func() {
 		Expect(err).To(Succeed())
 		Expect(result.Passed).To(BeFalse())
 	})
+
+	It("parses calendar references", func() {
+		base := Wait{}
+		config, err := ucfgwrap.FromYAML([]byte("duration: 1h\ncalendars: [holidays]"))
+		Expect(err).To(Succeed())
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(plugin).To(Equal(&Wait{Duration: time.Hour, Calendars: []string{"holidays"}}))
+	})
+
+	It("does not progress during blackouts", func() {
+		wait := Wait{Duration: 90 * time.Minute}
+		lastTransition := time.Date(2026, time.October, 19, 10, 0, 0, 0, time.UTC)
+		now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+		Expect(wait.checkInternal(lastTransition, now, nil).Passed).To(BeTrue())
+		blackouts := []common.CalendarPeriod{{Start: lastTransition.Add(30 * time.Minute), End: now.Add(-30 * time.Minute)}}
+		Expect(wait.checkInternal(lastTransition, now, blackouts).Passed).To(BeFalse())
+	})
+
+	It("fails on undefined calendars", func() {
+		wait := Wait{Duration: time.Minute, Calendars: []string{"missing"}}
+		_, err := wait.Check(plugin.Parameters{LastTransition: time.Now().UTC().Add(-time.Hour)})
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 var _ = Describe("The waitExclude plugin", func() {
@@ -54,11 +79,24 @@ var _ = Describe("The waitExclude plugin", func() {
 		Expect(plugin).To(Equal(&WaitExclude{
 			Duration: 17 * time.Minute,
 			Exclude:  []time.Weekday{time.Tuesday},
+			Location: time.UTC,
 		}))
 	})
 
+	It("can parse its time zone", func() {
+		base := WaitExclude{}
+		configStr := "duration: 17m\nexclude: [\"tue\"]\ntimezone: Asia/Tokyo"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		waitExclude, ok := plugin.(*WaitExclude)
+		Expect(ok).To(BeTrue())
+		Expect(waitExclude.Location.String()).To(Equal("Asia/Tokyo"))
+	})
+
 	checkWaitExclude := func(we *WaitExclude, transition, now time.Time) bool {
-		return we.checkInternal(&plugin.Parameters{LastTransition: transition}, now).Passed
+		return we.checkInternal(&plugin.Parameters{LastTransition: transition}, now, nil).Passed
 	}
 
 	Context("with a duration of one hour and no exclusions", func() {
@@ -121,6 +159,44 @@ var _ = Describe("The waitExclude plugin", func() {
 		})
 	})
 
+	Context("with a duration of 15 hours, an exclusion on monday and a blackout until tuesday noon", func() {
+		It("does not account excluded days twice", func() {
+			we := WaitExclude{Duration: 15 * time.Hour, Exclude: []time.Weekday{time.Monday}}
+			blackouts := []common.CalendarPeriod{{
+				Start: time.Date(2022, time.March, 7, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2022, time.March, 8, 12, 0, 0, 0, time.UTC),
+			}}
+			lastTransition := time.Date(2022, time.March, 6, 12, 00, 00, 00, time.UTC)
+			// 54 hours minus 24 excluded hours on monday minus 12 hours of blackout on tuesday
+			now := time.Date(2022, time.March, 8, 18, 00, 00, 00, time.UTC)
+			result := we.checkInternal(&plugin.Parameters{LastTransition: lastTransition}, now, blackouts)
+			Expect(result.Passed).To(BeTrue())
+			// 50 hours minus 24 excluded hours on monday minus 12 hours of blackout on tuesday
+			now = time.Date(2022, time.March, 8, 14, 00, 00, 00, time.UTC)
+			result = we.checkInternal(&plugin.Parameters{LastTransition: lastTransition}, now, blackouts)
+			Expect(result.Passed).To(BeFalse())
+		})
+	})
+
+	Context("with a duration of 5 hours and an exclusion on sunday", func() {
+		// saturday 20:00 in UTC, sunday 05:00 in Tokyo
+		lastTransition := time.Date(2022, time.March, 5, 20, 00, 00, 00, time.UTC)
+		// monday 00:00 in UTC, monday 09:00 in Tokyo
+		now := time.Date(2022, time.March, 7, 0, 00, 00, 00, time.UTC)
+
+		It("fails in UTC", func() {
+			we := WaitExclude{Duration: 5 * time.Hour, Exclude: []time.Weekday{time.Sunday}}
+			Expect(checkWaitExclude(&we, lastTransition, now)).To(BeFalse())
+		})
+
+		It("passes in Tokyo", func() {
+			tokyo, err := time.LoadLocation("Asia/Tokyo")
+			Expect(err).To(Succeed())
+			we := WaitExclude{Duration: 5 * time.Hour, Exclude: []time.Weekday{time.Sunday}, Location: tokyo}
+			Expect(checkWaitExclude(&we, lastTransition, now)).To(BeTrue())
+		})
+	})
+
 	Context("with a duration of 1 second and exclusions on monday and wednesday", func() {
 		It("passes on sunday after a second", func() {
 			we := WaitExclude{Duration: 1 * time.Second, Exclude: []time.Weekday{time.Monday, time.Wednesday}}
@@ -0,0 +1,232 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sapcc/ucfgwrap"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+var defaultRetryStatusCodes = []int{http.StatusTooManyRequests, http.StatusBadGateway,
+	http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+// Webhook is a trigger plugin, which sends a templated http request.
+type Webhook struct {
+	URL     string
+	Method  string
+	Body    string
+	Headers map[string]string
+	// status codes considered successful, any 2xx code if empty
+	ExpectedStatusCodes []int
+	// status codes, which are retried and finally reported as plugin.RetryError
+	RetryStatusCodes []int
+	Retries          int
+	Backoff          time.Duration
+	Timeout          time.Duration
+	// header which carries the idempotency key, no header is sent if empty
+	IdempotencyHeader string
+	Auth              SecretAuth
+}
+
+// New creates a new Webhook instance with the given config.
+func (w *Webhook) New(config *ucfgwrap.Config) (plugin.Trigger, error) {
+	conf := struct {
+		URL                 string            `config:"url" validate:"required"`
+		Method              string            `config:"method"`
+		Body                string            `config:"body"`
+		Headers             map[string]string `config:"headers"`
+		ExpectedStatusCodes []int             `config:"expectedStatusCodes"`
+		RetryStatusCodes    []int             `config:"retryStatusCodes"`
+		Retries             int               `config:"retries" validate:"min=0"`
+		Backoff             time.Duration     `config:"backoff"`
+		Timeout             time.Duration     `config:"timeout"`
+		IdempotencyHeader   string            `config:"idempotencyHeader"`
+		Auth                authConfig        `config:"auth"`
+	}{
+		Method:            http.MethodPost,
+		RetryStatusCodes:  defaultRetryStatusCodes,
+		Backoff:           time.Second,
+		Timeout:           10 * time.Second,
+		IdempotencyHeader: "Idempotency-Key",
+	}
+	if err := config.Unpack(&conf); err != nil {
+		return nil, err
+	}
+	auth, err := conf.Auth.toSecretAuth()
+	if err != nil {
+		return nil, err
+	}
+	if auth.Type == MTLSAuth {
+		return nil, errors.New("the webhook plugins do not support mtls auth")
+	}
+	return &Webhook{
+		URL:                 conf.URL,
+		Method:              strings.ToUpper(conf.Method),
+		Body:                conf.Body,
+		Headers:             conf.Headers,
+		ExpectedStatusCodes: conf.ExpectedStatusCodes,
+		RetryStatusCodes:    conf.RetryStatusCodes,
+		Retries:             conf.Retries,
+		Backoff:             conf.Backoff,
+		Timeout:             conf.Timeout,
+		IdempotencyHeader:   conf.IdempotencyHeader,
+		Auth:                auth,
+	}, nil
+}
+
+func (w *Webhook) ID() string {
+	return "webhook"
+}
+
+// Trigger renders and sends the configured request.
+// Requests are retried with an exponential backoff on transport errors and on status codes within RetryStatusCodes.
+// If the retries are exhausted on such a status code a plugin.RetryError is returned,
+// so the next reconciliation tries again. Any other unexpected status code is a hard failure.
+func (w *Webhook) Trigger(params plugin.Parameters) error {
+	url, err := plugin.RenderNotificationTemplate(w.URL, &params)
+	if err != nil {
+		return fmt.Errorf("failed to render webhook url: %w", err)
+	}
+	body, err := plugin.RenderNotificationTemplate(w.Body, &params)
+	if err != nil {
+		return fmt.Errorf("failed to render webhook body: %w", err)
+	}
+	headers := make(map[string]string, len(w.Headers))
+	for key, value := range w.Headers {
+		rendered, err := plugin.RenderNotificationTemplate(value, &params)
+		if err != nil {
+			return fmt.Errorf("failed to render webhook header %s: %w", key, err)
+		}
+		headers[key] = rendered
+	}
+
+	var lastStatus int
+	var lastErr error
+	backoff := wait.Backoff{
+		Duration: w.Backoff,
+		Factor:   2,
+		Steps:    w.Retries + 1,
+	}
+	err = wait.ExponentialBackoffWithContext(params.Ctx, backoff, func(ctx context.Context) (bool, error) {
+		lastStatus, lastErr = w.send(ctx, &params, url, body, headers)
+		if lastErr != nil {
+			params.Log.Info("webhook request failed", "url", url, "error", lastErr)
+			return false, nil
+		}
+		if w.isExpected(lastStatus) {
+			return true, nil
+		}
+		if slices.Contains(w.RetryStatusCodes, lastStatus) {
+			params.Log.Info("webhook returned retryable status code", "url", url, "status", lastStatus)
+			return false, nil
+		}
+		return false, fmt.Errorf("webhook %s returned unexpected status code %d", url, lastStatus)
+	})
+	if err == nil {
+		return nil
+	}
+	if !wait.Interrupted(err) {
+		return err
+	}
+	if lastErr != nil {
+		return fmt.Errorf("failed to send webhook request to %s: %w", url, lastErr)
+	}
+	return &plugin.RetryError{Message: fmt.Sprintf("webhook %s returned status code %d", url, lastStatus)}
+}
+
+func (w *Webhook) send(ctx context.Context, params *plugin.Parameters, url, body string,
+	headers map[string]string) (int, error) {
+
+	ctx, cancel := context.WithTimeout(ctx, w.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, w.Method, url, strings.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if w.IdempotencyHeader != "" {
+		req.Header.Set(w.IdempotencyHeader, IdempotencyKey(params))
+	}
+	if err := w.Auth.Apply(params, req); err != nil {
+		return 0, err
+	}
+	rsp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer rsp.Body.Close()
+	// drain the body, so the connection can be reused
+	_, err = io.Copy(io.Discard, rsp.Body)
+	if err != nil {
+		return 0, err
+	}
+	return rsp.StatusCode, nil
+}
+
+func (w *Webhook) isExpected(status int) bool {
+	if len(w.ExpectedStatusCodes) == 0 {
+		return status >= 200 && status < 300
+	}
+	return slices.Contains(w.ExpectedStatusCodes, status)
+}
+
+// IdempotencyKey derives a key from the node, the profile and the time of the last transition.
+// The key stays the same while a node remains in a state, so repeated invocations of the
+// same trigger can be deduplicated by the receiver.
+func IdempotencyKey(params *plugin.Parameters) string {
+	data := fmt.Sprintf("%s/%s/%s", params.Node.Name, params.Profile, params.LastTransition.UTC().Format(time.RFC3339Nano))
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:])
+}
+
+// WebhookNotifier is a notification plugin, which sends a templated http request.
+// It supports the same options as the webhook trigger, but sends no idempotency key unless
+// a header is configured explicitly, as repeated notifications within a state would share the key.
+type WebhookNotifier struct {
+	Webhook
+}
+
+// New creates a new WebhookNotifier instance with the given config.
+func (wn *WebhookNotifier) New(config *ucfgwrap.Config) (plugin.Notifier, error) {
+	var base Webhook
+	trigger, err := base.New(config)
+	if err != nil {
+		return nil, err
+	}
+	explicit := struct {
+		IdempotencyHeader *string `config:"idempotencyHeader"`
+	}{}
+	if err := config.Unpack(&explicit); err != nil {
+		return nil, err
+	}
+	notifier := WebhookNotifier{Webhook: *trigger.(*Webhook)} //nolint:forcetypeassert
+	if explicit.IdempotencyHeader == nil {
+		notifier.IdempotencyHeader = ""
+	}
+	return &notifier, nil
+}
+
+func (wn *WebhookNotifier) ID() string {
+	return "webhook"
+}
+
+// Notify renders and sends the configured request.
+func (wn *WebhookNotifier) Notify(params plugin.Parameters) error {
+	return wn.Trigger(params)
+}
@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sapcc/ucfgwrap"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+var _ = Describe("The webhook plugin", func() {
+
+	It("can parse its configuration", func() {
+		configStr := "url: http://example.com/{{ .Node.Name }}\n" +
+			"method: put\n" +
+			"body: '{\"node\":\"{{ .Node.Name }}\"}'\n" +
+			"headers:\n  Content-Type: application/json\n" +
+			"expectedStatusCodes: [200, 201]\n" +
+			"retries: 3\n" +
+			"backoff: 2s\n" +
+			"auth:\n  type: bearer\n  secret:\n    name: creds\n    namespace: default\n"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base Webhook
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(plugin).To(Equal(&Webhook{
+			URL:                 "http://example.com/{{ .Node.Name }}",
+			Method:              http.MethodPut,
+			Body:                "{\"node\":\"{{ .Node.Name }}\"}",
+			Headers:             map[string]string{"Content-Type": "application/json"},
+			ExpectedStatusCodes: []int{200, 201},
+			RetryStatusCodes:    defaultRetryStatusCodes,
+			Retries:             3,
+			Backoff:             2 * time.Second,
+			Timeout:             10 * time.Second,
+			IdempotencyHeader:   "Idempotency-Key",
+			Auth: SecretAuth{
+				Type:   BearerAuth,
+				Secret: client.ObjectKey{Name: "creds", Namespace: "default"},
+			},
+		}))
+	})
+
+	It("rejects unknown auth types", func() {
+		config, err := ucfgwrap.FromYAML([]byte("url: http://example.com\nauth:\n  type: magic"))
+		Expect(err).To(Succeed())
+		var base Webhook
+		_, err = base.New(&config)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects mtls auth", func() {
+		config, err := ucfgwrap.FromYAML([]byte("url: http://example.com\nauth:\n  type: mtls\n" +
+			"  secret:\n    name: certs\n    namespace: default"))
+		Expect(err).To(Succeed())
+		var base Webhook
+		_, err = base.New(&config)
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("with a mock endpoint", func() {
+		var (
+			server    *httptest.Server
+			status    int
+			requests  chan *http.Request
+			bodies    chan string
+			k8sClient client.Client
+			params    plugin.Parameters
+		)
+
+		BeforeEach(func() {
+			status = http.StatusOK
+			requests = make(chan *http.Request, 10)
+			bodies = make(chan string, 10)
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				body, err := io.ReadAll(r.Body)
+				Expect(err).To(Succeed())
+				requests <- r
+				bodies <- string(body)
+				w.WriteHeader(status)
+			}))
+			k8sClient = fake.NewClientBuilder().WithObjects(&corev1.Secret{
+				ObjectMeta: v1.ObjectMeta{Name: "creds", Namespace: "default"},
+				Data:       map[string][]byte{"username": []byte("user"), "password": []byte("pass")},
+			}).Build()
+			params = plugin.Parameters{
+				Client:         k8sClient,
+				Ctx:            context.Background(),
+				Log:            GinkgoLogr,
+				Node:           &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "targetnode"}},
+				Profile:        "someprofile",
+				LastTransition: time.Date(2026, time.March, 3, 10, 0, 0, 0, time.UTC),
+			}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		makeWebhook := func() Webhook {
+			return Webhook{
+				URL:               server.URL + "/nodes/{{ .Node.Name }}",
+				Method:            http.MethodPost,
+				Body:              "{{ .Profile }}",
+				Headers:           map[string]string{"X-Node": "{{ .Node.Name }}"},
+				RetryStatusCodes:  defaultRetryStatusCodes,
+				Backoff:           time.Millisecond,
+				Timeout:           time.Second,
+				IdempotencyHeader: "Idempotency-Key",
+				Auth: SecretAuth{
+					Type:   BasicAuth,
+					Secret: client.ObjectKey{Name: "creds", Namespace: "default"},
+				},
+			}
+		}
+
+		It("sends the rendered request", func() {
+			webhook := makeWebhook()
+			Expect(webhook.Trigger(params)).To(Succeed())
+			var req *http.Request
+			Eventually(requests).Should(Receive(&req))
+			Expect(req.URL.Path).To(Equal("/nodes/targetnode"))
+			Expect(req.Header.Get("X-Node")).To(Equal("targetnode"))
+			Expect(req.Header.Get("Idempotency-Key")).To(Equal(IdempotencyKey(&params)))
+			user, pass, ok := req.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(user).To(Equal("user"))
+			Expect(pass).To(Equal("pass"))
+			Expect(<-bodies).To(Equal("someprofile"))
+		})
+
+		It("reuses fetched secrets", func() {
+			gets := 0
+			params.Client = fake.NewClientBuilder().WithObjects(&corev1.Secret{
+				ObjectMeta: v1.ObjectMeta{Name: "reused-creds", Namespace: "default"},
+				Data:       map[string][]byte{"username": []byte("user"), "password": []byte("pass")},
+			}).WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					gets++
+					return c.Get(ctx, key, obj, opts...)
+				},
+			}).Build()
+			webhook := makeWebhook()
+			webhook.Auth.Secret.Name = "reused-creds"
+			Expect(webhook.Trigger(params)).To(Succeed())
+			Expect(webhook.Trigger(params)).To(Succeed())
+			Expect(requests).To(HaveLen(2))
+			Expect(gets).To(Equal(1))
+		})
+
+		It("returns a RetryError after exhausting retries on retryable status codes", func() {
+			status = http.StatusServiceUnavailable
+			webhook := makeWebhook()
+			webhook.Retries = 2
+			err := webhook.Trigger(params)
+			var retryErr *plugin.RetryError
+			Expect(errors.As(err, &retryErr)).To(BeTrue())
+			Expect(requests).To(HaveLen(3))
+		})
+
+		It("fails hard on unexpected status codes", func() {
+			status = http.StatusBadRequest
+			webhook := makeWebhook()
+			webhook.Retries = 2
+			err := webhook.Trigger(params)
+			Expect(err).To(HaveOccurred())
+			var retryErr *plugin.RetryError
+			Expect(errors.As(err, &retryErr)).To(BeFalse())
+			Expect(requests).To(HaveLen(1))
+		})
+
+		It("honors the expected status codes", func() {
+			status = http.StatusAccepted
+			webhook := makeWebhook()
+			webhook.ExpectedStatusCodes = []int{http.StatusOK}
+			Expect(webhook.Trigger(params)).ToNot(Succeed())
+			webhook.ExpectedStatusCodes = []int{http.StatusAccepted}
+			Expect(webhook.Trigger(params)).To(Succeed())
+		})
+	})
+
+	It("derives stable idempotency keys", func() {
+		params := plugin.Parameters{
+			Node:           &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "node"}},
+			Profile:        "profile",
+			LastTransition: time.Date(2026, time.March, 3, 10, 0, 0, 0, time.UTC),
+		}
+		key := IdempotencyKey(&params)
+		Expect(IdempotencyKey(&params)).To(Equal(key))
+		params.LastTransition = params.LastTransition.Add(time.Second)
+		Expect(IdempotencyKey(&params)).ToNot(Equal(key))
+	})
+
+})
+
+var _ = Describe("The webhook notifier", func() {
+
+	It("sends no idempotency key by default", func() {
+		config, err := ucfgwrap.FromYAML([]byte("url: http://example.com\nbody: '{\"text\": {{ .State | json }}}'"))
+		Expect(err).To(Succeed())
+		var base WebhookNotifier
+		notifier, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(notifier).To(HaveField("Body", "{\"text\": {{ .State | json }}}"))
+		Expect(notifier).To(HaveField("IdempotencyHeader", ""))
+
+		config, err = ucfgwrap.FromYAML([]byte("url: http://example.com\nidempotencyHeader: X-Key"))
+		Expect(err).To(Succeed())
+		notifier, err = base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(notifier).To(HaveField("IdempotencyHeader", "X-Key"))
+	})
+
+	It("sends the rendered notification", func() {
+		bodies := make(chan string, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+			body, err := io.ReadAll(r.Body)
+			Expect(err).To(Succeed())
+			bodies <- string(body)
+		}))
+		defer server.Close()
+		notifier := WebhookNotifier{Webhook: Webhook{
+			URL:     server.URL,
+			Method:  http.MethodPost,
+			Body:    `{"text": {{ printf "%s is %s" .Node.Name .State | json }}}`,
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Timeout: time.Second,
+		}}
+		Expect(notifier.Notify(plugin.Parameters{
+			Ctx:   context.Background(),
+			Log:   GinkgoLogr,
+			Node:  &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "node01"}},
+			State: "in-maintenance",
+		})).To(Succeed())
+		Expect(<-bodies).To(Equal(`{"text": "node01 is in-maintenance"}`))
+	})
+
+})
@@ -0,0 +1,390 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sapcc/ucfgwrap"
+	"github.com/slack-go/slack"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/sapcc/maintenance-controller/common"
+	"github.com/sapcc/maintenance-controller/constants"
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+const (
+	slackContactPrefix string = "slack:"
+	mailContactPrefix  string = "mailto:"
+	// the note of events.k8s.io events is limited to 1 KiB
+	maxEventNoteLength int = 1024
+
+	defaultWorkloadOwnersMessage string = `Node {{ .Node.Name }} enters maintenance of profile {{ .Profile }}.
+The following workloads in namespace {{ .Namespace }} will be evicted:
+{{ range .Workloads }}- {{ .Kind }} {{ .Name }}: {{ .Pods | join ", " }}
+{{ end }}`
+)
+
+// WorkloadOwners is a notification plugin, which notifies the owners of the pods on a node,
+// that their workloads are going to be evicted. Pods are grouped by namespace and the contacts
+// of each namespace are read from an annotation on the namespace.
+type WorkloadOwners struct {
+	// annotation on namespaces holding the comma-separated contacts
+	Annotation string
+	// rendered as template using WorkloadOwnersData
+	Message string
+	// selects the pods, which are considered to be evicted
+	PodFilter common.PodFilter
+	// required to notify slack contacts
+	Slack *WorkloadOwnersSlack
+	// required to notify mail contacts, the recipients are taken from the contacts
+	Mail *Mail
+	// required to notify webhook contacts
+	Webhook *WorkloadOwnersWebhook
+	testURL string
+}
+
+// WorkloadOwnersSlack configures the slack app used to post to the channels of owners.
+type WorkloadOwnersSlack struct {
+	Token string
+}
+
+// WorkloadOwnersWebhook restricts the urls owners can be notified at, as namespace annotations
+// would otherwise allow sending requests to arbitrary endpoints reachable by the controller.
+type WorkloadOwnersWebhook struct {
+	AllowedPrefixes []string
+	Timeout         time.Duration
+}
+
+// Workload is a group of pods managed by the same controller.
+type Workload struct {
+	Kind string
+	Name string
+	Pods []string
+}
+
+// WorkloadOwnersData is passed to the message template.
+type WorkloadOwnersData struct {
+	Node      *corev1.Node
+	State     string
+	Profile   string
+	Namespace string
+	Workloads []Workload
+}
+
+// New creates a new WorkloadOwners instance with the given config.
+func (wo *WorkloadOwners) New(config *ucfgwrap.Config) (plugin.Notifier, error) {
+	conf := struct {
+		Annotation string           `config:"annotation"`
+		Message    string           `config:"message"`
+		PodFilter  common.PodFilter `config:"podFilter"`
+		Slack      struct {
+			Token string `config:"token"`
+		} `config:"slack"`
+		Mail struct {
+			Auth     bool   `config:"auth"`
+			Subject  string `config:"subject"`
+			Address  string `config:"address"`
+			From     string `config:"from"`
+			Identity string `config:"identity"`
+			User     string `config:"user"`
+			Password string `config:"password"` // #nosec G117 - intentional passing of secret credential
+		} `config:"mail"`
+		Webhook struct {
+			AllowedPrefixes []string      `config:"allowedPrefixes"`
+			Timeout         time.Duration `config:"timeout"`
+		} `config:"webhook"`
+	}{Annotation: constants.ContactAnnotationKey, Message: defaultWorkloadOwnersMessage}
+	conf.Mail.Subject = "Maintenance of node affects your workloads"
+	conf.Webhook.Timeout = 10 * time.Second
+	if err := config.Unpack(&conf); err != nil {
+		return nil, err
+	}
+	owners := &WorkloadOwners{
+		Annotation: conf.Annotation,
+		Message:    conf.Message,
+		PodFilter:  conf.PodFilter,
+	}
+	if conf.Slack.Token != "" {
+		owners.Slack = &WorkloadOwnersSlack{Token: conf.Slack.Token}
+	}
+	if conf.Mail.Address != "" && conf.Mail.From != "" {
+		owners.Mail = &Mail{
+			Auth:     conf.Mail.Auth,
+			Subject:  conf.Mail.Subject,
+			Address:  conf.Mail.Address,
+			From:     conf.Mail.From,
+			Identity: conf.Mail.Identity,
+			User:     conf.Mail.User,
+			Password: conf.Mail.Password,
+		}
+	}
+	for _, prefix := range conf.Webhook.AllowedPrefixes {
+		parsed, err := url.Parse(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed webhook prefix %s: %w", prefix, err)
+		}
+		if parsed.Scheme == "" || parsed.Host == "" || parsed.User != nil {
+			return nil, fmt.Errorf("allowed webhook prefix %s needs a scheme and host without user info", prefix)
+		}
+	}
+	if len(conf.Webhook.AllowedPrefixes) > 0 {
+		owners.Webhook = &WorkloadOwnersWebhook{
+			AllowedPrefixes: conf.Webhook.AllowedPrefixes,
+			Timeout:         conf.Webhook.Timeout,
+		}
+	}
+	if owners.Slack == nil && owners.Mail == nil && owners.Webhook == nil {
+		return nil, errors.New("workloadOwners needs at least one of a slack token, a mail address and from " +
+			"or allowed webhook prefixes")
+	}
+	return owners, nil
+}
+
+func (wo *WorkloadOwners) ID() string {
+	return "workloadOwners"
+}
+
+func (wo *WorkloadOwners) SetTestURL(testURL string) {
+	wo.testURL = testURL
+}
+
+// Notify sends one message to the contacts of each namespace with pods on the node and records an event
+// in each of these namespaces. Namespaces without contacts only get the event.
+// Contacts, which can not be notified with the configuration of the instance, are logged and skipped.
+// Contacts are maintained by the owners of the namespaces, so failing to deliver a message is logged and
+// recorded as event on the node instead of returning an error, which would block the maintenance of the node.
+func (wo *WorkloadOwners) Notify(params plugin.Parameters) error {
+	pods, err := common.GetPodsForDrain(params.Ctx, params.Client, params.Node.Name)
+	if err != nil {
+		return fmt.Errorf("failed to list pods of node %s: %w", params.Node.Name, err)
+	}
+	filtered, err := wo.PodFilter.Apply(pods)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for namespace, workloads := range groupWorkloads(filtered.Drain) {
+		if err := wo.notifyNamespace(&params, namespace, workloads); err != nil {
+			errs = append(errs, fmt.Errorf("failed to notify owners of namespace %s: %w", namespace, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (wo *WorkloadOwners) notifyNamespace(params *plugin.Parameters, namespace string, workloads []Workload) error {
+	wo.recordNamespaceEvent(params, namespace, workloads)
+	var ns corev1.Namespace
+	err := params.Client.Get(params.Ctx, types.NamespacedName{Name: namespace}, &ns)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	contacts := parseContacts(ns.Annotations[wo.Annotation])
+	if len(contacts) == 0 {
+		return nil
+	}
+	message, err := plugin.RenderTemplate(wo.Message, WorkloadOwnersData{
+		Node:      params.Node,
+		State:     params.State,
+		Profile:   params.Profile,
+		Namespace: namespace,
+		Workloads: workloads,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render message: %w", err)
+	}
+	var recipients []string
+	for _, contact := range contacts {
+		var err error
+		switch {
+		case strings.HasPrefix(contact, mailContactPrefix) && wo.Mail != nil:
+			recipients = append(recipients, strings.TrimPrefix(contact, mailContactPrefix))
+			continue
+		case strings.HasPrefix(contact, slackContactPrefix) && wo.Slack != nil:
+			err = wo.sendSlack(params.Ctx, strings.TrimPrefix(contact, slackContactPrefix), message)
+		case wo.Webhook != nil && wo.Webhook.allows(contact):
+			err = wo.Webhook.send(params.Ctx, contact, message)
+		default:
+			params.Log.Info("Skipped contact, which can not be notified", "namespace", namespace, "contact", contact)
+			continue
+		}
+		if err != nil {
+			recordContactFailure(params, namespace, contact, err)
+			continue
+		}
+		recordTarget(params, wo.ID(), contact)
+	}
+	if len(recipients) > 0 {
+		routed := *wo.Mail
+		routed.To = recipients
+		if err := routed.send(message); err != nil {
+			recordContactFailure(params, namespace, strings.Join(recipients, ","), err)
+		} else {
+			recordTarget(params, wo.ID(), strings.Join(recipients, ","))
+		}
+	}
+	return nil
+}
+
+// recordContactFailure logs the failed delivery to a contact of a namespace and records it as event on the node.
+func recordContactFailure(params *plugin.Parameters, namespace, contact string, err error) {
+	params.Log.Error(err, "failed to notify workload owner", "node", params.Node.Name,
+		"namespace", namespace, "contact", contact)
+	if params.Recorder != nil {
+		params.Recorder.Eventf(params.Node, nil, corev1.EventTypeWarning, "NotificationFailed", "Notify",
+			"Failed to notify %s of namespace %s: %s", contact, namespace, err.Error())
+	}
+}
+
+// recordNamespaceEvent records an event regarding the first affected pod, as events are placed in the namespace
+// of the object they regard.
+func (wo *WorkloadOwners) recordNamespaceEvent(params *plugin.Parameters, namespace string, workloads []Workload) {
+	if params.Recorder == nil {
+		return
+	}
+	names := make([]string, 0, len(workloads))
+	for _, workload := range workloads {
+		names = append(names, workload.Kind+"/"+workload.Name)
+	}
+	note := fmt.Sprintf("Node %s enters maintenance of profile %s, workloads to be evicted: %s",
+		params.Node.Name, params.Profile, strings.Join(names, ", "))
+	if len(note) > maxEventNoteLength {
+		note = note[:maxEventNoteLength-3] + "..."
+	}
+	pod := &corev1.Pod{ObjectMeta: v1.ObjectMeta{Namespace: namespace, Name: workloads[0].Pods[0]}}
+	params.Recorder.Eventf(pod, params.Node, corev1.EventTypeWarning, "NodeMaintenance", "Notify", "%s", note)
+}
+
+func (wo *WorkloadOwners) sendSlack(ctx context.Context, channel, message string) error {
+	options := []slack.Option{}
+	if wo.testURL != "" {
+		options = append(options, slack.OptionAPIURL(wo.testURL))
+	}
+	api := slack.New(wo.Slack.Token, options...)
+	_, _, err := api.PostMessageContext(ctx, channel, slack.MsgOptionText(message, false))
+	return err
+}
+
+// allows checks whether the url has the scheme and host of an allowed prefix and its path starts with the path
+// of the prefix. Comparing the parsed urls prevents hosts like hooks.example.com.attacker.net
+// or user infos like hooks.example.com@attacker.net from passing.
+func (ww *WorkloadOwnersWebhook) allows(rawURL string) bool {
+	target, err := url.Parse(rawURL)
+	if err != nil || target.User != nil || target.Host == "" ||
+		slices.Contains(strings.Split(target.Path, "/"), "..") {
+		return false
+	}
+	return slices.ContainsFunc(ww.AllowedPrefixes, func(rawPrefix string) bool {
+		prefix, err := url.Parse(rawPrefix)
+		if err != nil {
+			return false
+		}
+		return strings.EqualFold(target.Scheme, prefix.Scheme) && strings.EqualFold(target.Host, prefix.Host) &&
+			strings.HasPrefix(target.Path, prefix.Path)
+	})
+}
+
+// webhookClient does not follow redirects, as their targets have not been checked against the allowed prefixes.
+var webhookClient = &http.Client{
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// send posts the message as json object with a text field, which is understood by most chat tools.
+func (ww *WorkloadOwnersWebhook) send(ctx context.Context, target, message string) error {
+	body, err := json.Marshal(struct {
+		Text string `json:"text"`
+	}{Text: message})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, ww.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	rsp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	// drain the body, so the connection can be reused
+	if _, err := io.Copy(io.Discard, rsp.Body); err != nil {
+		return err
+	}
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned unexpected status code %d", target, rsp.StatusCode)
+	}
+	return nil
+}
+
+// parseContacts splits the comma-separated contacts of a namespace annotation.
+func parseContacts(value string) []string {
+	contacts := make([]string, 0)
+	for contact := range strings.SplitSeq(value, ",") {
+		contact = strings.TrimSpace(contact)
+		if contact != "" {
+			contacts = append(contacts, contact)
+		}
+	}
+	return contacts
+}
+
+// groupWorkloads groups the pods by namespace and controller, both sorted by name.
+// Pods of ReplicaSets created by a Deployment are attributed to the Deployment.
+func groupWorkloads(pods []corev1.Pod) map[string][]Workload {
+	byNamespace := make(map[string]map[[2]string]*Workload)
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		kind, name := common.WorkloadOf(pod)
+		workloads, ok := byNamespace[pod.Namespace]
+		if !ok {
+			workloads = make(map[[2]string]*Workload)
+			byNamespace[pod.Namespace] = workloads
+		}
+		key := [2]string{kind, name}
+		workload, ok := workloads[key]
+		if !ok {
+			workload = &Workload{Kind: kind, Name: name}
+			workloads[key] = workload
+		}
+		workload.Pods = append(workload.Pods, pod.Name)
+	}
+	result := make(map[string][]Workload, len(byNamespace))
+	for namespace, workloads := range byNamespace {
+		sorted := make([]Workload, 0, len(workloads))
+		for _, workload := range workloads {
+			slices.Sort(workload.Pods)
+			sorted = append(sorted, *workload)
+		}
+		slices.SortFunc(sorted, func(a, b Workload) int {
+			return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Name, b.Name))
+		})
+		result[namespace] = sorted
+	}
+	return result
+}
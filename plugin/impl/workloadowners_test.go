@@ -0,0 +1,255 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sapcc/ucfgwrap"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sapcc/maintenance-controller/constants"
+	"github.com/sapcc/maintenance-controller/plugin"
+)
+
+var _ = Describe("The workloadOwners plugin", func() {
+
+	It("should parse its config", func() {
+		configStr := "slack:\n  token: token\nwebhook:\n  allowedPrefixes: [https://chat.example.com/]\n"
+		config, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		var base WorkloadOwners
+		plugin, err := base.New(&config)
+		Expect(err).To(Succeed())
+		Expect(plugin).To(Equal(&WorkloadOwners{
+			Annotation: constants.ContactAnnotationKey,
+			Message:    defaultWorkloadOwnersMessage,
+			Slack:      &WorkloadOwnersSlack{Token: "token"},
+			Webhook: &WorkloadOwnersWebhook{
+				AllowedPrefixes: []string{"https://chat.example.com/"},
+				Timeout:         10 * time.Second,
+			},
+		}))
+	})
+
+	It("should require a target", func() {
+		config, err := ucfgwrap.FromYAML([]byte("annotation: contact\n"))
+		Expect(err).To(Succeed())
+		var base WorkloadOwners
+		_, err = base.New(&config)
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("should require allowed webhook prefixes with a scheme and host", func() {
+		for _, prefix := range []string{"hooks.example.com/", "https://user@hooks.example.com/", "https://%zz"} {
+			config, err := ucfgwrap.FromYAML([]byte("webhook:\n  allowedPrefixes: [\"" + prefix + "\"]\n"))
+			Expect(err).To(Succeed())
+			var base WorkloadOwners
+			_, err = base.New(&config)
+			Expect(err).ToNot(Succeed(), prefix)
+		}
+	})
+
+	DescribeTable("should allow webhooks matching the scheme, host and path of a prefix",
+		func(target string, allowed bool) {
+			webhook := WorkloadOwnersWebhook{AllowedPrefixes: []string{"https://hooks.example.com/services/"}}
+			Expect(webhook.allows(target)).To(Equal(allowed))
+		},
+		Entry("matching url", "https://hooks.example.com/services/T123", true),
+		Entry("host in other case", "https://Hooks.Example.com/services/T123", true),
+		Entry("other path", "https://hooks.example.com/admin", false),
+		Entry("other scheme", "http://hooks.example.com/services/T123", false),
+		Entry("other port", "https://hooks.example.com:8443/services/T123", false),
+		Entry("host as subdomain", "https://hooks.example.com.attacker.net/services/T123", false),
+		Entry("host as user info", "https://hooks.example.com@evil/services/T123", false),
+		Entry("dot segments", "https://hooks.example.com/services/../admin", false),
+		Entry("no url", "slack:#team-a", false),
+	)
+
+	It("should group pods by namespace and workload", func() {
+		pods := []corev1.Pod{
+			makeOwnedPod("web-abc12-x", "team-a", "ReplicaSet", "web-abc12", "abc12"),
+			makeOwnedPod("web-abc12-y", "team-a", "ReplicaSet", "web-abc12", "abc12"),
+			makeOwnedPod("db-0", "team-a", "StatefulSet", "db", ""),
+			makeOwnedPod("standalone", "team-b", "", "", ""),
+		}
+		workloads := groupWorkloads(pods)
+		Expect(workloads).To(Equal(map[string][]Workload{
+			"team-a": {
+				{Kind: "Deployment", Name: "web", Pods: []string{"web-abc12-x", "web-abc12-y"}},
+				{Kind: "StatefulSet", Name: "db", Pods: []string{"db-0"}},
+			},
+			"team-b": {
+				{Kind: "Pod", Name: "standalone", Pods: []string{"standalone"}},
+			},
+		}))
+	})
+
+	It("should split contacts", func() {
+		Expect(parseContacts(" slack:#team-a, mailto:a@example.com ,,")).To(Equal([]string{"slack:#team-a", "mailto:a@example.com"}))
+		Expect(parseContacts("")).To(BeEmpty())
+	})
+
+	Context("with pods on the node", func() {
+		var slackServer, hookServer *httptest.Server
+		var slackChannels, hookTexts []string
+		var hookStatus int
+		var recorder *events.FakeRecorder
+		var params plugin.Parameters
+		var owners WorkloadOwners
+
+		BeforeEach(func() {
+			slackChannels = make([]string, 0)
+			hookTexts = make([]string, 0)
+			hookStatus = http.StatusNoContent
+			slackServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				Expect(r.ParseForm()).To(Succeed())
+				slackChannels = append(slackChannels, r.Form.Get("channel"))
+				w.Header().Set("Content-Type", "application/json")
+				_, err := w.Write([]byte(`{"ok":true,"channel":"C123","ts":"1700000000.000100"}`))
+				Expect(err).To(Succeed())
+			}))
+			hookServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				var body struct {
+					Text string `json:"text"`
+				}
+				Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+				hookTexts = append(hookTexts, body.Text)
+				w.WriteHeader(hookStatus)
+			}))
+			teamA := makeOwnedPod("web-abc12-x", "team-a", "ReplicaSet", "web-abc12", "abc12")
+			teamB := makeOwnedPod("db-0", "team-b", "StatefulSet", "db", "")
+			teamC := makeOwnedPod("job-1", "team-c", "Job", "job", "")
+			k8sClient := fake.NewClientBuilder().
+				WithObjects(
+					&teamA, &teamB, &teamC,
+					&corev1.Namespace{ObjectMeta: v1.ObjectMeta{Name: "team-a", Annotations: map[string]string{
+						constants.ContactAnnotationKey: "slack:#team-a, mailto:a@example.com",
+					}}},
+					&corev1.Namespace{ObjectMeta: v1.ObjectMeta{Name: "team-b", Annotations: map[string]string{
+						constants.ContactAnnotationKey: hookServer.URL + "/hook,http://internal.example.com/hook",
+					}}},
+					&corev1.Namespace{ObjectMeta: v1.ObjectMeta{Name: "team-c"}},
+				).
+				WithIndex(&corev1.Pod{}, "spec.nodeName", func(o client.Object) []string {
+					pod, ok := o.(*corev1.Pod)
+					if !ok {
+						return []string{}
+					}
+					return []string{pod.Spec.NodeName}
+				}).
+				Build()
+			recorder = events.NewFakeRecorder(10)
+			params = plugin.Parameters{
+				Client:   k8sClient,
+				Ctx:      context.Background(),
+				Log:      GinkgoLogr,
+				Node:     &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "targetnode"}},
+				Profile:  "profile",
+				State:    "in-maintenance",
+				Recorder: recorder,
+			}
+			owners = WorkloadOwners{
+				Annotation: constants.ContactAnnotationKey,
+				Message:    defaultWorkloadOwnersMessage,
+				Slack:      &WorkloadOwnersSlack{Token: "token"},
+				Webhook:    &WorkloadOwnersWebhook{AllowedPrefixes: []string{hookServer.URL}, Timeout: time.Second},
+			}
+			owners.SetTestURL(slackServer.URL + "/")
+		})
+
+		AfterEach(func() {
+			slackServer.Close()
+			hookServer.Close()
+		})
+
+		It("notifies the contacts of each namespace", func() {
+			Expect(owners.Notify(params)).To(Succeed())
+			// mail is not configured and the second webhook is not allowed
+			Expect(slackChannels).To(Equal([]string{"#team-a"}))
+			Expect(hookTexts).To(HaveLen(1))
+			Expect(hookTexts[0]).To(ContainSubstring("namespace team-b will be evicted"))
+			Expect(hookTexts[0]).To(ContainSubstring("- StatefulSet db: db-0"))
+		})
+
+		It("records an event in each namespace", func() {
+			Expect(owners.Notify(params)).To(Succeed())
+			var notes []string
+			for len(recorder.Events) > 0 {
+				event := <-recorder.Events
+				if strings.Contains(event, "NodeMaintenance") {
+					notes = append(notes, event)
+				}
+			}
+			Expect(notes).To(ConsistOf(
+				ContainSubstring("Deployment/web"),
+				ContainSubstring("StatefulSet/db"),
+				ContainSubstring("Job/job"),
+			))
+		})
+
+		It("records contacts, which can not be notified, without failing", func() {
+			hookStatus = http.StatusInternalServerError
+			Expect(owners.Notify(params)).To(Succeed())
+			Expect(slackChannels).To(Equal([]string{"#team-a"}))
+			Expect(hookTexts).To(HaveLen(1))
+			var failures, sent []string
+			for len(recorder.Events) > 0 {
+				event := <-recorder.Events
+				switch {
+				case strings.Contains(event, "NotificationFailed"):
+					failures = append(failures, event)
+				case strings.Contains(event, "NotificationSent"):
+					sent = append(sent, event)
+				}
+			}
+			Expect(failures).To(ConsistOf(ContainSubstring("namespace team-b")))
+			Expect(sent).To(ConsistOf(ContainSubstring("slack:#team-a")))
+		})
+
+		It("does not follow redirects of webhooks", func() {
+			redirectServer := httptest.NewServer(http.RedirectHandler(hookServer.URL, http.StatusTemporaryRedirect))
+			defer redirectServer.Close()
+			webhook := WorkloadOwnersWebhook{AllowedPrefixes: []string{redirectServer.URL}, Timeout: time.Second}
+			Expect(webhook.send(context.Background(), redirectServer.URL+"/hook", "text")).ToNot(Succeed())
+			Expect(hookTexts).To(BeEmpty())
+		})
+
+		It("fails if the message can not be rendered", func() {
+			owners.Message = "{{ .Missing }}"
+			Expect(owners.Notify(params)).ToNot(Succeed())
+		})
+	})
+
+})
+
+func makeOwnedPod(name, namespace, ownerKind, ownerName, hash string) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       corev1.PodSpec{NodeName: "targetnode"},
+	}
+	if hash != "" {
+		pod.Labels = map[string]string{"pod-template-hash": hash}
+	}
+	if ownerKind != "" {
+		pod.OwnerReferences = []v1.OwnerReference{{
+			APIVersion: "apps/v1", Kind: ownerKind, Name: ownerName, UID: "uid", Controller: ptr.To(true),
+		}}
+	}
+	return pod
+}
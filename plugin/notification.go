@@ -4,9 +4,11 @@
 package plugin
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -27,11 +29,27 @@ type Notifier interface {
 	ID() string
 }
 
+// StateChangeNotifier is implemented by notification plugins, which react to state changes of a profile
+// independently of their schedule, e.g. to resolve an incident once a profile is operational again.
+type StateChangeNotifier interface {
+	// NotifyStateChange is invoked once after a profile entered params.State coming from the previous state.
+	NotifyStateChange(params Parameters, previous string) error
+}
+
+// Flusher is implemented by notification plugins, which aggregate the notifications of many nodes
+// and need to send them independently of any node being notified, e.g. once a period passed.
+type Flusher interface {
+	// Flush is invoked periodically, only Ctx, Client and Log of the parameters are set.
+	Flush(params Parameters) error
+}
+
 // NotificationInstance represents a configured and named instance of a notification plugin.
 type NotificationInstance struct {
 	Plugin   Notifier
 	Schedule Scheduler
 	Name     string
+	// names of calendars, during which notifications are suppressed
+	Calendars []string
 }
 
 // NotificationChain represents a collection of multiple NotificationInstance that can be executed one after another.
@@ -42,6 +60,7 @@ type NotificationChain struct {
 // Execute invokes Notify on each NotificationInstance in the chain and aborts when a plugin returns an error.
 func (chain *NotificationChain) Execute(params Parameters) error {
 	for _, notifier := range chain.Plugins {
+		params.Instance = notifier.Name
 		err := notifier.Plugin.Notify(params)
 		if err != nil {
 			return &ChainError{
@@ -54,14 +73,74 @@ func (chain *NotificationChain) Execute(params Parameters) error {
 	return nil
 }
 
+// ExecuteStateChange invokes NotifyStateChange on each NotificationInstance in the chain, which implements
+// StateChangeNotifier. Failing instances do not keep the remaining ones from being invoked,
+// their errors are returned joined.
+func (chain *NotificationChain) ExecuteStateChange(params Parameters, previous string) error {
+	var errs []error
+	for _, notifier := range chain.Plugins {
+		stateNotifier, ok := notifier.Plugin.(StateChangeNotifier)
+		if !ok {
+			continue
+		}
+		params.Instance = notifier.Name
+		if err := stateNotifier.NotifyStateChange(params, previous); err != nil {
+			errs = append(errs, &ChainError{
+				Message: fmt.Sprintf("Notification instance %v failed to handle a state change", notifier.Name),
+				Err:     err,
+			})
+			continue
+		}
+		params.Log.Info("Notified instance about state change", "instance", notifier.Name, "state", params.State)
+	}
+	return errors.Join(errs...)
+}
+
+// templateFuncs are the helper functions available in all templates.
+var templateFuncs = template.FuncMap{
+	"now":     func() time.Time { return time.Now().UTC() },
+	"rfc3339": func(t time.Time) string { return t.Format(time.RFC3339) },
+	"unix":    func(t time.Time) int64 { return t.Unix() },
+	// returns 0 for values, which are not an integer, so missing counters start from scratch
+	"atoi": func(s string) int {
+		i, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return 0
+		}
+		return i
+	},
+	"add": func(a, b int) int { return a + b },
+	"default": func(fallback, s string) string {
+		if s == "" {
+			return fallback
+		}
+		return s
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+	"join":  func(sep string, values []string) string { return strings.Join(values, sep) },
+	// encodes a value as json, so it can be embedded into json bodies safely
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
 // Renders the given template string using the provided parameters.
+// Besides the builtin functions the helpers now, rfc3339, unix, atoi, add, default, lower, upper, trim, join and json are available.
 func RenderNotificationTemplate(templateStr string, params *Parameters) (string, error) {
-	templateObj, err := template.New("template").Parse(templateStr)
+	return RenderTemplate(templateStr, params)
+}
+
+// RenderTemplate renders the given template string using arbitrary data and the same helpers as RenderNotificationTemplate.
+func RenderTemplate(templateStr string, data any) (string, error) {
+	templateObj, err := template.New("template").Funcs(templateFuncs).Parse(templateStr)
 	if err != nil {
 		return "", err
 	}
 	var buf strings.Builder
-	err = templateObj.Execute(&buf, params)
+	err = templateObj.Execute(&buf, data)
 	if err != nil {
 		return "", err
 	}
@@ -128,15 +207,19 @@ func (np *NotifyPeriodic) ShouldNotify(params ShouldNotifyParams) bool {
 }
 
 // Notifies when the given instant passed on an allowed weekday.
+// Instant, weekdays and day boundaries are evaluated in the configured time zone.
 type NotifyScheduled struct {
 	Instant  time.Time
 	Weekdays []time.Weekday
+	// nil refers to UTC
+	Location *time.Location
 }
 
 func newNotifyScheduled(config *ucfgwrap.Config) (*NotifyScheduled, error) {
 	conf := struct {
 		Instant  string
 		Weekdays []string
+		Timezone string
 	}{}
 	err := config.Unpack(&conf)
 	if err != nil {
@@ -150,7 +233,11 @@ func newNotifyScheduled(config *ucfgwrap.Config) (*NotifyScheduled, error) {
 	if err != nil {
 		return nil, err
 	}
-	scheduled := &NotifyScheduled{Instant: instant}
+	location, err := common.LoadTimezone(conf.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	scheduled := &NotifyScheduled{Instant: instant, Location: location}
 	for _, weekdayStr := range conf.Weekdays {
 		weekday, err := common.WeekdayFromString(weekdayStr)
 		if err != nil {
@@ -163,7 +250,9 @@ func newNotifyScheduled(config *ucfgwrap.Config) (*NotifyScheduled, error) {
 
 func (ns *NotifyScheduled) ShouldNotify(params ShouldNotifyParams) bool {
 	current := params.Current
+	current.Time = common.InLocation(current.Time, ns.Location)
 	last := params.Last
+	last.Time = common.InLocation(last.Time, ns.Location)
 	log := params.Log
 	// check that a notification can be triggered on the current weekday
 	if !slices.Contains(ns.Weekdays, current.Time.Weekday()) {
@@ -183,7 +272,7 @@ func (ns *NotifyScheduled) ShouldNotify(params ShouldNotifyParams) bool {
 		return false
 	}
 	// ensure the notification triggers only once a day
-	if last.Time.Day() == current.Time.Day() {
+	if common.StartOfDay(last.Time).Equal(common.StartOfDay(current.Time)) {
 		if log.LogDetails {
 			log.Log.Info("NotifyScheduled: already triggered today")
 		}
@@ -104,6 +104,28 @@ var _ = Describe("The notification", func() {
 		Expect(result).To(Equal("def"))
 	})
 
+	It("should provide helper functions", func() {
+		result, err := RenderNotificationTemplate(`{{ "41" | atoi | add 1 }} {{ "x" | atoi }} {{ "" | default "none" | upper }}`, &Parameters{})
+		Expect(err).To(Succeed())
+		Expect(result).To(Equal("42 0 NONE"))
+		result, err = RenderNotificationTemplate("{{ now | rfc3339 }}", &Parameters{})
+		Expect(err).To(Succeed())
+		_, err = time.Parse(time.RFC3339, result)
+		Expect(err).To(Succeed())
+	})
+
+	It("should encode values as json", func() {
+		result, err := RenderNotificationTemplate(`{"text": {{ .State | json }}}`, &Parameters{State: "say \"hi\"\n"})
+		Expect(err).To(Succeed())
+		Expect(result).To(Equal(`{"text": "say \"hi\"\n"}`))
+	})
+
+	It("should render arbitrary data", func() {
+		result, err := RenderTemplate(`{{ .Nodes | join ", " }}`, struct{ Nodes []string }{Nodes: []string{"a", "b"}})
+		Expect(err).To(Succeed())
+		Expect(result).To(Equal("a, b"))
+	})
+
 })
 
 var _ = Describe("NotifyPeriodic", func() {
@@ -144,6 +166,68 @@ var _ = Describe("NotifyScheduled", func() {
 		Expect(ns.Weekdays).To(ContainElements(time.Friday, time.Saturday))
 	})
 
+	It("can parse its time zone", func() {
+		configStr := "instant: \"15:23\"\nweekdays: [\"fri\"]\ntimezone: Europe/Berlin\n"
+		conf, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		ns, err := newNotifyScheduled(&conf)
+		Expect(err).To(Succeed())
+		Expect(ns.Location.String()).To(Equal("Europe/Berlin"))
+	})
+
+	It("fails to parse unknown time zones", func() {
+		configStr := "instant: \"15:23\"\nweekdays: [\"fri\"]\ntimezone: Mars/Olympus\n"
+		conf, err := ucfgwrap.FromYAML([]byte(configStr))
+		Expect(err).To(Succeed())
+		_, err = newNotifyScheduled(&conf)
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("in New York", func() {
+		makeNewYorkSchedule := func() *NotifyScheduled {
+			location, err := time.LoadLocation("America/New_York")
+			Expect(err).To(Succeed())
+			schedule := makeSchedule()
+			schedule.Location = location
+			return schedule
+		}
+
+		It("should evaluate the instant in the time zone", func() {
+			// 11:00 in New York
+			currentDate := time.Date(2022, time.February, 21, 16, 0, 0, 0, time.UTC)
+			result := makeNewYorkSchedule().ShouldNotify(ShouldNotifyParams{
+				Current: NotificationData{State: "operational", Time: currentDate},
+				Last:    NotificationData{State: "operational", Time: currentDate.Add(-25 * time.Hour)},
+				Log:     SchedLog,
+			})
+			Expect(result).To(BeFalse())
+		})
+
+		It("should evaluate weekdays and days in the time zone", func() {
+			// monday 21:00 in New York, but tuesday in UTC
+			currentDate := time.Date(2022, time.February, 22, 2, 0, 0, 0, time.UTC)
+			result := makeNewYorkSchedule().ShouldNotify(ShouldNotifyParams{
+				Current: NotificationData{State: "operational", Time: currentDate},
+				// monday 01:00 in UTC, but sunday in New York
+				Last: NotificationData{State: "operational", Time: currentDate.Add(-25 * time.Hour)},
+				Log:  SchedLog,
+			})
+			Expect(result).To(BeTrue())
+		})
+
+		It("should not trigger more than once a day in the time zone", func() {
+			// monday 21:00 in New York, but tuesday in UTC
+			currentDate := time.Date(2022, time.February, 22, 2, 0, 0, 0, time.UTC)
+			result := makeNewYorkSchedule().ShouldNotify(ShouldNotifyParams{
+				Current: NotificationData{State: "operational", Time: currentDate},
+				// monday 13:00 in New York
+				Last: NotificationData{State: "operational", Time: currentDate.Add(-8 * time.Hour)},
+				Log:  SchedLog,
+			})
+			Expect(result).To(BeFalse())
+		})
+	})
+
 	Context("on tuesdays", func() {
 		It("should not trigger before 12:00", func() {
 			currentDate := time.Date(2022, time.February, 22, 11, 0, 0, 0, time.UTC)
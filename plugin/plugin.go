@@ -18,6 +18,8 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sapcc/maintenance-controller/common"
 )
 
 // AndSeparator is a string that is used to combine two plugin instance within a config string.
@@ -95,6 +97,22 @@ type Parameters struct {
 	Log            logr.Logger
 	Recorder       events.EventRecorder
 	LastTransition time.Time
+	// progress of the current or last drain of the node, which is persisted in the node data
+	Drain *common.DrainProgress
+	// named calendars, which can be referenced by plugins
+	Calendars *common.Calendars
+	// results of the check chains of the profile from the last reconciliation
+	LastCheckResults []CheckChainResult
+	// references to messages, which notification plugins edit in place, persisted in the node data
+	Messages map[string]string
+	// name of the notification instance being invoked, empty for other plugins
+	Instance string
+}
+
+// CalendarReferrer is implemented by plugins, which reference named calendars.
+// The references are validated when loading the configuration.
+type CalendarReferrer interface {
+	CalendarRefs() []string
 }
 
 // Registry is a central storage for all plugins and their instances.
@@ -249,6 +267,13 @@ func (r *Registry) loadNotificationInstance(config *ucfgwrap.Config, descriptor
 		return errors.New("a notification instance does not have a schedule assigned")
 	}
 	scheduleConf := config.Wrap(descriptor.Schedule.Config)
+	// calendars can be referenced by any schedule
+	calendarConf := struct {
+		Calendars []string `config:"calendars"`
+	}{}
+	if err := scheduleConf.Unpack(&calendarConf); err != nil {
+		return err
+	}
 	switch strings.ToLower(descriptor.Schedule.Type) {
 	case "periodic":
 		schedule, err = newNotifyPeriodic(&scheduleConf)
@@ -263,9 +288,10 @@ func (r *Registry) loadNotificationInstance(config *ucfgwrap.Config, descriptor
 		return err
 	}
 	r.NotificationInstances[instanceName] = NotificationInstance{
-		Name:     instanceName,
-		Plugin:   plugin,
-		Schedule: schedule,
+		Name:      instanceName,
+		Plugin:    plugin,
+		Schedule:  schedule,
+		Calendars: calendarConf.Calendars,
 	}
 	return nil
 }
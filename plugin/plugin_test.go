@@ -123,6 +123,27 @@ var _ = Describe("Registry", func() {
 				Expect(instance.Name).To(Equal("test"))
 			})
 
+			It("loads calendar references of notification schedules", func() {
+				var configStr = `notify:
+                - type: someNotificationPlugin
+                  name: test
+                  schedule:
+                    type: periodic
+                    config:
+                      interval: 5m
+                      calendars: [holidays]
+                `
+				registry := NewRegistry()
+				registry.NotificationPlugins["someNotificationPlugin"] = &successfulNotification{}
+				config, err := yaml.NewConfig([]byte(configStr))
+				Expect(err).To(Succeed())
+				var descriptor InstancesDescriptor
+				Expect(config.Unpack(&descriptor)).To(Succeed())
+				err = registry.LoadInstances(emptyConfig, &descriptor)
+				Expect(err).To(Succeed())
+				Expect(registry.NotificationInstances["test"].Calendars).To(Equal([]string{"holidays"}))
+			})
+
 			It("loads scheduled notification plugin instances", func() {
 				var configStr = `notify:
                 - type: someNotificationPlugin
@@ -39,6 +39,10 @@ func (s *inMaintenance) Enter(params plugin.Parameters, data *Data) error {
 	return nil
 }
 
+func (s *inMaintenance) NotifyStateChange(params plugin.Parameters, data *Data) error {
+	return notifyStateChangeDefault(params, data, &s.chains)
+}
+
 func (s *inMaintenance) Notify(params plugin.Parameters, data *Data) error {
 	return notifyDefault(params, data, &s.chains.Notification)
 }
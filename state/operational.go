@@ -28,6 +28,10 @@ func (s *operational) Enter(params plugin.Parameters, data *Data) error {
 	return s.chains.Enter.Execute(params)
 }
 
+func (s *operational) NotifyStateChange(params plugin.Parameters, data *Data) error {
+	return notifyStateChangeDefault(params, data, &s.chains)
+}
+
 func (s *operational) Notify(params plugin.Parameters, data *Data) error {
 	return notifyDefault(params, data, &s.chains.Notification)
 }
@@ -13,6 +13,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 
+	"github.com/sapcc/maintenance-controller/common"
 	"github.com/sapcc/maintenance-controller/constants"
 	"github.com/sapcc/maintenance-controller/metrics"
 	"github.com/sapcc/maintenance-controller/plugin"
@@ -82,17 +83,20 @@ type ProfileResult struct {
 }
 
 type NodeInfo struct {
-	Node     string            `json:"node"`
-	Profiles []ProfileResult   `json:"profiles"`
-	Labels   map[string]string `json:"labels"`
-	Updated  time.Time         `json:"updated"`
+	Node     string                `json:"node"`
+	Profiles []ProfileResult       `json:"profiles"`
+	Labels   map[string]string     `json:"labels"`
+	Drain    *common.DrainProgress `json:"drain,omitempty"`
+	Updated  time.Time             `json:"updated"`
 }
 
 // PluginChains is a struct containing a plugin chain of each plugin type.
 type PluginChains struct {
 	Enter        plugin.TriggerChain
 	Notification plugin.NotificationChain
-	Transitions  []Transition
+	// notification instances of all states of the profile, which implement plugin.StateChangeNotifier
+	StateChange plugin.NotificationChain
+	Transitions []Transition
 }
 
 // Profile contains its name and attached plugin chains.
@@ -111,6 +115,10 @@ type Data struct {
 	Profiles map[string]*ProfileData
 	// Maps a notification instance name to the last time it was triggered.
 	Notifications map[string]time.Time
+	// Progress of the current or last drain.
+	Drain *common.DrainProgress `json:",omitempty"`
+	// References to messages, which notification plugins edit in place.
+	Messages map[string]string `json:",omitempty"`
 }
 
 func ParseData(dataStr string) (Data, error) {
@@ -136,6 +144,8 @@ type NodeState interface {
 	// Enter is executed when a node enters a new state.
 	// Its not executed when a profile gets freshly attached.
 	Enter(params plugin.Parameters, data *Data) error
+	// NotifyStateChange informs notification instances about the state change once Enter succeeded.
+	NotifyStateChange(params plugin.Parameters, data *Data) error
 	// Notify executes the notification chain if required
 	Notify(params plugin.Parameters, data *Data) error
 	// Trigger executes the trigger chain
@@ -199,6 +209,15 @@ func Apply(state NodeState, node *v1.Node, data *Data, params plugin.Parameters)
 		if err != nil {
 			return handleTransitionError(err, fmt.Sprintf("Failed to enter state %s", state.Label()))
 		}
+		// the state has been entered already, so failures are not retried by entering again,
+		// which would also keep unreachable notification targets from blocking the node
+		if err := state.NotifyStateChange(params, data); err != nil {
+			params.Log.Error(err, "failed to notify about state change",
+				"state", params.State, "profile", params.Profile, "node", node.Name)
+			recorder.Eventf(node, nil, v1.EventTypeWarning,
+				"StateChangeNotificationFailed", "ChangeMaintenanceState",
+				"Failed to notify about entering %v for profile %v: %v", state.Label(), params.Profile, err)
+		}
 	}
 	// invoke notifications and check for transition
 	err := state.Notify(params, data)
@@ -227,6 +246,16 @@ func Apply(state NodeState, node *v1.Node, data *Data, params plugin.Parameters)
 	return result, nil
 }
 
+// notifyStateChangeDefault is a default NodeState.NotifyStateChange implementation that informs
+// the state change notification instances.
+func notifyStateChangeDefault(params plugin.Parameters, data *Data, chains *PluginChains) error {
+	previous := ""
+	if profileData, ok := data.Profiles[params.Profile]; ok && profileData != nil {
+		previous = string(profileData.Previous)
+	}
+	return chains.StateChange.ExecuteStateChange(params, previous)
+}
+
 // transitionDefault is a default NodeState.Transition implementation that checks
 // each specified transition in order and returns the next state. If len(trans)
 // is 0, the current state is returned.
@@ -310,6 +339,18 @@ func notifyDefault(params plugin.Parameters, data *Data, chain *plugin.Notificat
 			}
 			continue
 		}
+		blackout, blackedOut, err := params.Calendars.ActiveAt(params.Ctx, params.Client, notifyInstance.Calendars, now)
+		if err != nil {
+			return err
+		}
+		if blackedOut {
+			if params.LogDetails {
+				params.Log.Info("Notification instance is suppressed by a calendar", "node", params.Node.Name,
+					"instance", notifyInstance.Name, "calendar", blackout.Calendar, "summary", blackout.Summary)
+			}
+			continue
+		}
+		params.Instance = notifyInstance.Name
 		if err := notifyInstance.Plugin.Notify(params); err != nil {
 			return err
 		}
@@ -16,6 +16,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/events"
 
+	"github.com/sapcc/maintenance-controller/common"
 	"github.com/sapcc/maintenance-controller/plugin"
 )
 
@@ -78,6 +79,19 @@ func (n *mockNotification) ID() string {
 	return "mock"
 }
 
+type mockStateChangeNotification struct {
+	mockNotification
+	Changes []string
+}
+
+func (n *mockStateChangeNotification) NotifyStateChange(params plugin.Parameters, previous string) error {
+	n.Changes = append(n.Changes, previous+"->"+params.State)
+	if n.Fail {
+		return errors.New("mocked state change failure")
+	}
+	return nil
+}
+
 func mockNotificationChain(instanceCount int) (plugin.NotificationChain, *mockNotification) {
 	if instanceCount < 0 {
 		panic("mockNotificationChain requires at least zero instances.")
@@ -206,6 +220,33 @@ var _ = Describe("NotifyDefault", func() {
 		Expect(notification.Invoked).To(Equal(3))
 	})
 
+	It("should not execute notification instances during a period of a referenced calendar", func() {
+		today := time.Now().UTC()
+		calendars, err := common.NewCalendars([]common.CalendarDefinition{{
+			Name: "freeze",
+			Ranges: []common.CalendarRange{{
+				Start: today.AddDate(0, 0, -1).Format("2006-01-02"),
+				End:   today.AddDate(0, 0, 1).Format("2006-01-02"),
+			}},
+		}})
+		Expect(err).To(Succeed())
+		chain, notification := mockNotificationChain(2)
+		chain.Plugins[0].Calendars = []string{"freeze"}
+		lastNotification := time.Date(2000, time.April, 13, 2, 3, 4, 9, time.UTC)
+		data := Data{
+			Profiles: makeProfileMap(InMaintenance, InMaintenance),
+			Notifications: map[string]time.Time{
+				"mock0": lastNotification,
+				"mock1": lastNotification,
+			},
+		}
+		params := plugin.Parameters{Log: GinkgoLogr, Profile: "p", Calendars: calendars}
+		err = notifyDefault(params, &data, &chain)
+		Expect(err).To(Succeed())
+		Expect(notification.Invoked).To(Equal(1))
+		Expect(data.Notifications["mock0"]).To(Equal(lastNotification))
+	})
+
 })
 
 var _ = Describe("Apply", func() {
@@ -298,6 +339,57 @@ var _ = Describe("Apply", func() {
 		Expect(enter.Invoked).To(Equal(1))
 	})
 
+	It("informs state change notifiers when entering a state", func() {
+		notifier := &mockStateChangeNotification{}
+		nodeState := operational{
+			label: Operational,
+			chains: PluginChains{
+				StateChange: plugin.NotificationChain{
+					Plugins: []plugin.NotificationInstance{{Plugin: notifier, Name: "incident"}},
+				},
+			},
+		}
+		data := Data{Profiles: map[string]*ProfileData{"profile": {Current: Operational, Previous: InMaintenance}}}
+		_, err := Apply(&nodeState, &v1.Node{}, &data, buildParams())
+		Expect(err).To(Succeed())
+		Expect(notifier.Changes).To(Equal([]string{"in-maintenance->operational"}))
+
+		data.Profiles["profile"].Previous = Operational
+		_, err = Apply(&nodeState, &v1.Node{}, &data, buildParams())
+		Expect(err).To(Succeed())
+		Expect(notifier.Changes).To(HaveLen(1))
+		Expect(notifier.Invoked).To(BeZero())
+	})
+
+	It("does not fail entering a state if a state change notifier fails", func() {
+		chain, enter := mockTriggerChain()
+		failing := &mockStateChangeNotification{mockNotification: mockNotification{Fail: true}}
+		notifier := &mockStateChangeNotification{}
+		nodeState := operational{
+			label: Operational,
+			chains: PluginChains{
+				Enter: chain,
+				StateChange: plugin.NotificationChain{
+					Plugins: []plugin.NotificationInstance{
+						{Plugin: failing, Name: "failing"},
+						{Plugin: notifier, Name: "incident"},
+					},
+				},
+			},
+		}
+		data := Data{Profiles: map[string]*ProfileData{"profile": {Current: Operational, Previous: InMaintenance}}}
+		params := buildParams()
+		result, err := Apply(&nodeState, &v1.Node{}, &data, params)
+		Expect(err).To(Succeed())
+		Expect(result.Error).To(BeEmpty())
+		Expect(enter.Invoked).To(Equal(1))
+		Expect(failing.Changes).To(HaveLen(1))
+		Expect(notifier.Changes).To(Equal([]string{"in-maintenance->operational"}))
+		recorder, ok := params.Recorder.(*events.FakeRecorder)
+		Expect(ok).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring("StateChangeNotificationFailed")))
+	})
+
 })
 
 var _ = Describe("ParseData", func() {